@@ -1,15 +1,36 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
 	"log"
-	"net"
-	nethttp "net/http"
+	"os"
 	"time"
+	// Embeds a fallback copy of the timezone database, used to load launchpad timezones when the system's zoneinfo is
+	// missing, as it is in the Docker image.
+	_ "time/tzdata"
 
+	"github.com/petherin/spacetickets/internal/domains/bookings"
 	"github.com/petherin/spacetickets/internal/infrastructure/config"
 	"github.com/petherin/spacetickets/internal/infrastructure/database"
 	"github.com/petherin/spacetickets/internal/infrastructure/http"
+	"github.com/petherin/spacetickets/internal/infrastructure/notifications"
+	"github.com/petherin/spacetickets/internal/infrastructure/payments"
 	"github.com/petherin/spacetickets/internal/interfaces/api"
+	"github.com/petherin/spacetickets/internal/interfaces/workers"
+)
+
+const (
+	// holdSweepInterval is how often expired seat holds are released.
+	holdSweepInterval = time.Minute
+	// waitlistPromoteInterval is how often free seats are offered to waitlisted customers.
+	waitlistPromoteInterval = time.Minute
+	// paymentSettleInterval is how often the payments of bookings left pending are settled.
+	paymentSettleInterval = 5 * time.Minute
+	// reconcileInterval is how often upcoming bookings are re-checked against SpaceX's launches.
+	reconcileInterval = 15 * time.Minute
+	// launchPadSyncInterval is how often the launchpad catalogue is synced with SpaceX's launchpads.
+	launchPadSyncInterval = 24 * time.Hour
 )
 
 func main() {
@@ -18,30 +39,74 @@ func main() {
 		log.Fatalf("failed to get config: %s\n", err)
 	}
 
-	repo, err := database.New(cfg)
+	repo, err := database.Open(cfg)
 	if err != nil {
 		log.Fatalf("failed to create booking repo client: %s\n", err)
 	}
 	defer repo.Close()
 
-	client := &nethttp.Client{
-		Timeout: time.Duration(cfg.HTTPTimeout) * time.Second,
-		Transport: &nethttp.Transport{
-			MaxIdleConns:      cfg.MaxIdleConns,
-			MaxConnsPerHost:   cfg.MaxConnsPerHost,
-			IdleConnTimeout:   time.Duration(cfg.IdleConnTimeoutSecs) * time.Second,
-			DisableKeepAlives: cfg.DisableKeepAlives,
-			DialContext: (&net.Dialer{
-				Timeout:   time.Duration(cfg.DialerTimeoutSecs) * time.Second,
-				KeepAlive: time.Duration(cfg.DialerKeepAliveSecs) * time.Second,
-			}).DialContext,
-			TLSHandshakeTimeout: time.Duration(cfg.TLSHandshakeTimeoutSecs) * time.Second,
-		},
+	client := http.NewClient(cfg)
+
+	gateway, err := payments.Open(cfg, client)
+	if err != nil {
+		log.Fatalf("failed to create payment gateway: %s\n", err)
+	}
+
+	notifier, err := notifications.Open(cfg)
+	if err != nil {
+		log.Fatalf("failed to create notifier: %s\n", err)
+	}
+
+	refundPolicy, err := bookings.ParseRefundPolicy(cfg.RefundPolicy)
+	if err != nil {
+		log.Fatalf("failed to parse refund policy: %s\n", err)
+	}
+
+	checkInOpens, err := bookings.ParseCheckInOpens(cfg.CheckInOpens)
+	if err != nil {
+		log.Fatalf("failed to parse check-in opening time: %s\n", err)
+	}
+
+	handlers := api.NewBookingHandlers(repo, client, cfg.SpaceXAPIEndpoint, gateway, refundPolicy)
+	handlers.CheckInOpens = checkInOpens
+	handlers.Notifier = notifier
+
+	handlers.BoardingPassKey, err = boardingPassKey(cfg.BoardingPassKey)
+	if err != nil {
+		log.Fatalf("failed to parse boarding pass key: %s\n", err)
+	}
+
+	if len(cfg.SpaceXSnapshotPath) > 0 {
+		imported, err := importLaunchSnapshot(repo, cfg.SpaceXSnapshotPath)
+		if err != nil {
+			log.Fatalf("failed to import SpaceX launch snapshot: %s\n", err)
+		}
+		log.Printf("Imported %d SpaceX launches from %s, the SpaceX API won't be used\n", imported, cfg.SpaceXSnapshotPath)
+
+		handlers.Launches = bookings.LaunchSnapshot{Launches: repo}
 	}
 
-	handlers := api.NewBookingHandlers(repo, client, cfg.SpaceXAPIEndpoint)
+	admin := api.NewAdminHandlers(repo, gateway, refundPolicy)
+	admin.CheckInOpens = checkInOpens
+	admin.Notifier = notifier
+	if len(cfg.AdminAPIKey) == 0 {
+		log.Println("ADMIN_API_KEY not set, admin API disabled")
+	}
 
-	svr := http.New(":8080", handlers)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go workers.NewHoldSweeper(repo, holdSweepInterval).Run(ctx)
+	go workers.NewWaitlistPromoter(&handlers, waitlistPromoteInterval).Run(ctx)
+	go workers.NewPaymentSettler(&handlers, paymentSettleInterval).Run(ctx)
+	reconciler := workers.NewReconciler(repo, &handlers, reconcileInterval)
+	reconciler.Notifier = notifier
+	go reconciler.Run(ctx)
+	if len(cfg.SpaceXSnapshotPath) == 0 {
+		go workers.NewLaunchPadSyncer(repo, &handlers, launchPadSyncInterval).Run(ctx)
+	}
+
+	svr := http.New(":8080", handlers, admin, cfg.AdminAPIKey)
 
 	log.Printf("API running at http://localhost%s/api/v1\n", ":8080")
 	log.Printf("Swagger server running at http://localhost%s\n", ":8081")
@@ -49,3 +114,31 @@ func main() {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+// boardingPassKey returns the key to sign boarding passes with. Without a configured key a temporary one is generated,
+// so boarding passes issued before a restart stop verifying.
+func boardingPassKey(seed string) (ed25519.PrivateKey, error) {
+	if len(seed) > 0 {
+		return bookings.ParseBoardingPassKey(seed)
+	}
+
+	log.Println("BOARDING_PASS_KEY not set, boarding passes are signed with a temporary key")
+	_, key, err := ed25519.GenerateKey(nil)
+	return key, err
+}
+
+// importLaunchSnapshot replaces the stored snapshot of SpaceX's launches with the SpaceX launches JSON export at path.
+func importLaunchSnapshot(booker bookings.Booker, path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	launches, err := bookings.ParseLaunchSnapshot(file)
+	if err != nil {
+		return 0, err
+	}
+
+	return bookings.ImportLaunchSnapshot(booker, launches)
+}
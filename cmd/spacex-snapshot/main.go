@@ -0,0 +1,89 @@
+// Command spacex-snapshot downloads SpaceX's launches from the SpaceX API's /v4/launches endpoint and saves them to a
+// file, so the API can check bookings against the snapshot with SPACEX_SNAPSHOT_PATH when it can't reach SpaceX.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const defaultEndpoint = "https://api.spacexdata.com"
+
+func main() {
+	endpoint := flag.String("endpoint", os.Getenv("SPACEX_API_ENDPOINT"), "SpaceX API endpoint, defaults to SPACEX_API_ENDPOINT or "+defaultEndpoint)
+	out := flag.String("out", "spacex_launches.json", "file to save the snapshot to")
+	timeout := flag.Duration("timeout", time.Minute, "how long to wait for the SpaceX API")
+	flag.Parse()
+
+	if len(*endpoint) == 0 {
+		*endpoint = defaultEndpoint
+	}
+
+	body, err := download(&http.Client{Timeout: *timeout}, *endpoint)
+	if err != nil {
+		log.Fatalf("failed to download SpaceX launches: %s\n", err)
+	}
+
+	// Check the snapshot can be imported before replacing the old one.
+	launches, err := bookings.ParseLaunchSnapshot(bytes.NewReader(body))
+	if err != nil {
+		log.Fatalf("failed to parse SpaceX launches: %s\n", err)
+	}
+
+	if err := save(*out, body); err != nil {
+		log.Fatalf("failed to save snapshot: %s\n", err)
+	}
+
+	log.Printf("Saved %d SpaceX launches to %s\n", len(launches), *out)
+}
+
+// download gets every launch from the SpaceX API.
+func download(client *http.Client, endpoint string) ([]byte, error) {
+	fullURL, err := url.JoinPath(endpoint, "/v4/launches")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(fullURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from SpaceX API: %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// save writes the snapshot to a temporary file next to path and renames it, so a failed write doesn't leave a broken
+// snapshot behind.
+func save(path string, body []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
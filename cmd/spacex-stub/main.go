@@ -0,0 +1,52 @@
+// Command spacex-stub runs a local stand-in for the SpaceX API, so the API can be run and tested against SpaceX
+// without a network connection, and against a SpaceX that's slow, down or broken.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/spacexstub"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	fixture := flag.String("fixture", "", "SpaceX launches JSON export to serve, defaults to the built-in fixture")
+	fault := flag.String("fault", spacexstub.FaultNone, "fault to start with: none, latency, error, malformed or timeout")
+	latency := flag.Duration("latency", 2*time.Second, "how long the latency fault waits before answering")
+	flag.Parse()
+
+	launches, err := loadFixture(*fixture)
+	if err != nil {
+		log.Fatalf("failed to load fixture: %s\n", err)
+	}
+
+	stub := spacexstub.New(launches)
+	if err := stub.SetFault(*fault, *latency); err != nil {
+		log.Fatalf("failed to set fault: %s\n", err)
+	}
+
+	log.Printf("SpaceX stub serving %d launches at http://localhost%s with fault %s\n", len(launches), *addr, *fault)
+	if err := http.ListenAndServe(*addr, stub.Handler()); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
+
+// loadFixture returns the launches in the file at path, or the built-in fixture if path is empty.
+func loadFixture(path string) ([]bookings.ExternalLaunch, error) {
+	if len(path) == 0 {
+		return spacexstub.DefaultFixture()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return bookings.ParseLaunchSnapshot(file)
+}
@@ -0,0 +1,33 @@
+package bookings
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrAccessTokenInvalid is returned when a request for a booking doesn't have the booking's access token, so it can't
+// be shown to come from the customer who made it.
+var ErrAccessTokenInvalid = errors.New("access token not recognised")
+
+// NewAccessToken returns a new random access token for a booking.
+func NewAccessToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("could not generate access token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// CheckAccessToken returns ErrAccessTokenInvalid unless token is the booking's access token. Bookings without an access
+// token can't be accessed with any token.
+func (b Booking) CheckAccessToken(token string) error {
+	if len(b.AccessToken) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(b.AccessToken)) != 1 {
+		return fmt.Errorf("booking %s: %w", b.Id, ErrAccessTokenInvalid)
+	}
+
+	return nil
+}
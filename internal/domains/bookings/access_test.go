@@ -0,0 +1,43 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBooking_CheckAccessToken(t *testing.T) {
+	token, err := NewAccessToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other, err := NewAccessToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other == token {
+		t.Fatalf("access tokens should be random, got %q twice", token)
+	}
+
+	tests := []struct {
+		name        string
+		accessToken string
+		token       string
+		wantErr     error
+	}{
+		{name: "1. Booking's access token", accessToken: token, token: token},
+		{name: "2. Another booking's access token", accessToken: token, token: other, wantErr: ErrAccessTokenInvalid},
+		{name: "3. No access token", accessToken: token, wantErr: ErrAccessTokenInvalid},
+		{name: "4. Booking without an access token", wantErr: ErrAccessTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := Booking{Id: "uuid-1", AccessToken: tt.accessToken}
+
+			if err := booking.CheckAccessToken(tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("wrong error, got %v want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
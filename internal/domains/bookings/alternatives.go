@@ -0,0 +1,46 @@
+package bookings
+
+import "time"
+
+const (
+	// MaxAlternativeDates is the most other dates suggested for a flight that can't be booked.
+	MaxAlternativeDates = 3
+	// AlternativeSearchDays is how many days either side of the requested date are searched for other dates.
+	AlternativeSearchDays = 14
+)
+
+// Alternative is a flight that could be booked instead of one that was rejected, either from the same launchpad on
+// another date or from another launchpad on the same date.
+type Alternative struct {
+	LaunchPadId   string     `json:"launch_pad_id"`
+	LaunchPadName string     `json:"launch_pad_name"`
+	DestinationId string     `json:"destination_id"`
+	LaunchDate    time.Time  `json:"launch_date"`
+	DepartureAt   *time.Time `json:"departure_at"`
+}
+
+// NearbyDates returns the dates up to days either side of the date, nearest first, leaving out any before today. Of
+// two dates the same distance away, the earlier one comes first.
+func NearbyDates(date, today time.Time, days int) []time.Time {
+	dates := make([]time.Time, 0, 2*days)
+	for i := 1; i <= days; i++ {
+		for _, nearby := range []time.Time{date.AddDate(0, 0, -i), date.AddDate(0, 0, i)} {
+			if !nearby.Before(today) {
+				dates = append(dates, nearby)
+			}
+		}
+	}
+
+	return dates
+}
+
+// NewAlternative returns the alternative for a booking on the flight from the launchpad.
+func NewAlternative(launchPad LaunchPad, booking Booking) Alternative {
+	return Alternative{
+		LaunchPadId:   launchPad.Id,
+		LaunchPadName: launchPad.FullName,
+		DestinationId: booking.DestinationId,
+		LaunchDate:    booking.LaunchDate,
+		DepartureAt:   booking.DepartureAt,
+	}
+}
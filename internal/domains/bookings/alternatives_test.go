@@ -0,0 +1,35 @@
+package bookings
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNearbyDates(t *testing.T) {
+	date, _ := time.Parse(time.DateOnly, "2024-01-08")
+
+	tests := []struct {
+		name  string
+		today string
+		want  []string
+	}{
+		{name: "1. Nearest first", today: "2024-01-01", want: []string{"2024-01-07", "2024-01-09", "2024-01-06", "2024-01-10"}},
+		{name: "2. Dates before today left out", today: "2024-01-07", want: []string{"2024-01-07", "2024-01-09", "2024-01-10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			today, _ := time.Parse(time.DateOnly, tt.today)
+
+			var got []string
+			for _, nearby := range NearbyDates(date, today, 2) {
+				got = append(got, nearby.Format(time.DateOnly))
+			}
+
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("wrong dates, got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
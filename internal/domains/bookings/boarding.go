@@ -0,0 +1,193 @@
+package bookings
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrBoardingPassInvalid is returned for a boarding pass that's malformed or whose signature doesn't verify, e.g.
+	// because it's been forged or changed.
+	ErrBoardingPassInvalid = errors.New("boarding pass is invalid")
+	// ErrBoardingPassOutdated is returned for a boarding pass that no longer matches its booking, e.g. because the
+	// passenger's name was corrected after it was issued.
+	ErrBoardingPassOutdated = errors.New("boarding pass does not match the booking")
+	// ErrNotBoardable is returned when a booking can't board, e.g. because it's been cancelled or its flight has
+	// already left.
+	ErrNotBoardable = errors.New("booking cannot board")
+	// ErrNotCheckedIn is returned when a confirmed booking tries to board before the passenger has checked in.
+	ErrNotCheckedIn = errors.New("passenger has not checked in")
+)
+
+// BoardingPass is the passenger and flight a booking's boarding pass is for. It's signed with the API's Ed25519 key, so
+// gate staff can check a pass offline with the public key, without looking the booking up.
+type BoardingPass struct {
+	BookingId     string    `json:"booking_id"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	LaunchDate    time.Time `json:"launch_date"`
+}
+
+// boardingPassClaims is a boarding pass as it's signed, with short field names to keep the QR code small.
+type boardingPassClaims struct {
+	BookingId     string `json:"b"`
+	FirstName     string `json:"f"`
+	LastName      string `json:"l"`
+	LaunchPadId   string `json:"p"`
+	DestinationId string `json:"d"`
+	LaunchDate    string `json:"t"`
+}
+
+// NewBoardingPass returns the boarding pass for a confirmed booking, or one that has checked in or boarded. It returns
+// ErrNotBoardable for any other booking.
+func NewBoardingPass(booking Booking) (BoardingPass, error) {
+	if booking.Deleted || !slices.Contains([]string{StatusConfirmed, StatusCheckedIn, StatusBoarded}, booking.Status) {
+		return BoardingPass{}, fmt.Errorf("booking %s is %s: %w", booking.Id, booking.Status, ErrNotBoardable)
+	}
+
+	return BoardingPass{
+		BookingId:     booking.Id,
+		FirstName:     booking.FirstName,
+		LastName:      booking.LastName,
+		LaunchPadId:   booking.LaunchPadId,
+		DestinationId: booking.DestinationId,
+		LaunchDate:    booking.LaunchDate,
+	}, nil
+}
+
+// Matches returns true if the boarding pass is for the booking's passenger and flight as they are now.
+func (p BoardingPass) Matches(booking Booking) bool {
+	return p.BookingId == booking.Id && p.FirstName == booking.FirstName && p.LastName == booking.LastName &&
+		p.LaunchPadId == booking.LaunchPadId && p.DestinationId == booking.DestinationId &&
+		p.LaunchDate.Format(time.DateOnly) == booking.LaunchDate.Format(time.DateOnly)
+}
+
+// Sign returns the boarding pass as a token to put in its QR code: the pass's claims, a dot, then their signature, both
+// base64url encoded.
+func (p BoardingPass) Sign(key ed25519.PrivateKey) string {
+	claims, _ := json.Marshal(boardingPassClaims{
+		BookingId:     p.BookingId,
+		FirstName:     p.FirstName,
+		LastName:      p.LastName,
+		LaunchPadId:   p.LaunchPadId,
+		DestinationId: p.DestinationId,
+		LaunchDate:    p.LaunchDate.Format(time.DateOnly),
+	})
+
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	signature := ed25519.Sign(key, []byte(payload))
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// ParseBoardingPass returns the boarding pass in a token made by Sign, or ErrBoardingPassInvalid if it's malformed or
+// wasn't signed by the key's private key.
+func ParseBoardingPass(token string, key ed25519.PublicKey) (BoardingPass, error) {
+	payload, encodedSignature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return BoardingPass{}, ErrBoardingPassInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !ed25519.Verify(key, []byte(payload), signature) {
+		return BoardingPass{}, ErrBoardingPassInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return BoardingPass{}, ErrBoardingPassInvalid
+	}
+
+	var claims boardingPassClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return BoardingPass{}, ErrBoardingPassInvalid
+	}
+
+	launchDate, err := time.Parse(time.DateOnly, claims.LaunchDate)
+	if err != nil {
+		return BoardingPass{}, ErrBoardingPassInvalid
+	}
+
+	return BoardingPass{
+		BookingId:     claims.BookingId,
+		FirstName:     claims.FirstName,
+		LastName:      claims.LastName,
+		LaunchPadId:   claims.LaunchPadId,
+		DestinationId: claims.DestinationId,
+		LaunchDate:    launchDate,
+	}, nil
+}
+
+// ParseBoardingPassKey returns the Ed25519 private key for a base64 encoded 32 byte seed.
+func ParseBoardingPassKey(seed string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil {
+		return nil, fmt.Errorf("boarding pass key is not base64: %w", err)
+	}
+
+	if len(data) != ed25519.SeedSize {
+		return nil, fmt.Errorf("boarding pass key is %d bytes, it must be %d", len(data), ed25519.SeedSize)
+	}
+
+	return ed25519.NewKeyFromSeed(data), nil
+}
+
+// Board checks a scanned boarding pass token against its booking and records the passenger as boarded. The pass must
+// be signed with the key and still match its booking, the passenger must have checked in, and it must be scanned
+// between check-in opening and departure. Scanning a pass again returns the booking as it is, with the time it first
+// boarded, so a scan can be safely retried, and it returns false if the passenger had already boarded.
+func Board(booker Booker, key ed25519.PublicKey, token string, checkInOpens time.Duration, now time.Time) (*Booking, bool, error) {
+	pass, err := ParseBoardingPass(token, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	booking, err := booker.Get(pass.BookingId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !pass.Matches(*booking) {
+		return nil, false, fmt.Errorf("booking %s: %w", booking.Id, ErrBoardingPassOutdated)
+	}
+
+	switch {
+	case booking.Deleted:
+		return nil, false, fmt.Errorf("booking %s is %s: %w", booking.Id, booking.Status, ErrNotBoardable)
+	case booking.Status == StatusBoarded:
+		return booking, false, nil
+	case booking.Status == StatusConfirmed:
+		return nil, false, fmt.Errorf("booking %s: %w", booking.Id, ErrNotCheckedIn)
+	case booking.Status != StatusCheckedIn:
+		return nil, false, fmt.Errorf("booking %s is %s: %w", booking.Id, booking.Status, ErrNotBoardable)
+	}
+
+	if err := CheckMove(*booking, StatusBoarded, checkInOpens, now); err != nil {
+		return nil, false, err
+	}
+
+	boarded, err := booker.MoveStatus(booking.Id, StatusCheckedIn, StatusBoarded, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	booking, err = booker.Get(booking.Id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The booking may have been boarded by another scan, or cancelled, since it was checked.
+	if boarded == 0 && booking.Status != StatusBoarded {
+		return nil, false, fmt.Errorf("booking %s is %s: %w", booking.Id, booking.Status, ErrNotBoardable)
+	}
+
+	return booking, boarded > 0, nil
+}
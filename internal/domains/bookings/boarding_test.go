@@ -0,0 +1,106 @@
+package bookings
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBoardingPass_Sign(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherPublic, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	booking := Booking{
+		Id:            "b0d4e7b8-6a3f-4c55-9d0e-2f1a7c9b3e11",
+		Customer:      Customer{FirstName: "Zoë", LastName: "O'Brien", Gender: "Female"},
+		LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+		DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+		LaunchDate:    time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC),
+		Status:        StatusConfirmed,
+	}
+
+	pass, err := NewBoardingPass(booking)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token := pass.Sign(private)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	tampered, _ := base64.RawURLEncoding.DecodeString(payload)
+	tampered = []byte(strings.Replace(string(tampered), "2030-01-14", "2030-01-21", 1))
+
+	tests := []struct {
+		name    string
+		token   string
+		key     ed25519.PublicKey
+		wantErr error
+	}{
+		{name: "1. Signed with the key", token: token, key: public},
+		{name: "2. Signed with another key", token: token, key: otherPublic, wantErr: ErrBoardingPassInvalid},
+		{name: "3. Flight changed", token: base64.RawURLEncoding.EncodeToString(tampered) + "." + signature, key: public, wantErr: ErrBoardingPassInvalid},
+		{name: "4. No signature", token: payload, key: public, wantErr: ErrBoardingPassInvalid},
+		{name: "5. Not a boarding pass", token: "hello.world", key: public, wantErr: ErrBoardingPassInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBoardingPass(tt.token, tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("wrong error, got %v want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got != pass || !got.Matches(booking) {
+				t.Errorf("wrong boarding pass, got %+v want %+v", got, pass)
+			}
+		})
+	}
+
+	renamed := booking
+	renamed.LastName = "Brien"
+	if pass.Matches(renamed) {
+		t.Errorf("boarding pass matches a booking whose passenger has changed")
+	}
+
+	booking.Status = StatusPending
+	if _, err := NewBoardingPass(booking); !errors.Is(err, ErrNotBoardable) {
+		t.Errorf("wrong error for a pending booking, got %v want %v", err, ErrNotBoardable)
+	}
+}
+
+func TestParseBoardingPassKey(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+
+	tests := []struct {
+		name    string
+		seed    string
+		wantErr bool
+	}{
+		{name: "1. 32 byte seed", seed: base64.StdEncoding.EncodeToString(seed)},
+		{name: "2. Too short", seed: base64.StdEncoding.EncodeToString(seed[:16]), wantErr: true},
+		{name: "3. Not base64", seed: "not a key!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseBoardingPassKey(tt.seed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got %v, want error %t", err, tt.wantErr)
+			}
+			if err == nil && !key.Equal(ed25519.NewKeyFromSeed(seed)) {
+				t.Errorf("wrong key")
+			}
+		})
+	}
+}
@@ -2,10 +2,18 @@ package bookings
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	// ErrLaunchScheduleInvalid is returned when the launchpad doesn't fly to the destination on the requested day.
+	ErrLaunchScheduleInvalid = errors.New("launchpad does not fly to the destination on the requested day")
+	// ErrFlightFull is returned when every seat on the requested flight has been booked.
+	ErrFlightFull = errors.New("no seats remaining on this flight")
+)
+
 // Booking represents a flight booking.
 type Booking struct {
 	Id string `json:"id"`
@@ -13,25 +21,70 @@ type Booking struct {
 	LaunchPadId   string    `json:"launch_pad_id"`
 	DestinationId string    `json:"destination_id"`
 	LaunchDate    time.Time `json:"launch_date"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	// DepartureAt is when the flight's launch window opens, worked out from LaunchDate in the launchpad's timezone.
+	DepartureAt *time.Time `json:"departure_at"`
+	// EstimatedArrival is the date the flight is expected to arrive, LaunchDate plus the travel time.
+	EstimatedArrival *time.Time `json:"estimated_arrival"`
+	// Direction is DirectionOutbound or DirectionReturn. A return flight's LaunchPadId is the launchpad it lands at,
+	// and OutboundBookingId is the booking for the flight out.
+	Direction         string `json:"direction"`
+	OutboundBookingId string `json:"outbound_booking_id"`
+	// GroupId is shared by the bookings made together in one group booking.
+	GroupId string `json:"group_id"`
+	// Price is what the customer was quoted for the seat, in Currency's minor unit. It's nil if the flight didn't have
+	// a fare when it was booked. PromoCode is the promo code used to get the price, if any.
+	Price     *int64 `json:"price"`
+	Currency  string `json:"currency"`
+	PromoCode string `json:"promo_code"`
+	// Status is StatusPending until the booking has been paid for, then StatusPaid and StatusConfirmed, and then moves
+	// through check-in and boarding to StatusFlown. PaymentId is the gateway's id for the payment, shared by the
+	// bookings in a group booking.
+	Status    string `json:"status"`
+	PaymentId string `json:"payment_id"`
+	// CancellationReason is why a cancelled booking was cancelled, and RefundAmount is how much of its price was
+	// refunded. RefundAmount is nil if the booking wasn't paid for. RefundId is the gateway's id for the refund.
+	CancellationReason string `json:"cancellation_reason"`
+	RefundAmount       *int64 `json:"refund_amount"`
+	RefundId           string `json:"refund_id"`
+	// ReviewRequired is set when a timetable change means the booking's flight may no longer run.
+	ReviewRequired bool   `json:"review_required"`
+	ReviewReason   string `json:"review_reason"`
+	// CheckedInAt is when the passenger checked in, and BoardedAt is when their boarding pass was scanned at the gate.
+	// They're nil until the passenger checks in and boards.
+	CheckedInAt *time.Time `json:"checked_in_at"`
+	BoardedAt   *time.Time `json:"boarded_at"`
+	// AccessToken is a secret only given to the customer who made the booking, as anyone can see its id. They need it
+	// to check in and get their boarding pass. A return flight shares its outbound booking's access token.
+	AccessToken string    `json:"access_token,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Deleted is set once the booking has been deleted. Deleted bookings are still returned by Get.
+	Deleted bool `json:"-"`
 }
 
-// Customer represents a customer wishing to book a flight.
+// Customer represents a customer wishing to book a flight. Email and Phone are optional, see Customer.Validate, and
+// customers without an email address aren't sent notifications. They're left out of the JSON when they're empty, so
+// the public list of bookings can leave them out.
 type Customer struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	Gender    string    `json:"gender"`
 	Birthday  time.Time `json:"birthday"`
+	Email     string    `json:"email,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
 }
 
 // LaunchPad represents a launch pad name and id, and maps to the corresponding launch id that SpaceX use.
 type LaunchPad struct {
-	Id                string    `json:"id"`
-	FullName          string    `json:"full_name"`
-	SpaceXLaunchPadId string    `json:"spacex_launchpad_id"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	Id                string `json:"id"`
+	FullName          string `json:"full_name"`
+	SpaceXLaunchPadId string `json:"spacex_launchpad_id"`
+	SeatCapacity      int    `json:"seat_capacity"`
+	// Timezone is the IANA timezone the launchpad is in, launch dates and windows are in its local time.
+	Timezone  string    `json:"timezone"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // SpaceXLaunches lists how many launches are found.
@@ -66,12 +119,46 @@ type Options struct {
 }
 
 // Booker defines the methods an object needs to implement to list, create, delete and validate bookings.
+// The schedule and seat counts are per direction, so an outbound flight and a return flight landing at the same
+// launchpad on the same day are different flights.
+// SetStatus leaves the booking's payment id as it is when paymentId is empty. ClaimCancellation moves a booking that
+// isn't deleted from the status from to StatusCancelling, recording the reason, and affects 0 rows if its status isn't
+// from. Cancel marks a booking that isn't deleted as cancelled and deleted, recording the reason and refund.
+// ClearDisruption moves a disrupted booking that isn't deleted back to the status and clears its review flag, and
+// affects 0 rows if it isn't disrupted.
 type Booker interface {
 	GetAll() ([]Booking, error)
+	Get(bookingId string) (*Booking, error)
+	GetReturn(outboundBookingId string) (*Booking, error)
 	Create(booking Booking) (*Booking, error)
 	Delete(bookingId string) (int64, error)
 	GetLaunchPad(id string) (*LaunchPad, error)
-	IsLaunchScheduleValid(launchPadId, dayOfWeek, destinationId string) (bool, error)
+	FindScheduledFlight(direction, launchPadId, destinationId string, launchDate time.Time) (*ScheduleEntry, error)
+	IsLaunchScheduleValid(direction, launchPadId, destinationId string, launchDate time.Time) (bool, error)
+	CountBookings(direction, launchPadId string, launchDate time.Time) (int, error)
+	GetUpcoming(launchPadId string, from time.Time) ([]Booking, error)
+	FlagForReview(bookingId, reason string) (int64, error)
+	SetStatus(bookingId, status, paymentId string) (int64, error)
+	ClaimCancellation(bookingId, from, reason string) (int64, error)
+	Cancel(bookingId, reason string, refundAmount *int64, refundId string) (int64, error)
+	ClearDisruption(bookingId, status string) (int64, error)
+	Catalogue
+	Holds
+	Pricing
+	Eligibility
+	Waitlist
+	ExternalLaunches
+	Lifecycle
+	UnitOfWork
+}
+
+// UnitOfWork runs a group of Booker calls as a single unit.
+//
+// InTransaction calls fn with a Booker whose calls all happen in one transaction, which is committed if fn returns nil
+// and rolled back otherwise. Inside the transaction GetLaunchPad locks the launchpad, so bookings for the same
+// launchpad are made one at a time. Calling InTransaction on the Booker passed to fn runs in the same transaction.
+type UnitOfWork interface {
+	InTransaction(fn func(tx Booker) error) error
 }
 
 // UnmarshalJSON unmarshals booking JSON so that dates have the proper time.Time format.
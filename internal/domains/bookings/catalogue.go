@@ -0,0 +1,239 @@
+package bookings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the requested booking, launchpad, destination, schedule entry, exception or travel
+	// time doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrLaunchPadRetired is returned when booking a flight from a launchpad that has been retired.
+	ErrLaunchPadRetired = errors.New("launchpad has been retired")
+	// ErrDestinationRetired is returned when booking a flight to a destination that has been retired.
+	ErrDestinationRetired = errors.New("destination has been retired")
+	// ErrScheduleConflict is returned when a launchpad would fly to two destinations on the same day of the week
+	// at the same time.
+	ErrScheduleConflict = errors.New("launchpad already flies to a destination on that day of the week while this entry is in effect")
+)
+
+// ValidationError describes why a launchpad, destination or schedule entry can't be saved.
+type ValidationError struct {
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return e.Reason
+}
+
+var spaceXLaunchPadIdRegexp = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// Destination represents somewhere flights can be booked to.
+type Destination struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// TravelDays is how long flights to and from the destination take, unless a TravelTime overrides it.
+	TravelDays int       `json:"travel_days"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ScheduleEntry says a launchpad flies to a destination every week on the given day, from EffectiveFrom to
+// EffectiveTo inclusive. A nil EffectiveFrom or EffectiveTo leaves that end of the period open. Empty window times
+// default to the launchpad's whole local day. A return entry is a flight from the destination on the given day that
+// lands at the launchpad.
+type ScheduleEntry struct {
+	Id            string     `json:"id"`
+	LaunchPadId   string     `json:"launch_pad_id"`
+	DayOfWeek     string     `json:"day_of_week"`
+	DestinationId string     `json:"destination_id"`
+	Direction     string     `json:"direction"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	// WindowOpens and WindowCloses are the launch window, as HH:MM in the launchpad's timezone.
+	WindowOpens  string    `json:"window_opens"`
+	WindowCloses string    `json:"window_closes"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON unmarshals schedule entry JSON, parsing the effective dates as YYYY-MM-DD.
+// Dates missing from the JSON are left as they were, and a null date clears it.
+func (e *ScheduleEntry) UnmarshalJSON(data []byte) error {
+	type Alias ScheduleEntry
+	aux := &struct {
+		EffectiveFrom json.RawMessage `json:"effective_from"`
+		EffectiveTo   json.RawMessage `json:"effective_to"`
+		*Alias
+	}{
+		Alias: (*Alias)(e),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if e.EffectiveFrom, err = unmarshalOptionalDate(aux.EffectiveFrom, e.EffectiveFrom); err != nil {
+		return err
+	}
+
+	if e.EffectiveTo, err = unmarshalOptionalDate(aux.EffectiveTo, e.EffectiveTo); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// unmarshalOptionalDate returns current if the date wasn't in the JSON, nil if it was null, and the parsed date
+// otherwise.
+func unmarshalOptionalDate(data json.RawMessage, current *time.Time) (*time.Time, error) {
+	if len(data) == 0 {
+		return current, nil
+	}
+
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	date, err := parseDate(*value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
+
+// InEffect returns true if the entry is in force on the given date.
+func (e ScheduleEntry) InEffect(date time.Time) bool {
+	return (e.EffectiveFrom == nil || !date.Before(*e.EffectiveFrom)) && (e.EffectiveTo == nil || !date.After(*e.EffectiveTo))
+}
+
+// overlaps returns true if there are any dates on which both entries are in force.
+func (e ScheduleEntry) overlaps(other ScheduleEntry) bool {
+	startsBeforeOtherEnds := e.EffectiveFrom == nil || other.EffectiveTo == nil || !e.EffectiveFrom.After(*other.EffectiveTo)
+	otherStartsBeforeEnd := other.EffectiveFrom == nil || e.EffectiveTo == nil || !other.EffectiveFrom.After(*e.EffectiveTo)
+
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
+
+// Catalogue defines the methods an object needs to implement to manage launchpads, destinations and the schedule.
+// Retiring a launchpad or destination keeps it, and the bookings made for it, but stops new bookings being made.
+// GetScheduleExceptions returns the exceptions covering the date, or every exception if the date is zero.
+// GetTravelDays returns how long flights between the launchpad and destination take, using the launchpad's travel time
+// override if there is one.
+type Catalogue interface {
+	GetLaunchPads() ([]LaunchPad, error)
+	CreateLaunchPad(launchPad LaunchPad) (*LaunchPad, error)
+	UpdateLaunchPad(launchPad LaunchPad) (*LaunchPad, error)
+	RetireLaunchPad(id string) (int64, error)
+	GetDestinations() ([]Destination, error)
+	GetDestination(id string) (*Destination, error)
+	CreateDestination(destination Destination) (*Destination, error)
+	UpdateDestination(destination Destination) (*Destination, error)
+	RetireDestination(id string) (int64, error)
+	GetSchedule(launchPadId string) ([]ScheduleEntry, error)
+	CreateScheduleEntry(entry ScheduleEntry) (*ScheduleEntry, error)
+	UpdateScheduleEntry(entry ScheduleEntry) (*ScheduleEntry, error)
+	DeleteScheduleEntry(id string) (int64, error)
+	GetScheduleExceptions(date time.Time) ([]ScheduleException, error)
+	GetScheduleException(id string) (*ScheduleException, error)
+	CreateScheduleException(exception ScheduleException) (*ScheduleException, error)
+	UpdateScheduleException(exception ScheduleException) (*ScheduleException, error)
+	DeleteScheduleException(id string) (int64, error)
+	GetTravelTimes(launchPadId string) ([]TravelTime, error)
+	GetTravelDays(launchPadId, destinationId string) (int, error)
+	SetTravelTime(travelTime TravelTime) (*TravelTime, error)
+	DeleteTravelTime(launchPadId, destinationId string) (int64, error)
+}
+
+// Validate checks the launchpad has a name, a well-formed SpaceX launchpad id, a seat capacity and an IANA timezone.
+func (l LaunchPad) Validate() error {
+	if len(strings.TrimSpace(l.FullName)) == 0 {
+		return ValidationError{Reason: "full_name is required"}
+	}
+
+	if !spaceXLaunchPadIdRegexp.MatchString(l.SpaceXLaunchPadId) {
+		return ValidationError{Reason: "spacex_launchpad_id must be 24 lowercase hexadecimal characters"}
+	}
+
+	if l.SeatCapacity < 1 {
+		return ValidationError{Reason: "seat_capacity must be at least 1"}
+	}
+
+	if len(l.Timezone) == 0 {
+		return ValidationError{Reason: "timezone is required"}
+	}
+
+	if _, err := time.LoadLocation(l.Timezone); err != nil {
+		return ValidationError{Reason: fmt.Sprintf("unrecognised timezone %q", l.Timezone)}
+	}
+
+	return nil
+}
+
+// Validate checks the destination has a name and its travel time isn't negative.
+func (d Destination) Validate() error {
+	if len(strings.TrimSpace(d.Name)) == 0 {
+		return ValidationError{Reason: "name is required"}
+	}
+
+	if d.TravelDays < 0 {
+		return ValidationError{Reason: "travel_days must not be negative"}
+	}
+
+	return nil
+}
+
+// Validate checks the entry names a launchpad, destination, direction and day of the week, that its launch window and
+// effective dates are in order, and that it doesn't clash with an entry in the launchpad's existing schedule in the
+// same direction that's in force at the same time.
+func (e ScheduleEntry) Validate(existing []ScheduleEntry) error {
+	if len(e.LaunchPadId) == 0 || len(e.DestinationId) == 0 {
+		return ValidationError{Reason: "launch_pad_id and destination_id are required"}
+	}
+
+	if e.Direction != DirectionOutbound && e.Direction != DirectionReturn {
+		return ValidationError{Reason: fmt.Sprintf("direction must be %s or %s", DirectionOutbound, DirectionReturn)}
+	}
+
+	if !isWeekday(e.DayOfWeek) {
+		return ValidationError{Reason: fmt.Sprintf("unrecognised day_of_week %q", e.DayOfWeek)}
+	}
+
+	if err := validateWindow(e.Window()); err != nil {
+		return err
+	}
+
+	if e.EffectiveFrom != nil && e.EffectiveTo != nil && e.EffectiveTo.Before(*e.EffectiveFrom) {
+		return ValidationError{Reason: "effective_to must not be before effective_from"}
+	}
+
+	for _, other := range existing {
+		if other.Id != e.Id && other.LaunchPadId == e.LaunchPadId && other.DayOfWeek == e.DayOfWeek &&
+			other.Direction == e.Direction && e.overlaps(other) {
+			return ErrScheduleConflict
+		}
+	}
+
+	return nil
+}
+
+func isWeekday(day string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if d.String() == day {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package bookings
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrInvalidEmail is returned when a customer's email address isn't a plain address such as ian@example.com.
+	ErrInvalidEmail = errors.New("email address is not valid")
+	// ErrInvalidPhone is returned when a customer's phone number isn't in international format, e.g. +44 20 7946 0958.
+	ErrInvalidPhone = errors.New("phone number is not valid")
+)
+
+// phonePattern matches an E.164 phone number, a + then the country code and number, up to 15 digits in all.
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Validate checks the customer's contact details. Both are optional, but an email address must be a plain address,
+// without a display name, and a phone number must be in international format. Phone numbers can be written with spaces
+// or hyphens between the digits.
+func (c Customer) Validate() error {
+	if len(c.Email) > 0 {
+		address, err := mail.ParseAddress(c.Email)
+		if err != nil || address.Address != c.Email {
+			return ErrInvalidEmail
+		}
+	}
+
+	if len(c.Phone) > 0 && !phonePattern.MatchString(strings.NewReplacer(" ", "", "-", "").Replace(c.Phone)) {
+		return ErrInvalidPhone
+	}
+
+	return nil
+}
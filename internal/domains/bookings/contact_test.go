@@ -0,0 +1,34 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCustomer_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		phone   string
+		wantErr error
+	}{
+		{name: "1. No contact details"},
+		{name: "2. Email and phone", email: "ian@example.com", phone: "+44 20 7946 0958"},
+		{name: "3. Phone with hyphens", phone: "+1-202-555-0143"},
+		{name: "4. Email without a domain", email: "ian", wantErr: ErrInvalidEmail},
+		{name: "5. Email with a display name", email: "Ian <ian@example.com>", wantErr: ErrInvalidEmail},
+		{name: "6. Phone without a country code", phone: "020 7946 0958", wantErr: ErrInvalidPhone},
+		{name: "7. Phone with letters", phone: "+44 CALL ME", wantErr: ErrInvalidPhone},
+		{name: "8. Phone too long", phone: "+1234567890123456", wantErr: ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := Customer{FirstName: "Ian", LastName: "Thomson", Email: tt.email, Phone: tt.phone}
+
+			if err := customer.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("wrong error, got %v want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The kinds of eligibility rule.
+const (
+	// EligibilityMinAge rejects passengers younger than the rule's value on the launch date.
+	EligibilityMinAge = "min_age"
+	// EligibilityMaxAge rejects passengers older than the rule's value on the launch date.
+	EligibilityMaxAge = "max_age"
+	// EligibilityMaxTripsPerYear rejects passengers who already have the rule's value of trips launching in the same
+	// calendar year.
+	EligibilityMaxTripsPerYear = "max_trips_per_year"
+)
+
+// The reason codes a passenger is rejected with when they aren't eligible to fly.
+const (
+	// IneligibleTooYoung passengers are younger than a minimum age rule allows.
+	IneligibleTooYoung = "too_young"
+	// IneligibleTooOld passengers are older than a maximum age rule allows.
+	IneligibleTooOld = "too_old"
+	// IneligibleTooManyTrips passengers have already taken as many trips this year as a rule allows.
+	IneligibleTooManyTrips = "too_many_trips"
+)
+
+// ErrIneligible is returned when a passenger isn't eligible to fly, wrapped in an EligibilityError that says why.
+var ErrIneligible = errors.New("passenger is not eligible for this flight")
+
+// EligibilityError is returned when a passenger breaks an eligibility rule. Code is one of the reason codes, e.g.
+// IneligibleTooYoung, and Reason explains it to the customer.
+type EligibilityError struct {
+	Code   string
+	Reason string
+	Rule   EligibilityRule
+}
+
+func (e EligibilityError) Error() string {
+	return e.Reason
+}
+
+// Is makes errors.Is(err, ErrIneligible) true for every EligibilityError.
+func (e EligibilityError) Is(target error) bool {
+	return target == ErrIneligible
+}
+
+// EligibilityRule limits who can fly, by their age on the launch date or how many trips they take a year. An empty
+// DestinationId applies the rule to every destination, otherwise it only applies to flights to that destination.
+// Reason is why the rule exists, e.g. "no minors on the long flight to Pluto".
+type EligibilityRule struct {
+	Id            string    `json:"id"`
+	Kind          string    `json:"kind"`
+	DestinationId string    `json:"destination_id"`
+	Value         int       `json:"value"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate checks the rule has a recognised kind and a positive value.
+func (r EligibilityRule) Validate() error {
+	switch r.Kind {
+	case EligibilityMinAge, EligibilityMaxAge, EligibilityMaxTripsPerYear:
+	default:
+		return ValidationError{Reason: fmt.Sprintf("unrecognised kind %q, use %s, %s or %s", r.Kind, EligibilityMinAge,
+			EligibilityMaxAge, EligibilityMaxTripsPerYear)}
+	}
+
+	if r.Value <= 0 {
+		return ValidationError{Reason: "value must be greater than 0"}
+	}
+
+	return nil
+}
+
+// Applies returns true if the rule applies to flights to the destination.
+func (r EligibilityRule) Applies(destinationId string) bool {
+	return len(r.DestinationId) == 0 || r.DestinationId == destinationId
+}
+
+// reject returns the EligibilityError for a passenger who broke the rule, adding the rule's reason to the explanation.
+func (r EligibilityRule) reject(code, explanation string) EligibilityError {
+	if len(r.Reason) > 0 {
+		explanation += " (" + r.Reason + ")"
+	}
+
+	return EligibilityError{Code: code, Reason: explanation, Rule: r}
+}
+
+// Eligibility defines the methods an object needs to implement to manage eligibility rules and count passengers' trips.
+// CountTrips counts the outbound bookings that aren't deleted for the customer, matched by name and birthday, launching
+// on or after from and before to.
+type Eligibility interface {
+	GetEligibilityRules() ([]EligibilityRule, error)
+	CreateEligibilityRule(rule EligibilityRule) (*EligibilityRule, error)
+	DeleteEligibilityRule(id string) (int64, error)
+	CountTrips(customer Customer, from, to time.Time) (int, error)
+}
+
+// CheckEligibility checks the booking's passenger can fly under every rule that applies to its destination, returning an
+// EligibilityError for the first rule they break. Ages are worked out on the launch date, and trips are the
+// passenger's outbound bookings launching in the launch date's calendar year.
+func CheckEligibility(booker Booker, rules []EligibilityRule, booking Booking) error {
+	age := AgeOn(booking.Birthday, booking.LaunchDate)
+
+	for _, rule := range rules {
+		if !rule.Applies(booking.DestinationId) {
+			continue
+		}
+
+		switch rule.Kind {
+		case EligibilityMinAge:
+			if age < rule.Value {
+				return rule.reject(IneligibleTooYoung, fmt.Sprintf("passengers must be at least %d on the launch date", rule.Value))
+			}
+		case EligibilityMaxAge:
+			if age > rule.Value {
+				return rule.reject(IneligibleTooOld, fmt.Sprintf("passengers must be %d or younger on the launch date", rule.Value))
+			}
+		case EligibilityMaxTripsPerYear:
+			from := time.Date(booking.LaunchDate.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+			trips, err := booker.CountTrips(booking.Customer, from, from.AddDate(1, 0, 0))
+			if err != nil {
+				return err
+			}
+
+			if trips >= rule.Value {
+				return rule.reject(IneligibleTooManyTrips, fmt.Sprintf("passengers can take at most %d trips a year", rule.Value))
+			}
+		}
+	}
+
+	return nil
+}
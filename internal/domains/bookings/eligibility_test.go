@@ -0,0 +1,128 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckEligibility(t *testing.T) {
+	const (
+		plutoId = "fbd40165-03c7-47a5-be72-c79f81ebbf67"
+		moonId  = "466fc378-14eb-4ed9-8bec-d29abe54c5a9"
+	)
+
+	launchDate := time.Date(2030, 6, 3, 0, 0, 0, 0, time.UTC)
+	rules := []EligibilityRule{
+		{Id: "rule-1", Kind: EligibilityMinAge, Value: 2, Reason: "infants are too young for launch"},
+		{Id: "rule-2", Kind: EligibilityMaxAge, Value: 90},
+		{Id: "rule-3", Kind: EligibilityMinAge, DestinationId: plutoId, Value: 18, Reason: "no minors on the long flight to Pluto"},
+	}
+
+	tests := []struct {
+		name          string
+		birthday      time.Time
+		destinationId string
+		wantCode      string
+		wantReason    string
+	}{
+		{
+			name:          "1. Adult to the Moon",
+			birthday:      time.Date(1980, 4, 12, 0, 0, 0, 0, time.UTC),
+			destinationId: moonId,
+		},
+		{
+			name:          "2. Newborn to the Moon",
+			birthday:      time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC),
+			destinationId: moonId,
+			wantCode:      IneligibleTooYoung,
+			wantReason:    "passengers must be at least 2 on the launch date (infants are too young for launch)",
+		},
+		{
+			name:          "3. Turns 2 on the launch date",
+			birthday:      time.Date(2028, 6, 3, 0, 0, 0, 0, time.UTC),
+			destinationId: moonId,
+		},
+		{
+			name:          "4. Turns 91 on the launch date",
+			birthday:      time.Date(1939, 6, 3, 0, 0, 0, 0, time.UTC),
+			destinationId: moonId,
+			wantCode:      IneligibleTooOld,
+			wantReason:    "passengers must be 90 or younger on the launch date",
+		},
+		{
+			name:          "5. Child to the Moon",
+			birthday:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			destinationId: moonId,
+		},
+		{
+			name:          "6. Child to Pluto",
+			birthday:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			destinationId: plutoId,
+			wantCode:      IneligibleTooYoung,
+			wantReason:    "passengers must be at least 18 on the launch date (no minors on the long flight to Pluto)",
+		},
+		{
+			name:          "7. Turns 18 on the way to Pluto",
+			birthday:      time.Date(2012, 6, 4, 0, 0, 0, 0, time.UTC),
+			destinationId: plutoId,
+			wantCode:      IneligibleTooYoung,
+			wantReason:    "passengers must be at least 18 on the launch date (no minors on the long flight to Pluto)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := Booking{Customer: Customer{Birthday: tt.birthday}, DestinationId: tt.destinationId, LaunchDate: launchDate}
+
+			// None of the rules count trips, so there's no need for a Booker.
+			err := CheckEligibility(nil, rules, booking)
+			if len(tt.wantCode) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var ineligible EligibilityError
+			if !errors.As(err, &ineligible) || !errors.Is(err, ErrIneligible) {
+				t.Fatalf("wrong error, got %v want an EligibilityError", err)
+			}
+
+			if ineligible.Code != tt.wantCode {
+				t.Errorf("wrong reason code, got %s want %s", ineligible.Code, tt.wantCode)
+			}
+
+			if ineligible.Reason != tt.wantReason {
+				t.Errorf("wrong reason, got %q want %q", ineligible.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEligibilityRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    EligibilityRule
+		wantErr bool
+	}{
+		{name: "1. Minimum age", rule: EligibilityRule{Kind: EligibilityMinAge, Value: 18}},
+		{name: "2. Trips a year", rule: EligibilityRule{Kind: EligibilityMaxTripsPerYear, Value: 3}},
+		{name: "3. Unrecognised kind", rule: EligibilityRule{Kind: "height", Value: 150}, wantErr: true},
+		{name: "4. Negative value", rule: EligibilityRule{Kind: EligibilityMaxAge, Value: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got %v", err)
+			}
+
+			var validation ValidationError
+			if err != nil && !errors.As(err, &validation) {
+				t.Errorf("wrong error type, got %T want ValidationError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package bookings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The kinds of schedule exception.
+const (
+	// ExceptionExtraFlight adds a flight from a launchpad to a destination, or back, that isn't in the weekly schedule.
+	ExceptionExtraFlight = "extra_flight"
+	// ExceptionCancelledFlight cancels a launchpad's flight, or only its flight to one destination.
+	ExceptionCancelledFlight = "cancelled_flight"
+	// ExceptionLaunchPadClosed stops any flights leaving a launchpad, or landing at it, e.g. while it's closed for maintenance.
+	ExceptionLaunchPadClosed = "launchpad_closed"
+	// ExceptionDestinationUnavailable stops any flights going to a destination, or leaving it, e.g. outside its orbital window.
+	ExceptionDestinationUnavailable = "destination_unavailable"
+)
+
+var (
+	// ErrFlightCancelled is returned when a scheduled flight has been cancelled on the requested day.
+	ErrFlightCancelled = errors.New("flight has been cancelled on the requested day")
+	// ErrLaunchPadClosed is returned when the launchpad is closed on the requested day.
+	ErrLaunchPadClosed = errors.New("launchpad is closed on the requested day")
+	// ErrDestinationUnavailable is returned when the destination can't be flown to on the requested day.
+	ErrDestinationUnavailable = errors.New("destination is unavailable on the requested day")
+)
+
+// ScheduleException overrides the weekly schedule on every day from StartDate to EndDate inclusive.
+// Which of LaunchPadId and DestinationId are needed depends on the Kind. Direction is the flights it applies to,
+// outbound flights leaving the launchpad or return flights landing at it, so e.g. an extra flight out to a destination
+// doesn't add a flight back, and closing a launchpad to launches doesn't stop passengers landing there.
+type ScheduleException struct {
+	Id            string    `json:"id"`
+	Kind          string    `json:"kind"`
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	Direction     string    `json:"direction"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON unmarshals schedule exception JSON, parsing the start and end dates as YYYY-MM-DD.
+// Dates missing from the JSON are left as they were, and a missing end date defaults to the start date.
+func (e *ScheduleException) UnmarshalJSON(data []byte) error {
+	type Alias ScheduleException
+	aux := &struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		*Alias
+	}{
+		Alias: (*Alias)(e),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.StartDate) > 0 {
+		startDate, err := parseDate(aux.StartDate)
+		if err != nil {
+			return err
+		}
+		e.StartDate = startDate
+	}
+
+	if len(aux.EndDate) > 0 {
+		endDate, err := parseDate(aux.EndDate)
+		if err != nil {
+			return err
+		}
+		e.EndDate = endDate
+	}
+
+	if e.EndDate.IsZero() {
+		e.EndDate = e.StartDate
+	}
+
+	return nil
+}
+
+// parseDate accepts YYYY-MM-DD, and also the RFC 3339 times that dates are returned with so that
+// a GET response can be sent back unchanged.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format. Use YYYY-MM-DD: %w", err)
+	}
+
+	return date, nil
+}
+
+// Covers returns true if the exception applies on the given date.
+func (e ScheduleException) Covers(date time.Time) bool {
+	return !date.Before(e.StartDate) && !date.After(e.EndDate)
+}
+
+// Validate checks the exception has a recognised kind, the ids its kind needs, a direction and a valid date range.
+func (e ScheduleException) Validate() error {
+	switch e.Kind {
+	case ExceptionExtraFlight:
+		if len(e.LaunchPadId) == 0 || len(e.DestinationId) == 0 {
+			return ValidationError{Reason: "launch_pad_id and destination_id are required for an extra_flight"}
+		}
+	case ExceptionCancelledFlight, ExceptionLaunchPadClosed:
+		if len(e.LaunchPadId) == 0 {
+			return ValidationError{Reason: fmt.Sprintf("launch_pad_id is required for a %s", e.Kind)}
+		}
+	case ExceptionDestinationUnavailable:
+		if len(e.DestinationId) == 0 {
+			return ValidationError{Reason: "destination_id is required for a destination_unavailable"}
+		}
+	default:
+		return ValidationError{Reason: fmt.Sprintf("unrecognised kind %q", e.Kind)}
+	}
+
+	if e.Direction != DirectionOutbound && e.Direction != DirectionReturn {
+		return ValidationError{Reason: fmt.Sprintf("direction must be %s or %s", DirectionOutbound, DirectionReturn)}
+	}
+
+	if e.StartDate.IsZero() {
+		return ValidationError{Reason: "start_date is required"}
+	}
+
+	if e.EndDate.Before(e.StartDate) {
+		return ValidationError{Reason: "end_date must not be before start_date"}
+	}
+
+	return nil
+}
+
+// CheckFlight decides whether a launchpad flies to a destination, or a return flight from the destination lands at the
+// launchpad, on the given date. scheduledWeekly says whether the weekly schedule has the flight, and exceptions are the
+// exceptions that cover the date. Only exceptions in the flight's direction apply.
+//
+// Closures and unavailable destinations take priority over everything else, then cancellations, then extra flights,
+// and finally the weekly schedule. It returns nil if the flight goes ahead.
+func CheckFlight(scheduledWeekly bool, exceptions []ScheduleException, direction, launchPadId, destinationId string, date time.Time) error {
+	var cancelled, extra bool
+
+	for _, e := range exceptions {
+		if !e.Covers(date) || e.Direction != direction {
+			continue
+		}
+
+		switch e.Kind {
+		case ExceptionLaunchPadClosed:
+			if e.LaunchPadId == launchPadId {
+				return ErrLaunchPadClosed
+			}
+		case ExceptionDestinationUnavailable:
+			if e.DestinationId == destinationId && (len(e.LaunchPadId) == 0 || e.LaunchPadId == launchPadId) {
+				return ErrDestinationUnavailable
+			}
+		case ExceptionCancelledFlight:
+			if e.LaunchPadId == launchPadId && (len(e.DestinationId) == 0 || e.DestinationId == destinationId) {
+				cancelled = true
+			}
+		case ExceptionExtraFlight:
+			if e.LaunchPadId == launchPadId && e.DestinationId == destinationId {
+				extra = true
+			}
+		}
+	}
+
+	switch {
+	case cancelled:
+		return ErrFlightCancelled
+	case extra, scheduledWeekly:
+		return nil
+	default:
+		return ErrLaunchScheduleInvalid
+	}
+}
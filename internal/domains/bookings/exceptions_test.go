@@ -0,0 +1,118 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckFlight(t *testing.T) {
+	date, _ := time.Parse(time.DateOnly, "2024-01-03")
+	weekBefore, _ := time.Parse(time.DateOnly, "2023-12-27")
+
+	tests := []struct {
+		name            string
+		direction       string
+		scheduledWeekly bool
+		exceptions      []ScheduleException
+		want            error
+	}{
+		{
+			name:            "1. Weekly flight with no exceptions goes ahead",
+			scheduledWeekly: true,
+			want:            nil,
+		},
+		{
+			name: "2. No weekly flight and no exceptions is invalid",
+			want: ErrLaunchScheduleInvalid,
+		},
+		{
+			name:       "3. Extra flight goes ahead",
+			exceptions: []ScheduleException{{Kind: ExceptionExtraFlight, LaunchPadId: "pad", DestinationId: "moon", Direction: DirectionOutbound, StartDate: date, EndDate: date}},
+			want:       nil,
+		},
+		{
+			name:       "4. Extra flight to a different destination doesn't help",
+			exceptions: []ScheduleException{{Kind: ExceptionExtraFlight, LaunchPadId: "pad", DestinationId: "mars", Direction: DirectionOutbound, StartDate: date, EndDate: date}},
+			want:       ErrLaunchScheduleInvalid,
+		},
+		{
+			name:            "5. Cancelled flight with no destination cancels every flight from the launchpad",
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionCancelledFlight, LaunchPadId: "pad", Direction: DirectionOutbound, StartDate: date, EndDate: date}},
+			want:            ErrFlightCancelled,
+		},
+		{
+			name:            "6. Cancelled flight to a different destination doesn't apply",
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionCancelledFlight, LaunchPadId: "pad", DestinationId: "mars", Direction: DirectionOutbound, StartDate: date, EndDate: date}},
+			want:            nil,
+		},
+		{
+			name: "7. Launchpad closure overrides an extra flight",
+			exceptions: []ScheduleException{
+				{Kind: ExceptionExtraFlight, LaunchPadId: "pad", DestinationId: "moon", Direction: DirectionOutbound, StartDate: date, EndDate: date},
+				{Kind: ExceptionLaunchPadClosed, LaunchPadId: "pad", Direction: DirectionOutbound, StartDate: weekBefore, EndDate: date},
+			},
+			want: ErrLaunchPadClosed,
+		},
+		{
+			name:            "8. Destination unavailable from every launchpad",
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionDestinationUnavailable, DestinationId: "moon", Direction: DirectionOutbound, StartDate: weekBefore, EndDate: date}},
+			want:            ErrDestinationUnavailable,
+		},
+		{
+			name:            "9. Closure that ended the day before doesn't apply",
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionLaunchPadClosed, LaunchPadId: "pad", Direction: DirectionOutbound, StartDate: weekBefore, EndDate: date.AddDate(0, 0, -1)}},
+			want:            nil,
+		},
+		{
+			name:       "10. Outbound extra flight doesn't add a return flight",
+			direction:  DirectionReturn,
+			exceptions: []ScheduleException{{Kind: ExceptionExtraFlight, LaunchPadId: "pad", DestinationId: "moon", Direction: DirectionOutbound, StartDate: date, EndDate: date}},
+			want:       ErrLaunchScheduleInvalid,
+		},
+		{
+			name:       "11. Return extra flight goes ahead",
+			direction:  DirectionReturn,
+			exceptions: []ScheduleException{{Kind: ExceptionExtraFlight, LaunchPadId: "pad", DestinationId: "moon", Direction: DirectionReturn, StartDate: date, EndDate: date}},
+			want:       nil,
+		},
+		{
+			name:            "12. Launchpad closed to launches still takes landings",
+			direction:       DirectionReturn,
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionLaunchPadClosed, LaunchPadId: "pad", Direction: DirectionOutbound, StartDate: date, EndDate: date}},
+			want:            nil,
+		},
+		{
+			name:            "13. Launchpad closed to landings",
+			direction:       DirectionReturn,
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionLaunchPadClosed, LaunchPadId: "pad", Direction: DirectionReturn, StartDate: date, EndDate: date}},
+			want:            ErrLaunchPadClosed,
+		},
+		{
+			name:            "14. Launchpad closed to landings still launches",
+			scheduledWeekly: true,
+			exceptions:      []ScheduleException{{Kind: ExceptionLaunchPadClosed, LaunchPadId: "pad", Direction: DirectionReturn, StartDate: date, EndDate: date}},
+			want:            nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			direction := tt.direction
+			if len(direction) == 0 {
+				direction = DirectionOutbound
+			}
+
+			err := CheckFlight(tt.scheduledWeekly, tt.exceptions, direction, "pad", "moon", date)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("wrong error, got %v want %v", err, tt.want)
+			}
+		})
+	}
+}
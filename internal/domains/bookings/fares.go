@@ -0,0 +1,324 @@
+package bookings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The kinds of fare rule.
+const (
+	// FareRuleDayOfWeek changes the price of flights leaving on a day of the week.
+	FareRuleDayOfWeek = "day_of_week"
+	// FareRuleSeason changes the price of flights leaving between two dates.
+	FareRuleSeason = "season"
+)
+
+// Age discounts, worked out from the customer's age on the launch date.
+const (
+	// ChildMaxAge is the oldest a customer can be to get the child discount.
+	ChildMaxAge = 11
+	// ChildDiscountPercent is taken off the price for children.
+	ChildDiscountPercent = 50
+	// SeniorMinAge is the youngest a customer can be to get the senior discount.
+	SeniorMinAge = 65
+	// SeniorDiscountPercent is taken off the price for seniors.
+	SeniorDiscountPercent = 20
+)
+
+var (
+	// ErrNoFare is returned when quoting for a flight that doesn't have a fare.
+	ErrNoFare = errors.New("no fare has been set for this flight")
+	// ErrPromoCodeInvalid is returned when a promo code doesn't exist or can't be used today.
+	ErrPromoCodeInvalid = errors.New("promo code is not valid")
+)
+
+var currencyRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Fare is the base price of a seat on flights between a launchpad and a destination. Prices are in the currency's
+// minor unit, e.g. cents.
+type Fare struct {
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	BasePrice     int64     `json:"base_price"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate checks the fare names a launchpad and destination, isn't negative and has an ISO 4217 currency code.
+func (f Fare) Validate() error {
+	if len(f.LaunchPadId) == 0 || len(f.DestinationId) == 0 {
+		return ValidationError{Reason: "launch_pad_id and destination_id are required"}
+	}
+
+	if f.BasePrice < 0 {
+		return ValidationError{Reason: "base_price must not be negative"}
+	}
+
+	if !currencyRegexp.MatchString(f.Currency) {
+		return ValidationError{Reason: "currency must be a three letter ISO 4217 code, e.g. USD"}
+	}
+
+	return nil
+}
+
+// FareRule multiplies the price of flights on a day of the week, or between StartDate and EndDate inclusive.
+// An empty LaunchPadId or DestinationId applies the rule to every launchpad or destination.
+type FareRule struct {
+	Id            string     `json:"id"`
+	Kind          string     `json:"kind"`
+	LaunchPadId   string     `json:"launch_pad_id"`
+	DestinationId string     `json:"destination_id"`
+	DayOfWeek     string     `json:"day_of_week"`
+	StartDate     *time.Time `json:"start_date"`
+	EndDate       *time.Time `json:"end_date"`
+	Multiplier    float64    `json:"multiplier"`
+	Reason        string     `json:"reason"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// UnmarshalJSON unmarshals fare rule JSON, parsing the start and end dates as YYYY-MM-DD.
+func (r *FareRule) UnmarshalJSON(data []byte) error {
+	type Alias FareRule
+	aux := &struct {
+		StartDate json.RawMessage `json:"start_date"`
+		EndDate   json.RawMessage `json:"end_date"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if r.StartDate, err = unmarshalOptionalDate(aux.StartDate, r.StartDate); err != nil {
+		return err
+	}
+
+	if r.EndDate, err = unmarshalOptionalDate(aux.EndDate, r.EndDate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the rule has a recognised kind, the day or dates its kind needs and a positive multiplier.
+func (r FareRule) Validate() error {
+	switch r.Kind {
+	case FareRuleDayOfWeek:
+		if !isWeekday(r.DayOfWeek) {
+			return ValidationError{Reason: fmt.Sprintf("unrecognised day_of_week %q", r.DayOfWeek)}
+		}
+	case FareRuleSeason:
+		if r.StartDate == nil || r.EndDate == nil {
+			return ValidationError{Reason: "start_date and end_date are required for a season"}
+		}
+		if r.EndDate.Before(*r.StartDate) {
+			return ValidationError{Reason: "end_date must not be before start_date"}
+		}
+	default:
+		return ValidationError{Reason: fmt.Sprintf("unrecognised kind %q", r.Kind)}
+	}
+
+	if r.Multiplier <= 0 {
+		return ValidationError{Reason: "multiplier must be greater than 0"}
+	}
+
+	return nil
+}
+
+// Applies returns true if the rule changes the price of the flight from the launchpad to the destination on the date.
+func (r FareRule) Applies(launchPadId, destinationId string, date time.Time) bool {
+	if len(r.LaunchPadId) > 0 && r.LaunchPadId != launchPadId {
+		return false
+	}
+
+	if len(r.DestinationId) > 0 && r.DestinationId != destinationId {
+		return false
+	}
+
+	switch r.Kind {
+	case FareRuleDayOfWeek:
+		return r.DayOfWeek == date.Weekday().String()
+	case FareRuleSeason:
+		return r.StartDate != nil && r.EndDate != nil && !date.Before(*r.StartDate) && !date.After(*r.EndDate)
+	default:
+		return false
+	}
+}
+
+// PromoCode takes PercentOff off the price of flights booked from ValidFrom to ValidTo inclusive.
+type PromoCode struct {
+	Code       string    `json:"code"`
+	PercentOff int       `json:"percent_off"`
+	ValidFrom  time.Time `json:"valid_from"`
+	ValidTo    time.Time `json:"valid_to"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON unmarshals promo code JSON, parsing the validity dates as YYYY-MM-DD.
+func (p *PromoCode) UnmarshalJSON(data []byte) error {
+	type Alias PromoCode
+	aux := &struct {
+		ValidFrom string `json:"valid_from"`
+		ValidTo   string `json:"valid_to"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.ValidFrom) > 0 {
+		validFrom, err := parseDate(aux.ValidFrom)
+		if err != nil {
+			return err
+		}
+		p.ValidFrom = validFrom
+	}
+
+	if len(aux.ValidTo) > 0 {
+		validTo, err := parseDate(aux.ValidTo)
+		if err != nil {
+			return err
+		}
+		p.ValidTo = validTo
+	}
+
+	return nil
+}
+
+// NormalisePromoCode returns the code the way promo codes are stored, so customers can type them in any case.
+func NormalisePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks the promo code has a code, takes off between 1 and 100 percent and has a valid date range.
+func (p PromoCode) Validate() error {
+	if len(p.Code) == 0 {
+		return ValidationError{Reason: "code is required"}
+	}
+
+	if p.PercentOff < 1 || p.PercentOff > 100 {
+		return ValidationError{Reason: "percent_off must be between 1 and 100"}
+	}
+
+	if p.ValidFrom.IsZero() || p.ValidTo.IsZero() {
+		return ValidationError{Reason: "valid_from and valid_to are required"}
+	}
+
+	if p.ValidTo.Before(p.ValidFrom) {
+		return ValidationError{Reason: "valid_to must not be before valid_from"}
+	}
+
+	return nil
+}
+
+// ValidOn returns true if the promo code can be used on the given day.
+func (p PromoCode) ValidOn(now time.Time) bool {
+	today := Today(now)
+
+	return !today.Before(p.ValidFrom) && !today.After(p.ValidTo)
+}
+
+// Adjustment is one of the changes made to the base price to get a quoted price.
+type Adjustment struct {
+	Reason     string  `json:"reason"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Quote is the price of a seat for one customer on a flight, and how it was worked out from the fare.
+type Quote struct {
+	LaunchPadId   string       `json:"launch_pad_id"`
+	DestinationId string       `json:"destination_id"`
+	LaunchDate    time.Time    `json:"launch_date"`
+	BasePrice     int64        `json:"base_price"`
+	Price         int64        `json:"price"`
+	Currency      string       `json:"currency"`
+	Adjustments   []Adjustment `json:"adjustments"`
+}
+
+// NewQuote prices a seat on the fare's flight on the launch date. The fare rules that apply to the flight are
+// applied first, then the age discount for a customer with the birthday, if there is one, then the promo code, if
+// there is one. The price is rounded to the nearest minor unit.
+func NewQuote(fare Fare, rules []FareRule, launchDate time.Time, birthday *time.Time, promo *PromoCode) Quote {
+	quote := Quote{
+		LaunchPadId:   fare.LaunchPadId,
+		DestinationId: fare.DestinationId,
+		LaunchDate:    launchDate,
+		BasePrice:     fare.BasePrice,
+		Currency:      fare.Currency,
+		Adjustments:   []Adjustment{},
+	}
+
+	for _, rule := range rules {
+		if rule.Applies(fare.LaunchPadId, fare.DestinationId, launchDate) {
+			reason := rule.Reason
+			if len(reason) == 0 {
+				reason = rule.Kind
+			}
+			quote.Adjustments = append(quote.Adjustments, Adjustment{Reason: reason, Multiplier: rule.Multiplier})
+		}
+	}
+
+	if birthday != nil {
+		switch age := AgeOn(*birthday, launchDate); {
+		case age <= ChildMaxAge:
+			quote.Adjustments = append(quote.Adjustments, Adjustment{Reason: "child discount", Multiplier: percentOff(ChildDiscountPercent)})
+		case age >= SeniorMinAge:
+			quote.Adjustments = append(quote.Adjustments, Adjustment{Reason: "senior discount", Multiplier: percentOff(SeniorDiscountPercent)})
+		}
+	}
+
+	if promo != nil {
+		quote.Adjustments = append(quote.Adjustments, Adjustment{Reason: "promo code " + promo.Code, Multiplier: percentOff(promo.PercentOff)})
+	}
+
+	price := float64(fare.BasePrice)
+	for _, adjustment := range quote.Adjustments {
+		price *= adjustment.Multiplier
+	}
+	quote.Price = int64(math.Round(price))
+
+	return quote
+}
+
+// AgeOn returns how old someone born on the birthday is on the date, in whole years.
+func AgeOn(birthday, date time.Time) int {
+	age := date.Year() - birthday.Year()
+	if date.Month() < birthday.Month() || (date.Month() == birthday.Month() && date.Day() < birthday.Day()) {
+		age--
+	}
+
+	return age
+}
+
+func percentOff(percent int) float64 {
+	return float64(100-percent) / 100
+}
+
+// Pricing defines the methods an object needs to implement to manage fares, the rules that change them and promo
+// codes.
+// GetFare returns bookings.ErrNotFound if the flight between the launchpad and destination doesn't have a fare.
+type Pricing interface {
+	GetFares() ([]Fare, error)
+	GetFare(launchPadId, destinationId string) (*Fare, error)
+	SetFare(fare Fare) (*Fare, error)
+	DeleteFare(launchPadId, destinationId string) (int64, error)
+	GetFareRules() ([]FareRule, error)
+	CreateFareRule(rule FareRule) (*FareRule, error)
+	DeleteFareRule(id string) (int64, error)
+	GetPromoCodes() ([]PromoCode, error)
+	GetPromoCode(code string) (*PromoCode, error)
+	SetPromoCode(promo PromoCode) (*PromoCode, error)
+	DeletePromoCode(code string) (int64, error)
+}
@@ -0,0 +1,104 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewQuote(t *testing.T) {
+	fare := Fare{LaunchPadId: "pad", DestinationId: "moon", BasePrice: 1000, Currency: "USD"}
+	saturday, _ := time.Parse(time.DateOnly, "2024-01-06")
+	monday, _ := time.Parse(time.DateOnly, "2024-01-08")
+	seasonStart, _ := time.Parse(time.DateOnly, "2024-01-01")
+	seasonEnd, _ := time.Parse(time.DateOnly, "2024-01-07")
+
+	rules := []FareRule{
+		{Kind: FareRuleDayOfWeek, DayOfWeek: "Saturday", Multiplier: 1.2, Reason: "weekend"},
+		{Kind: FareRuleSeason, StartDate: &seasonStart, EndDate: &seasonEnd, Multiplier: 1.5},
+		{Kind: FareRuleDayOfWeek, DayOfWeek: "Monday", DestinationId: "mars", Multiplier: 2},
+	}
+
+	child, _ := time.Parse(time.DateOnly, "2015-01-07")
+	senior, _ := time.Parse(time.DateOnly, "1959-01-08")
+	adult, _ := time.Parse(time.DateOnly, "1959-01-09")
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		birthday  *time.Time
+		promo     *PromoCode
+		wantPrice int64
+	}{
+		{
+			name:      "1. No rules apply",
+			date:      monday,
+			wantPrice: 1000,
+		},
+		{
+			name:      "2. Weekend in the season",
+			date:      saturday,
+			wantPrice: 1800,
+		},
+		{
+			name:      "3. Child the day after their ninth birthday",
+			date:      monday,
+			birthday:  &child,
+			wantPrice: 500,
+		},
+		{
+			name:      "4. Senior on their 65th birthday",
+			date:      monday,
+			birthday:  &senior,
+			wantPrice: 800,
+		},
+		{
+			name:      "5. Adult the day before their 65th birthday",
+			date:      monday,
+			birthday:  &adult,
+			wantPrice: 1000,
+		},
+		{
+			name:      "6. Promo code rounded to the nearest minor unit",
+			date:      saturday,
+			promo:     &PromoCode{Code: "THIRD", PercentOff: 33},
+			wantPrice: 1206,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := NewQuote(fare, rules, tt.date, tt.birthday, tt.promo)
+
+			if quote.Price != tt.wantPrice {
+				t.Errorf("wrong price, got %d want %d with adjustments %+v", quote.Price, tt.wantPrice, quote.Adjustments)
+			}
+			if quote.BasePrice != 1000 || quote.Currency != "USD" {
+				t.Errorf("wrong base price, got %d %s", quote.BasePrice, quote.Currency)
+			}
+		})
+	}
+}
+
+func TestPromoCode_ValidOn(t *testing.T) {
+	validFrom, _ := time.Parse(time.DateOnly, "2024-01-01")
+	validTo, _ := time.Parse(time.DateOnly, "2024-01-31")
+	promo := PromoCode{Code: "JANUARY", PercentOff: 10, ValidFrom: validFrom, ValidTo: validTo}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "1. Last moment of the last day", now: time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC), want: true},
+		{name: "2. First day", now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "3. Day after it ends", now: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promo.ValidOn(tt.now); got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package bookings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoPassengers is returned when a group booking doesn't list any passengers.
+	ErrNoPassengers = errors.New("group booking has no passengers")
+	// ErrNotEnoughSeats is returned when the flight has seats left, but not enough for everyone in the group.
+	ErrNotEnoughSeats = errors.New("not enough seats remaining on this flight for the group")
+)
+
+// GroupBooking is a request to book several passengers on the same flight. Either every passenger is booked or none
+// of them are.
+type GroupBooking struct {
+	LaunchPadId   string     `json:"launch_pad_id"`
+	DestinationId string     `json:"destination_id"`
+	LaunchDate    time.Time  `json:"launch_date"`
+	PromoCode     string     `json:"promo_code"`
+	Passengers    []Customer `json:"passengers"`
+}
+
+// UnmarshalJSON unmarshals group booking JSON so that dates have the proper time.Time format.
+func (g *GroupBooking) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		LaunchPadId   string `json:"launch_pad_id"`
+		DestinationId string `json:"destination_id"`
+		LaunchDate    string `json:"launch_date"`
+		PromoCode     string `json:"promo_code"`
+		Passengers    []struct {
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+			Gender    string `json:"gender"`
+			Birthday  string `json:"birthday"`
+			Email     string `json:"email"`
+			Phone     string `json:"phone"`
+		} `json:"passengers"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	launchDate, err := time.Parse(time.DateOnly, aux.LaunchDate)
+	if err != nil {
+		return fmt.Errorf("invalid date format. Use YYYY-MM-DD: %w", err)
+	}
+
+	g.LaunchPadId = aux.LaunchPadId
+	g.DestinationId = aux.DestinationId
+	g.LaunchDate = launchDate
+	g.PromoCode = aux.PromoCode
+	g.Passengers = make([]Customer, 0, len(aux.Passengers))
+
+	for _, passenger := range aux.Passengers {
+		birthday, err := time.Parse(time.DateOnly, passenger.Birthday)
+		if err != nil {
+			return fmt.Errorf("invalid date format. Use YYYY-MM-DD: %w", err)
+		}
+
+		g.Passengers = append(g.Passengers, Customer{
+			FirstName: passenger.FirstName,
+			LastName:  passenger.LastName,
+			Gender:    passenger.Gender,
+			Birthday:  birthday,
+			Email:     passenger.Email,
+			Phone:     passenger.Phone,
+		})
+	}
+
+	return nil
+}
+
+// Flight returns an outbound booking for the group's flight, without a customer.
+func (g GroupBooking) Flight() Booking {
+	return Booking{
+		LaunchPadId:   g.LaunchPadId,
+		DestinationId: g.DestinationId,
+		LaunchDate:    g.LaunchDate,
+		Direction:     DirectionOutbound,
+		PromoCode:     g.PromoCode,
+	}
+}
@@ -0,0 +1,128 @@
+package bookings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultHoldMinutes is how long a hold lasts when the request doesn't say.
+	DefaultHoldMinutes = 15
+	// MaxHoldMinutes is the longest a seat can be held for.
+	MaxHoldMinutes = 60
+)
+
+// ErrHoldExpired is returned when confirming a hold after it has expired, and its seat may have gone to someone else.
+var ErrHoldExpired = errors.New("hold has expired")
+
+// Hold reserves a seat on a flight until ExpiresAt, so the customer can give their details without the flight filling
+// up. Confirming the hold turns it into a booking, and expired holds are released by the hold sweeper.
+type Hold struct {
+	Id            string    `json:"id"`
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	LaunchDate    time.Time `json:"launch_date"`
+	Direction     string    `json:"direction"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// HoldRequest asks for a seat on a flight to be held for Minutes.
+type HoldRequest struct {
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	LaunchDate    time.Time `json:"launch_date"`
+	Minutes       int       `json:"minutes"`
+}
+
+// UnmarshalJSON unmarshals hold request JSON so that dates have the proper time.Time format.
+func (h *HoldRequest) UnmarshalJSON(data []byte) error {
+	type Alias HoldRequest
+	aux := &struct {
+		LaunchDate string `json:"launch_date"`
+		*Alias
+	}{
+		Alias: (*Alias)(h),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	launchDate, err := time.Parse(time.DateOnly, aux.LaunchDate)
+	if err != nil {
+		return fmt.Errorf("invalid date format. Use YYYY-MM-DD: %w", err)
+	}
+
+	h.LaunchDate = launchDate
+
+	return nil
+}
+
+// Validate checks the request names a flight and holds the seat for between 1 and MaxHoldMinutes minutes. A request
+// without minutes, or with minutes of 0, is held for DefaultHoldMinutes.
+func (h HoldRequest) Validate() error {
+	if len(h.LaunchPadId) == 0 || len(h.DestinationId) == 0 {
+		return ValidationError{Reason: "launch_pad_id and destination_id are required"}
+	}
+
+	if h.Minutes < 0 || h.Minutes > MaxHoldMinutes {
+		return ValidationError{Reason: fmt.Sprintf("minutes must be between 1 and %d, or left out to hold the seat for %d",
+			MaxHoldMinutes, DefaultHoldMinutes)}
+	}
+
+	return nil
+}
+
+// Flight returns an outbound booking for the flight the seat is being held on, without a customer.
+func (h HoldRequest) Flight() Booking {
+	return Booking{
+		LaunchPadId:   h.LaunchPadId,
+		DestinationId: h.DestinationId,
+		LaunchDate:    h.LaunchDate,
+		Direction:     DirectionOutbound,
+	}
+}
+
+// NewHold returns a hold on the request's flight, made at now.
+func (h HoldRequest) NewHold(now time.Time) Hold {
+	minutes := h.Minutes
+	if minutes == 0 {
+		minutes = DefaultHoldMinutes
+	}
+
+	return Hold{
+		LaunchPadId:   h.LaunchPadId,
+		DestinationId: h.DestinationId,
+		LaunchDate:    h.LaunchDate,
+		Direction:     DirectionOutbound,
+		ExpiresAt:     now.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+// Expired returns true if the hold had expired by now.
+func (h Hold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// Booking returns a booking for the customer on the held flight.
+func (h Hold) Booking(customer Customer) Booking {
+	return Booking{
+		Customer:      customer,
+		LaunchPadId:   h.LaunchPadId,
+		DestinationId: h.DestinationId,
+		LaunchDate:    h.LaunchDate,
+		Direction:     h.Direction,
+	}
+}
+
+// Holds defines the methods an object needs to implement to store seat holds. Holds that have expired don't count
+// towards the seats taken on a flight, even before they're deleted.
+type Holds interface {
+	GetHold(id string) (*Hold, error)
+	CreateHold(hold Hold) (*Hold, error)
+	DeleteHold(id string) (int64, error)
+	CountHolds(direction, launchPadId string, launchDate, now time.Time) (int, error)
+	DeleteExpiredHolds(now time.Time) (int64, error)
+}
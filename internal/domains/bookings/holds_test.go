@@ -0,0 +1,77 @@
+package bookings
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHoldRequest(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		body          string
+		wantExpiresAt time.Time
+		wantErr       string
+	}{
+		{
+			name:          "1. Holds for the default number of minutes",
+			body:          `{"launch_pad_id": "pad", "destination_id": "moon", "launch_date": "2024-01-08"}`,
+			wantExpiresAt: now.Add(DefaultHoldMinutes * time.Minute),
+		},
+		{
+			name:          "2. Holds for the requested number of minutes",
+			body:          `{"launch_pad_id": "pad", "destination_id": "moon", "launch_date": "2024-01-08", "minutes": 5}`,
+			wantExpiresAt: now.Add(5 * time.Minute),
+		},
+		{
+			name:    "3. Holds for longer than allowed",
+			body:    `{"launch_pad_id": "pad", "destination_id": "moon", "launch_date": "2024-01-08", "minutes": 61}`,
+			wantErr: "minutes must be between 1 and 60, or left out to hold the seat for 15",
+		},
+		{
+			name:    "3a. Holds for a negative number of minutes",
+			body:    `{"launch_pad_id": "pad", "destination_id": "moon", "launch_date": "2024-01-08", "minutes": -1}`,
+			wantErr: "minutes must be between 1 and 60, or left out to hold the seat for 15",
+		},
+		{
+			name:    "4. Doesn't name a flight",
+			body:    `{"launch_date": "2024-01-08"}`,
+			wantErr: "launch_pad_id and destination_id are required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request HoldRequest
+			if err := json.Unmarshal([]byte(tt.body), &request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := request.Validate()
+			var validationErr ValidationError
+			if len(tt.wantErr) > 0 {
+				if !errors.As(err, &validationErr) || validationErr.Reason != tt.wantErr {
+					t.Errorf("wrong error, got %v want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			hold := request.NewHold(now)
+			if !hold.ExpiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("wrong expiry, got %v want %v", hold.ExpiresAt, tt.wantExpiresAt)
+			}
+			if hold.Direction != DirectionOutbound || hold.LaunchDate.Format(time.DateOnly) != "2024-01-08" {
+				t.Errorf("wrong flight held, got %+v", hold)
+			}
+			if hold.Expired(now) || !hold.Expired(hold.ExpiresAt) {
+				t.Errorf("wrong expiry check for hold %+v", hold)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SpaceXLaunchPadRetired is the status SpaceX give launchpads that are no longer used.
+const SpaceXLaunchPadRetired = "retired"
+
+// SpaceXLaunchPad is a launchpad as listed by the SpaceX API's /v4/launchpads endpoint.
+type SpaceXLaunchPad struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Status   string `json:"status"`
+}
+
+// SpaceXLaunchPads lists every launchpad SpaceX know about.
+type SpaceXLaunchPads interface {
+	GetSpaceXLaunchPads() ([]SpaceXLaunchPad, error)
+}
+
+// LaunchPadSync reports what syncing our launchpads with SpaceX's changed, and what still needs an admin to look at it.
+type LaunchPadSync struct {
+	// Renamed launchpads have been given SpaceX's full name for them.
+	Renamed []LaunchPad `json:"renamed"`
+	// Retired launchpads have been marked inactive because SpaceX have retired them.
+	Retired []LaunchPad `json:"retired"`
+	// Unmapped launchpads are listed by SpaceX but none of our launchpads use their id, so they can't be flown from.
+	Unmapped []SpaceXLaunchPad `json:"unmapped"`
+	// Unknown launchpads use a SpaceX launchpad id that SpaceX don't list, so their launches can't be checked.
+	Unknown []LaunchPad `json:"unknown"`
+}
+
+// SyncLaunchPads reconciles our launchpads with SpaceX's by SpaceX launchpad id. Launchpads are renamed to SpaceX's full
+// name for them, and active launchpads SpaceX have retired are marked inactive so no more flights can be booked from
+// them. Launchpads we've retired ourselves stay retired whatever SpaceX say. A launchpad that can't be updated doesn't
+// stop the others.
+func SyncLaunchPads(booker Booker, spaceX SpaceXLaunchPads) (LaunchPadSync, error) {
+	var report LaunchPadSync
+
+	spaceXLaunchPads, err := spaceX.GetSpaceXLaunchPads()
+	if err != nil {
+		return report, fmt.Errorf("could not get SpaceX launchpads: %w", err)
+	}
+
+	launchPads, err := booker.GetLaunchPads()
+	if err != nil {
+		return report, err
+	}
+
+	byId := map[string]SpaceXLaunchPad{}
+	for _, spaceXLaunchPad := range spaceXLaunchPads {
+		byId[spaceXLaunchPad.Id] = spaceXLaunchPad
+	}
+
+	mapped := map[string]bool{}
+	var errs []error
+
+	for _, launchPad := range launchPads {
+		spaceXLaunchPad, ok := byId[launchPad.SpaceXLaunchPadId]
+		if !ok {
+			report.Unknown = append(report.Unknown, launchPad)
+			continue
+		}
+		mapped[spaceXLaunchPad.Id] = true
+
+		renamed := len(spaceXLaunchPad.FullName) > 0 && launchPad.FullName != spaceXLaunchPad.FullName
+		retired := launchPad.Active && spaceXLaunchPad.Status == SpaceXLaunchPadRetired
+		if !renamed && !retired {
+			continue
+		}
+
+		if renamed {
+			launchPad.FullName = spaceXLaunchPad.FullName
+		}
+		if retired {
+			launchPad.Active = false
+		}
+
+		updated, err := booker.UpdateLaunchPad(launchPad)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not sync launchpad %s: %w", launchPad.Id, err))
+			continue
+		}
+
+		if renamed {
+			report.Renamed = append(report.Renamed, *updated)
+		}
+		if retired {
+			report.Retired = append(report.Retired, *updated)
+		}
+	}
+
+	for _, spaceXLaunchPad := range spaceXLaunchPads {
+		if !mapped[spaceXLaunchPad.Id] {
+			report.Unmapped = append(report.Unmapped, spaceXLaunchPad)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
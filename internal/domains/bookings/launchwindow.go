@@ -0,0 +1,113 @@
+package bookings
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// DefaultWindowOpens is when a flight's launch window opens if its schedule entry doesn't say.
+	DefaultWindowOpens = "00:00"
+	// DefaultWindowCloses is when a flight's launch window closes if its schedule entry doesn't say.
+	DefaultWindowCloses = "24:00"
+)
+
+var windowTimeRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$|^24:00$`)
+
+// LaunchWindow is the period a flight can depart in.
+type LaunchWindow struct {
+	Opens  time.Time
+	Closes time.Time
+}
+
+// NewLaunchWindow returns the window for a flight from the launchpad on the launch date. The launch date is the
+// calendar date at the launchpad, and the window times in the schedule entry are the launchpad's local time. If the
+// entry is nil, e.g. for an extra flight, the window is the launchpad's whole local day.
+func NewLaunchWindow(launchPad LaunchPad, entry *ScheduleEntry, launchDate time.Time) (LaunchWindow, error) {
+	location, err := time.LoadLocation(launchPad.Timezone)
+	if err != nil {
+		return LaunchWindow{}, fmt.Errorf("launchpad %s timezone: %w", launchPad.Id, err)
+	}
+
+	opens, closes := DefaultWindowOpens, DefaultWindowCloses
+	if entry != nil {
+		opens, closes = entry.Window()
+	}
+
+	opensAt, err := localTime(launchDate, opens, location)
+	if err != nil {
+		return LaunchWindow{}, err
+	}
+
+	closesAt, err := localTime(launchDate, closes, location)
+	if err != nil {
+		return LaunchWindow{}, err
+	}
+
+	return LaunchWindow{Opens: opensAt, Closes: closesAt}, nil
+}
+
+// LocalDate returns the calendar date at the launchpad at now, as a launch date.
+func (l LaunchPad) LocalDate(now time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("launchpad %s timezone: %w", l.Id, err)
+	}
+
+	return Today(now.In(location)), nil
+}
+
+// Today returns the calendar date of now in its own location, as a launch date at midnight UTC.
+func Today(now time.Time) time.Time {
+	today, _ := time.Parse(time.DateOnly, now.Format(time.DateOnly))
+	return today
+}
+
+// Window returns the entry's launch window times, defaulting to the whole day.
+func (e ScheduleEntry) Window() (string, string) {
+	opens, closes := e.WindowOpens, e.WindowCloses
+	if len(opens) == 0 {
+		opens = DefaultWindowOpens
+	}
+	if len(closes) == 0 {
+		closes = DefaultWindowCloses
+	}
+
+	return opens, closes
+}
+
+// localTime returns the instant the HH:MM clock time happens on the date in the location. 24:00 is midnight at the
+// end of the date.
+func localTime(date time.Time, clock string, location *time.Location) (time.Time, error) {
+	if !windowTimeRegexp.MatchString(clock) {
+		return time.Time{}, fmt.Errorf("invalid launch window time %q", clock)
+	}
+
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil {
+		return time.Time{}, fmt.Errorf("invalid launch window time %q: %w", clock, err)
+	}
+
+	// time.Date normalises hour 24 to midnight the next day, and picks a valid time if the clock time is skipped
+	// by a daylight saving change.
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, location), nil
+}
+
+// validateWindow checks the window times are HH:MM and that the window closes after it opens.
+func validateWindow(opens, closes string) error {
+	if !windowTimeRegexp.MatchString(opens) || opens == "24:00" {
+		return ValidationError{Reason: "window_opens must be HH:MM"}
+	}
+
+	if !windowTimeRegexp.MatchString(closes) {
+		return ValidationError{Reason: "window_closes must be HH:MM"}
+	}
+
+	// Zero-padded HH:MM times sort in the same order as the times themselves.
+	if closes <= opens {
+		return ValidationError{Reason: "window_closes must be after window_opens"}
+	}
+
+	return nil
+}
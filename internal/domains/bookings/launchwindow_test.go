@@ -0,0 +1,81 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLaunchWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		timezone   string
+		entry      *ScheduleEntry
+		launchDate string
+		wantOpens  string
+		wantCloses string
+	}{
+		{
+			name:       "1. No window is the launchpad's whole local day",
+			timezone:   "Pacific/Kwajalein",
+			launchDate: "2022-10-05",
+			wantOpens:  "2022-10-04T12:00:00Z",
+			wantCloses: "2022-10-05T12:00:00Z",
+		},
+		{
+			name:       "2. Late evening window in Kwajalein is the morning in UTC",
+			timezone:   "Pacific/Kwajalein",
+			entry:      &ScheduleEntry{WindowOpens: "21:00", WindowCloses: "23:30"},
+			launchDate: "2022-10-05",
+			wantOpens:  "2022-10-05T09:00:00Z",
+			wantCloses: "2022-10-05T11:30:00Z",
+		},
+		{
+			name:       "3. Evening window in Florida crosses midnight UTC",
+			timezone:   "America/New_York",
+			entry:      &ScheduleEntry{WindowOpens: "19:00", WindowCloses: "22:00"},
+			launchDate: "2022-10-05",
+			wantOpens:  "2022-10-05T23:00:00Z",
+			wantCloses: "2022-10-06T02:00:00Z",
+		},
+		{
+			name:       "4. Window follows daylight saving time",
+			timezone:   "America/New_York",
+			entry:      &ScheduleEntry{WindowOpens: "19:00", WindowCloses: "22:00"},
+			launchDate: "2022-12-05",
+			wantOpens:  "2022-12-06T00:00:00Z",
+			wantCloses: "2022-12-06T03:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, tt.launchDate)
+
+			window, err := NewLaunchWindow(LaunchPad{Timezone: tt.timezone}, tt.entry, launchDate)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := window.Opens.UTC().Format(time.RFC3339); got != tt.wantOpens {
+				t.Errorf("wrong window opening, got %s want %s", got, tt.wantOpens)
+			}
+			if got := window.Closes.UTC().Format(time.RFC3339); got != tt.wantCloses {
+				t.Errorf("wrong window closing, got %s want %s", got, tt.wantCloses)
+			}
+		})
+	}
+}
+
+func TestLaunchPad_LocalDate(t *testing.T) {
+	// 03:00 UTC is still the evening before in New York.
+	now := time.Date(2024, 1, 8, 3, 0, 0, 0, time.UTC)
+
+	got, err := LaunchPad{Timezone: "America/New_York"}.LocalDate(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "2024-01-07"; got.Format(time.DateOnly) != want {
+		t.Errorf("wrong date, got %v want %v", got.Format(time.DateOnly), want)
+	}
+}
@@ -0,0 +1,150 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// The states a confirmed booking moves through around its flight. A confirmed booking checks in once check-in opens,
+// boards at the gate and has flown once its flight has departed. Passengers who don't board before departure are
+// StatusNoShow.
+const (
+	// StatusCheckedIn bookings have checked in for their flight, so they can board.
+	StatusCheckedIn = "checked_in"
+	// StatusBoarded bookings have had their boarding pass scanned at the gate.
+	StatusBoarded = "boarded"
+	// StatusFlown bookings boarded a flight that has departed.
+	StatusFlown = "flown"
+	// StatusNoShow bookings didn't board before their flight departed.
+	StatusNoShow = "no_show"
+)
+
+// DefaultCheckInOpens is how long before departure check-in opens, unless it's configured otherwise.
+const DefaultCheckInOpens = 24 * time.Hour
+
+var (
+	// ErrInvalidTransition is returned when a booking can't move from its status to the one requested, e.g. a
+	// cancelled booking checking in or a booking that has boarded being cancelled.
+	ErrInvalidTransition = errors.New("booking cannot move to the requested status")
+	// ErrCheckInNotOpen is returned when a passenger checks in or boards before check-in has opened for their flight.
+	ErrCheckInNotOpen = errors.New("check-in has not opened for this flight")
+	// ErrCheckInClosed is returned when a passenger checks in or boards once their flight has departed.
+	ErrCheckInClosed = errors.New("check-in has closed, the flight has departed")
+	// ErrNotDeparted is returned when a booking is marked as flown or a no-show before its flight has departed.
+	ErrNotDeparted = errors.New("flight has not departed yet")
+)
+
+// Lifecycle moves bookings through check-in, boarding and their flight.
+//
+// MoveStatus moves a booking that isn't deleted from one status to another, recording when it checked in or boarded.
+// 0 rows are affected if the booking's status isn't from, e.g. because it has already moved.
+type Lifecycle interface {
+	MoveStatus(bookingId, from, to string, at time.Time) (int64, error)
+}
+
+// transitions are the statuses a booking can move to from each status, apart from being cancelled.
+var transitions = map[string][]string{
+	StatusConfirmed: {StatusCheckedIn, StatusNoShow},
+	StatusCheckedIn: {StatusBoarded, StatusNoShow},
+	StatusBoarded:   {StatusFlown},
+}
+
+// CanMove returns true if a booking can move from one status to another. Bookings can be cancelled until they've
+// boarded or missed their flight.
+func CanMove(from, to string) bool {
+	if to == StatusCancelled {
+		return !slices.Contains([]string{StatusCancelled, StatusPaymentFailed, StatusBoarded, StatusFlown, StatusNoShow}, from)
+	}
+
+	return slices.Contains(transitions[from], to)
+}
+
+// ValidateLifecycleStatus checks the status is one a booking can be moved to around its flight.
+func ValidateLifecycleStatus(status string) error {
+	switch status {
+	case StatusCheckedIn, StatusBoarded, StatusFlown, StatusNoShow:
+		return nil
+	default:
+		return ValidationError{Reason: fmt.Sprintf("unrecognised status %q, use %s, %s, %s or %s", status, StatusCheckedIn,
+			StatusBoarded, StatusFlown, StatusNoShow)}
+	}
+}
+
+// ParseCheckInOpens parses how long before departure check-in opens, e.g. "24h". An empty string returns
+// DefaultCheckInOpens.
+func ParseCheckInOpens(s string) (time.Duration, error) {
+	if len(s) == 0 {
+		return DefaultCheckInOpens, nil
+	}
+
+	opens, err := time.ParseDuration(s)
+	if err != nil || opens <= 0 {
+		return 0, fmt.Errorf("invalid check-in duration %q, use a positive duration such as 24h", s)
+	}
+
+	return opens, nil
+}
+
+// DepartsAt returns when the booking's flight departs, its DepartureAt, or the start of its launch date if it doesn't
+// have one.
+func (b Booking) DepartsAt() time.Time {
+	if b.DepartureAt != nil {
+		return *b.DepartureAt
+	}
+
+	return b.LaunchDate
+}
+
+// CheckMove checks the booking can move to the status now. Checking in and boarding are only possible from
+// checkInOpens before departure until departure, and a booking can only be marked as flown or a no-show once its
+// flight has departed.
+func CheckMove(booking Booking, status string, checkInOpens time.Duration, now time.Time) error {
+	if booking.Deleted || !CanMove(booking.Status, status) {
+		return fmt.Errorf("booking %s is %s, it can't be %s: %w", booking.Id, booking.Status, status, ErrInvalidTransition)
+	}
+
+	departure := booking.DepartsAt()
+
+	switch status {
+	case StatusCheckedIn, StatusBoarded:
+		if now.Before(departure.Add(-checkInOpens)) {
+			return fmt.Errorf("booking %s check-in opens at %s: %w", booking.Id,
+				departure.Add(-checkInOpens).UTC().Format(time.RFC3339), ErrCheckInNotOpen)
+		}
+		if !now.Before(departure) {
+			return fmt.Errorf("booking %s departed at %s: %w", booking.Id, departure.UTC().Format(time.RFC3339), ErrCheckInClosed)
+		}
+	case StatusFlown, StatusNoShow:
+		if now.Before(departure) {
+			return fmt.Errorf("booking %s departs at %s: %w", booking.Id, departure.UTC().Format(time.RFC3339), ErrNotDeparted)
+		}
+	}
+
+	return nil
+}
+
+// MoveBooking moves the booking to the status if it's a legal move and it's within the move's time window, and returns
+// the booking as it is afterwards.
+func MoveBooking(booker Booker, bookingId, status string, checkInOpens time.Duration, now time.Time) (*Booking, error) {
+	booking, err := booker.Get(bookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckMove(*booking, status, checkInOpens, now); err != nil {
+		return nil, err
+	}
+
+	moved, err := booker.MoveStatus(booking.Id, booking.Status, status, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if moved == 0 {
+		return nil, fmt.Errorf("booking %s is no longer %s: %w", booking.Id, booking.Status, ErrInvalidTransition)
+	}
+
+	return booker.Get(booking.Id)
+}
@@ -0,0 +1,170 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckMove(t *testing.T) {
+	departure := time.Date(2030, 1, 14, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		status  string
+		deleted bool
+		to      string
+		now     time.Time
+		wantErr error
+	}{
+		{
+			name:   "1. Checks in once check-in opens",
+			status: StatusConfirmed,
+			to:     StatusCheckedIn,
+			now:    departure.Add(-DefaultCheckInOpens),
+		},
+		{
+			name:    "2. Checks in before check-in opens",
+			status:  StatusConfirmed,
+			to:      StatusCheckedIn,
+			now:     departure.Add(-DefaultCheckInOpens - time.Minute),
+			wantErr: ErrCheckInNotOpen,
+		},
+		{
+			name:    "3. Checks in as the flight departs",
+			status:  StatusConfirmed,
+			to:      StatusCheckedIn,
+			now:     departure,
+			wantErr: ErrCheckInClosed,
+		},
+		{
+			name:    "4. Pending booking checks in",
+			status:  StatusPending,
+			to:      StatusCheckedIn,
+			now:     departure.Add(-time.Hour),
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "5. Cancelled booking checks in",
+			status:  StatusCancelled,
+			deleted: true,
+			to:      StatusCheckedIn,
+			now:     departure.Add(-time.Hour),
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:   "6. Boards after checking in",
+			status: StatusCheckedIn,
+			to:     StatusBoarded,
+			now:    departure.Add(-time.Minute),
+		},
+		{
+			name:    "7. Boards without checking in",
+			status:  StatusConfirmed,
+			to:      StatusBoarded,
+			now:     departure.Add(-time.Minute),
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "8. Boards after departure",
+			status:  StatusCheckedIn,
+			to:      StatusBoarded,
+			now:     departure.Add(time.Minute),
+			wantErr: ErrCheckInClosed,
+		},
+		{
+			name:   "9. Flown once departed",
+			status: StatusBoarded,
+			to:     StatusFlown,
+			now:    departure,
+		},
+		{
+			name:    "10. Flown before departure",
+			status:  StatusBoarded,
+			to:      StatusFlown,
+			now:     departure.Add(-time.Minute),
+			wantErr: ErrNotDeparted,
+		},
+		{
+			name:    "11. Flown without boarding",
+			status:  StatusCheckedIn,
+			to:      StatusFlown,
+			now:     departure.Add(time.Hour),
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:   "12. Checked in but didn't board",
+			status: StatusCheckedIn,
+			to:     StatusNoShow,
+			now:    departure.Add(time.Hour),
+		},
+		{
+			name:    "13. No-show before departure",
+			status:  StatusConfirmed,
+			to:      StatusNoShow,
+			now:     departure.Add(-time.Hour),
+			wantErr: ErrNotDeparted,
+		},
+		{
+			name:    "14. Boarded passenger marked as a no-show",
+			status:  StatusBoarded,
+			to:      StatusNoShow,
+			now:     departure.Add(time.Hour),
+			wantErr: ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := Booking{Id: "booking-1", Status: tt.status, Deleted: tt.deleted, DepartureAt: &departure}
+
+			if err := CheckMove(booking, tt.to, DefaultCheckInOpens, tt.now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("wrong error, got %v want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanMove_Cancelled(t *testing.T) {
+	for status, want := range map[string]bool{
+		StatusPending:   true,
+		StatusConfirmed: true,
+		StatusCheckedIn: true,
+		StatusDisrupted: true,
+		StatusBoarded:   false,
+		StatusFlown:     false,
+		StatusNoShow:    false,
+		StatusCancelled: false,
+	} {
+		if got := CanMove(status, StatusCancelled); got != want {
+			t.Errorf("wrong result cancelling a %s booking, got %t want %t", status, got, want)
+		}
+	}
+}
+
+func TestParseCheckInOpens(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "1. Empty, default", s: "", want: DefaultCheckInOpens},
+		{name: "2. Hours", s: "48h", want: 48 * time.Hour},
+		{name: "3. Not a duration", s: "two days", wantErr: true},
+		{name: "4. Zero", s: "0h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCheckInOpens(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("wrong duration, got %s want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package bookings
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// ManifestPassenger is a passenger on a flight's manifest.
+type ManifestPassenger struct {
+	BookingId     string `json:"booking_id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Gender        string `json:"gender"`
+	AgeAtLaunch   int    `json:"age_at_launch"`
+	DestinationId string `json:"destination_id"`
+	Destination   string `json:"destination"`
+	// Status is whether the passenger is confirmed, has checked in or has boarded.
+	Status string `json:"status"`
+}
+
+// Manifest lists the passengers launching from a launchpad on a date, so ground operations know who to board.
+type Manifest struct {
+	LaunchPadId string              `json:"launch_pad_id"`
+	LaunchPad   string              `json:"launch_pad"`
+	LaunchDate  time.Time           `json:"launch_date"`
+	Passengers  []ManifestPassenger `json:"passengers"`
+}
+
+// NewManifest returns the manifest for flights from the launchpad on the launch date, built from the launchpad's
+// bookings. Only outbound bookings on the launch date that are confirmed, checked in or boarded are listed, since return flights land at the launchpad
+// rather than launch from it. Passengers are sorted by destination, then by name.
+func NewManifest(launchPad LaunchPad, launchDate time.Time, upcoming []Booking, destinations []Destination) Manifest {
+	names := map[string]string{}
+	for _, destination := range destinations {
+		names[destination.Id] = destination.Name
+	}
+
+	manifest := Manifest{
+		LaunchPadId: launchPad.Id,
+		LaunchPad:   launchPad.FullName,
+		LaunchDate:  launchDate,
+		Passengers:  []ManifestPassenger{},
+	}
+
+	for _, booking := range upcoming {
+		if booking.Deleted || !slices.Contains([]string{StatusConfirmed, StatusCheckedIn, StatusBoarded}, booking.Status) ||
+			booking.LaunchPadId != launchPad.Id ||
+			!booking.LaunchDate.Equal(launchDate) || booking.Direction == DirectionReturn {
+			continue
+		}
+
+		manifest.Passengers = append(manifest.Passengers, ManifestPassenger{
+			BookingId:     booking.Id,
+			FirstName:     booking.FirstName,
+			LastName:      booking.LastName,
+			Gender:        booking.Gender,
+			AgeAtLaunch:   AgeOn(booking.Birthday, launchDate),
+			DestinationId: booking.DestinationId,
+			Destination:   names[booking.DestinationId],
+			Status:        booking.Status,
+		})
+	}
+
+	slices.SortStableFunc(manifest.Passengers, func(a, b ManifestPassenger) int {
+		return cmp.Or(
+			cmp.Compare(a.Destination, b.Destination),
+			cmp.Compare(a.LastName, b.LastName),
+			cmp.Compare(a.FirstName, b.FirstName),
+		)
+	})
+
+	return manifest
+}
@@ -0,0 +1,64 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewManifest(t *testing.T) {
+	launchDate := time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC)
+	launchPad := LaunchPad{Id: "pad-1", FullName: "Cape Canaveral"}
+	destinations := []Destination{{Id: "moon", Name: "Moon"}, {Id: "mars", Name: "Mars"}}
+
+	booking := func(id, firstName, lastName, destinationId string, birthday time.Time) Booking {
+		return Booking{
+			Id:            id,
+			Customer:      Customer{FirstName: firstName, LastName: lastName, Gender: "Female", Birthday: birthday},
+			LaunchPadId:   launchPad.Id,
+			DestinationId: destinationId,
+			LaunchDate:    launchDate,
+			Direction:     DirectionOutbound,
+			Status:        StatusConfirmed,
+		}
+	}
+
+	pending := booking("pending", "Pat", "Pending", "moon", time.Time{})
+	pending.Status = StatusPending
+	deleted := booking("deleted", "Dee", "Deleted", "moon", time.Time{})
+	deleted.Deleted = true
+	returning := booking("return", "Rita", "Return", "moon", time.Time{})
+	returning.Direction = DirectionReturn
+	nextWeek := booking("next-week", "Nell", "Later", "moon", time.Time{})
+	nextWeek.LaunchDate = launchDate.AddDate(0, 0, 7)
+	checkedIn := booking("moon-1", "Ada", "Smith", "moon", time.Date(1990, 1, 14, 0, 0, 0, 0, time.UTC))
+	checkedIn.Status = StatusCheckedIn
+	noShow := booking("no-show", "Nora", "Show", "moon", time.Time{})
+	noShow.Status = StatusNoShow
+
+	upcoming := []Booking{
+		booking("moon-2", "Zoe", "Smith", "moon", time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC)),
+		checkedIn,
+		booking("mars-1", "Ian", "Thomson", "mars", time.Date(1980, 4, 12, 0, 0, 0, 0, time.UTC)),
+		pending, deleted, returning, nextWeek, noShow,
+	}
+
+	manifest := NewManifest(launchPad, launchDate, upcoming, destinations)
+
+	if manifest.LaunchPadId != "pad-1" || manifest.LaunchPad != "Cape Canaveral" || !manifest.LaunchDate.Equal(launchDate) {
+		t.Errorf("wrong flight, got %+v", manifest)
+	}
+
+	want := []ManifestPassenger{
+		{BookingId: "mars-1", FirstName: "Ian", LastName: "Thomson", Gender: "Female", AgeAtLaunch: 49, DestinationId: "mars", Destination: "Mars", Status: StatusConfirmed},
+		{BookingId: "moon-1", FirstName: "Ada", LastName: "Smith", Gender: "Female", AgeAtLaunch: 40, DestinationId: "moon", Destination: "Moon", Status: StatusCheckedIn},
+		{BookingId: "moon-2", FirstName: "Zoe", LastName: "Smith", Gender: "Female", AgeAtLaunch: 39, DestinationId: "moon", Destination: "Moon", Status: StatusConfirmed},
+	}
+	if len(manifest.Passengers) != len(want) {
+		t.Fatalf("wrong passengers, got %+v want %+v", manifest.Passengers, want)
+	}
+	for i := range want {
+		if manifest.Passengers[i] != want[i] {
+			t.Errorf("wrong passenger %d, got %+v want %+v", i, manifest.Passengers[i], want[i])
+		}
+	}
+}
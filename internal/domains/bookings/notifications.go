@@ -0,0 +1,207 @@
+package bookings
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// The notifications customers are sent about their bookings.
+const (
+	// NotifyConfirmed is sent once a booking has been paid for and confirmed.
+	NotifyConfirmed = "booking_confirmed"
+	// NotifyCancelled is sent once a booking has been cancelled, by the customer or an admin.
+	NotifyCancelled = "booking_cancelled"
+	// NotifyRescheduled is sent when a timetable change means the booking's flight is no longer scheduled.
+	NotifyRescheduled = "booking_rescheduled"
+	// NotifyDisrupted is sent when SpaceX schedule a launch from the launchpad during the booking's launch window.
+	NotifyDisrupted = "booking_disrupted"
+)
+
+// Notification is an email to a customer about their booking.
+type Notification struct {
+	Kind      string
+	BookingId string
+	To        string
+	Subject   string
+	Body      string
+}
+
+// Notifier sends notifications to customers, e.g. by email or to a log.
+type Notifier interface {
+	Notify(notification Notification) error
+}
+
+// notificationTemplate is the subject and body of a kind of notification, as text/template templates executed with
+// notificationData.
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// notificationData is what notification templates can use. From and To are where the flight leaves from and lands, the
+// launchpad and destination the other way round for a return flight. Times are in the launchpad's timezone.
+type notificationData struct {
+	Booking     Booking
+	LaunchPad   LaunchPad
+	Destination Destination
+	From        string
+	To          string
+	LaunchDate  string
+	Departs     string
+	Arrives     string
+	Price       string
+	Refund      string
+}
+
+// notificationSignOff ends every notification.
+const notificationSignOff = `
+Booking reference: {{.Booking.Id}}
+
+Space Tickets
+`
+
+var notificationTemplates = map[string]notificationTemplate{
+	NotifyConfirmed: newNotificationTemplate(
+		`Your flight to {{.To}} on {{.LaunchDate}} is confirmed`,
+		`Hi {{.Booking.FirstName}},
+
+Your seat from {{.From}} to {{.To}} is confirmed.
+
+Launch date: {{.LaunchDate}}
+Departs: {{.Departs}}
+{{- if .Arrives}}
+Estimated arrival: {{.Arrives}}
+{{- end}}
+{{- if .Price}}
+Price: {{.Price}}
+{{- end}}
+
+You can check in online before your flight, and your boarding pass will be scanned at the gate.
+{{- if .Booking.AccessToken}}
+
+Access token: {{.Booking.AccessToken}}
+
+Keep your access token private, you'll need it to check in and get your boarding pass.
+{{- end}}
+`),
+	NotifyCancelled: newNotificationTemplate(
+		`Your flight to {{.To}} on {{.LaunchDate}} has been cancelled`,
+		`Hi {{.Booking.FirstName}},
+
+Your seat from {{.From}} to {{.To}} on {{.LaunchDate}} has been cancelled.
+{{- if .Refund}}
+
+A refund of {{.Refund}} is on its way back to you.
+{{- end}}
+`),
+	NotifyRescheduled: newNotificationTemplate(
+		`Timetable change for your flight to {{.To}} on {{.LaunchDate}}`,
+		`Hi {{.Booking.FirstName}},
+
+The timetable at {{.LaunchPad.FullName}} has changed, and your flight to {{.To}} on {{.LaunchDate}} is no longer scheduled.
+
+Our team will be in touch to move you to another flight.
+`),
+	NotifyDisrupted: newNotificationTemplate(
+		`Your flight to {{.To}} on {{.LaunchDate}} has been disrupted`,
+		`Hi {{.Booking.FirstName}},
+
+SpaceX have scheduled a launch from {{.LaunchPad.FullName}} during your flight's launch window, so your flight to {{.To}} on {{.LaunchDate}} can't leave as planned.
+
+Our team will be in touch to move you to another flight.
+`),
+}
+
+// newNotificationTemplate parses a notification's subject and body, adding the sign off to the body.
+func newNotificationTemplate(subject, body string) notificationTemplate {
+	return notificationTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body + notificationSignOff)),
+	}
+}
+
+// NewNotification returns the notification of the kind about the booking, to the customer's email address, with the
+// launchpad's and destination's names filled in.
+func NewNotification(kind string, booking Booking, launchPad LaunchPad, destination Destination) (Notification, error) {
+	tmpl, ok := notificationTemplates[kind]
+	if !ok {
+		return Notification{}, fmt.Errorf("unrecognised notification %q", kind)
+	}
+
+	location, err := time.LoadLocation(launchPad.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	data := notificationData{
+		Booking:     booking,
+		LaunchPad:   launchPad,
+		Destination: destination,
+		From:        launchPad.FullName,
+		To:          destination.Name,
+		LaunchDate:  booking.LaunchDate.Format("Monday 2 January 2006"),
+		Departs:     "See launch window",
+	}
+	if booking.Direction == DirectionReturn {
+		data.From, data.To = destination.Name, launchPad.FullName
+	}
+	if booking.DepartureAt != nil {
+		data.Departs = booking.DepartureAt.In(location).Format("15:04 MST")
+	}
+	if booking.EstimatedArrival != nil {
+		data.Arrives = booking.EstimatedArrival.Format("Monday 2 January 2006")
+	}
+	if booking.Price != nil {
+		data.Price = formatAmount(*booking.Price, booking.Currency)
+	}
+	if booking.RefundAmount != nil && *booking.RefundAmount > 0 {
+		data.Refund = formatAmount(*booking.RefundAmount, booking.Currency)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return Notification{}, fmt.Errorf("error rendering %s subject: %w", kind, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return Notification{}, fmt.Errorf("error rendering %s body: %w", kind, err)
+	}
+
+	return Notification{Kind: kind, BookingId: booking.Id, To: booking.Email, Subject: subject.String(), Body: body.String()}, nil
+}
+
+// formatAmount formats an amount in the currency's minor unit, e.g. 25000000 USD is "250000.00 USD". Every currency is
+// assumed to have two decimal places.
+func formatAmount(amount int64, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", amount/100, amount%100, currency)
+}
+
+// SendNotification sends the notification of the kind about the booking. Nothing is sent if there's no notifier or the
+// customer didn't give an email address.
+func SendNotification(booker Booker, notifier Notifier, kind string, booking Booking) error {
+	if notifier == nil || len(booking.Email) == 0 {
+		return nil
+	}
+
+	launchPad, err := booker.GetLaunchPad(booking.LaunchPadId)
+	if err != nil {
+		return err
+	}
+
+	destination, err := booker.GetDestination(booking.DestinationId)
+	if err != nil {
+		return err
+	}
+
+	notification, err := NewNotification(kind, booking, *launchPad, *destination)
+	if err != nil {
+		return err
+	}
+
+	if err := notifier.Notify(notification); err != nil {
+		return fmt.Errorf("could not send %s to booking %s: %w", kind, booking.Id, err)
+	}
+
+	return nil
+}
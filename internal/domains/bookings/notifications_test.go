@@ -0,0 +1,107 @@
+package bookings
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewNotification(t *testing.T) {
+	launchPad := LaunchPad{Id: "launchpad-1", FullName: "Cape Canaveral", Timezone: "America/New_York"}
+	destination := Destination{Id: "destination-1", Name: "Pluto"}
+
+	departure := time.Date(2030, 1, 14, 19, 30, 0, 0, time.UTC)
+	price, refund := int64(25000000), int64(18750000)
+	booking := Booking{
+		Id:            "booking-1",
+		Customer:      Customer{FirstName: "Ian", LastName: "Thomson", Email: "ian@example.com"},
+		LaunchPadId:   launchPad.Id,
+		DestinationId: destination.Id,
+		LaunchDate:    time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC),
+		DepartureAt:   &departure,
+		Direction:     DirectionOutbound,
+		Price:         &price,
+		Currency:      "USD",
+		AccessToken:   "secret-token",
+	}
+
+	tests := []struct {
+		name        string
+		kind        string
+		booking     func() Booking
+		wantSubject string
+		wantBody    []string
+	}{
+		{
+			name:        "1. Confirmed",
+			kind:        NotifyConfirmed,
+			booking:     func() Booking { return booking },
+			wantSubject: "Your flight to Pluto on Monday 14 January 2030 is confirmed",
+			wantBody: []string{"Hi Ian,", "Your seat from Cape Canaveral to Pluto is confirmed.", "Departs: 14:30 EST",
+				"Price: 250000.00 USD", "Access token: secret-token", "Booking reference: booking-1"},
+		},
+		{
+			name: "2. Cancelled with a refund",
+			kind: NotifyCancelled,
+			booking: func() Booking {
+				cancelled := booking
+				cancelled.RefundAmount = &refund
+				return cancelled
+			},
+			wantSubject: "Your flight to Pluto on Monday 14 January 2030 has been cancelled",
+			wantBody:    []string{"Your seat from Cape Canaveral to Pluto on Monday 14 January 2030 has been cancelled.", "A refund of 187500.00 USD"},
+		},
+		{
+			name:        "3. Rescheduled",
+			kind:        NotifyRescheduled,
+			booking:     func() Booking { return booking },
+			wantSubject: "Timetable change for your flight to Pluto on Monday 14 January 2030",
+			wantBody:    []string{"The timetable at Cape Canaveral has changed"},
+		},
+		{
+			name:        "4. Disrupted",
+			kind:        NotifyDisrupted,
+			booking:     func() Booking { return booking },
+			wantSubject: "Your flight to Pluto on Monday 14 January 2030 has been disrupted",
+			wantBody:    []string{"SpaceX have scheduled a launch from Cape Canaveral"},
+		},
+		{
+			name: "5. Return flight",
+			kind: NotifyConfirmed,
+			booking: func() Booking {
+				returning := booking
+				returning.Direction = DirectionReturn
+				return returning
+			},
+			wantSubject: "Your flight to Cape Canaveral on Monday 14 January 2030 is confirmed",
+			wantBody:    []string{"Your seat from Pluto to Cape Canaveral is confirmed."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewNotification(tt.kind, tt.booking(), launchPad, destination)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Kind != tt.kind || got.To != "ian@example.com" || got.BookingId != "booking-1" {
+				t.Errorf("wrong notification, got %+v", got)
+			}
+
+			if got.Subject != tt.wantSubject {
+				t.Errorf("wrong subject, got %q want %q", got.Subject, tt.wantSubject)
+			}
+
+			for _, want := range tt.wantBody {
+				if !strings.Contains(got.Body, want) {
+					t.Errorf("body missing %q, got %s", want, got.Body)
+				}
+			}
+		})
+	}
+
+	if _, err := NewNotification("booking_lost", booking, launchPad, destination); err == nil {
+		t.Error("expected an error for an unrecognised notification")
+	}
+}
@@ -0,0 +1,85 @@
+package bookings
+
+import "errors"
+
+// The states a booking moves through. A booking is pending from when its seat is taken until it's paid for, and is
+// confirmed once the payment has been taken. If the payment is declined the booking's status is StatusPaymentFailed
+// and it's deleted, releasing the seat. Bookings are StatusCancelling while they're refunded and StatusCancelled once
+// they've been cancelled, and confirmed bookings whose flight has since clashed with a SpaceX launch are
+// StatusDisrupted. Confirmed bookings then move through check-in and boarding, see lifecycle.go.
+const (
+	// StatusPending bookings are waiting to be paid for. They count towards the flight's seats.
+	StatusPending = "pending"
+	// StatusPaid bookings have been paid for, but not yet confirmed.
+	StatusPaid = "paid"
+	// StatusConfirmed bookings have been paid for, or didn't need paying for, and the customer has a ticket.
+	StatusConfirmed = "confirmed"
+	// StatusPaymentFailed bookings had their payment declined and have been released.
+	StatusPaymentFailed = "payment_failed"
+	// StatusCancelling bookings have been claimed for cancellation and are being refunded. They can't check in or board.
+	StatusCancelling = "cancelling"
+	// StatusCancelled bookings have been cancelled, and refunded if they were paid for.
+	StatusCancelled = "cancelled"
+	// StatusDisrupted bookings were confirmed, but SpaceX have since scheduled a launch from the launchpad during the
+	// flight's launch window.
+	StatusDisrupted = "disrupted"
+)
+
+// ErrPaymentDeclined is returned by a PaymentGateway when the customer's payment is declined.
+var ErrPaymentDeclined = errors.New("payment declined")
+
+// ErrPaymentPending is returned when a PaymentGateway couldn't say whether the payment was taken, e.g. because it timed
+// out. The booking stays pending until the payment is settled.
+var ErrPaymentPending = errors.New("payment not confirmed")
+
+// Charge asks a payment gateway to take Amount, in Currency's minor unit. Reference identifies what's being paid for,
+// a booking id or the group id of a group booking, so the gateway can tell repeated charges apart.
+type Charge struct {
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+// Payment is a charge the gateway has taken.
+type Payment struct {
+	Id        string `json:"id"`
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+// Refund gives Amount of a payment back, in Currency's minor unit. Reference is the id of the booking being refunded,
+// and Id is the gateway's id for the refund once it's been made.
+type Refund struct {
+	Id        string `json:"id"`
+	PaymentId string `json:"payment_id"`
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+// NewCharge returns the charge for the bookings, which are paid for together. It returns false if none of them have
+// a price, so there's nothing to charge.
+func NewCharge(reference string, bookings []Booking) (Charge, bool) {
+	charge := Charge{Reference: reference}
+
+	for _, booking := range bookings {
+		if booking.Price == nil {
+			continue
+		}
+
+		charge.Amount += *booking.Price
+		charge.Currency = booking.Currency
+	}
+
+	return charge, len(charge.Currency) > 0
+}
+
+// PaymentGateway takes payments for bookings and refunds them.
+// Charge returns ErrPaymentDeclined if the payment is declined. Any other error means it's not known whether the
+// payment was taken, and charging the same Reference again returns the payment if it was, rather than taking another.
+// Refunds are the same, refunding the same Reference again returns the refund that was made rather than making another.
+type PaymentGateway interface {
+	Charge(charge Charge) (*Payment, error)
+	Refund(refund Refund) (*Refund, error)
+}
@@ -0,0 +1,36 @@
+package bookings
+
+import "testing"
+
+func TestNewCharge(t *testing.T) {
+	adult, child := int64(1000), int64(500)
+
+	tests := []struct {
+		name     string
+		bookings []Booking
+		want     Charge
+		wantOk   bool
+	}{
+		{
+			name:     "1. Prices of a group are added together",
+			bookings: []Booking{{Price: &adult, Currency: "USD"}, {Price: &child, Currency: "USD"}},
+			want:     Charge{Reference: "ref", Amount: 1500, Currency: "USD"},
+			wantOk:   true,
+		},
+		{
+			name:     "2. Bookings without a price have nothing to charge",
+			bookings: []Booking{{}, {}},
+			want:     Charge{Reference: "ref"},
+			wantOk:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NewCharge("ref", tt.bookings)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("wrong charge, got %+v, %v want %+v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
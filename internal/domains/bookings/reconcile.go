@@ -0,0 +1,145 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// spaceXConflictReason starts the review reason of bookings disrupted by a SpaceX launch.
+const spaceXConflictReason = "SpaceX are launching from the launchpad"
+
+// LaunchConflicts checks whether SpaceX are launching from one of their launchpads during a launch window.
+type LaunchConflicts interface {
+	HasLaunch(spaceXLaunchPadId string, window LaunchWindow) (bool, error)
+}
+
+// ReconcileBookings re-checks confirmed, checked in and disrupted outbound bookings for flights from today onwards
+// against SpaceX's launches, since SpaceX may have scheduled or moved a launch after the booking was made. Bookings
+// whose flight now overlaps with a launch are marked StatusDisrupted and flagged for review with the reason, and their
+// customers are sent NotifyDisrupted if notifier isn't nil. Disrupted bookings whose flight no longer overlaps with a
+// launch go back to confirmed, or checked in if the passenger had checked in, and their review flag is cleared, unless
+// they've since been flagged for another reason. Each flight is only checked once. A flight that can't be checked
+// doesn't stop the others, but the launchpad's other flights that day aren't checked until the next run, so an outage
+// is only reported once per launchpad and date. It returns how many bookings were disrupted and how many were restored.
+func ReconcileBookings(booker Booker, launches LaunchConflicts, notifier Notifier, now time.Time) (int, int, error) {
+	today := Today(now.UTC())
+
+	launchPads, err := booker.GetLaunchPads()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	disrupted, restored := 0, 0
+	var errs []error
+
+	// failed holds the launchpads and launch dates whose flights couldn't be checked.
+	failed := map[string]bool{}
+
+	for _, launchPad := range launchPads {
+		upcoming, err := booker.GetUpcoming(launchPad.Id, today)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// clashes holds the reason each of the launchpad's flights that's been checked is disrupted, or an empty string
+		// if it isn't.
+		clashes := map[string]string{}
+
+		for _, booking := range upcoming {
+			if booking.Direction != DirectionOutbound ||
+				(booking.Status != StatusConfirmed && booking.Status != StatusCheckedIn && booking.Status != StatusDisrupted) {
+				continue
+			}
+
+			date := booking.LaunchDate.Format(time.DateOnly)
+			if failed[launchPad.Id+" "+date] {
+				continue
+			}
+
+			flight := booking.DestinationId + " " + date
+			reason, checked := clashes[flight]
+			if !checked {
+				reason, err = checkConflict(booker, launches, launchPad, booking)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("could not check flight from launchpad %s on %s: %w", launchPad.Id,
+						date, err))
+					failed[launchPad.Id+" "+date] = true
+					continue
+				}
+				clashes[flight] = reason
+			}
+
+			if booking.Status == StatusDisrupted {
+				if len(reason) > 0 || !strings.HasPrefix(booking.ReviewReason, spaceXConflictReason) {
+					continue
+				}
+
+				status := StatusConfirmed
+				if booking.CheckedInAt != nil {
+					status = StatusCheckedIn
+				}
+
+				rowsAffected, err := booker.ClearDisruption(booking.Id, status)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				restored += int(rowsAffected)
+				continue
+			}
+
+			if len(reason) == 0 {
+				continue
+			}
+
+			err := booker.InTransaction(func(tx Booker) error {
+				if _, err := tx.SetStatus(booking.Id, StatusDisrupted, ""); err != nil {
+					return err
+				}
+
+				_, err := tx.FlagForReview(booking.Id, reason)
+				return err
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			disrupted++
+
+			booking.Status, booking.ReviewRequired, booking.ReviewReason = StatusDisrupted, true, reason
+			if err := SendNotification(booker, notifier, NotifyDisrupted, booking); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return disrupted, restored, errors.Join(errs...)
+}
+
+// checkConflict returns why the booking's flight is disrupted if SpaceX are launching from its launchpad during its
+// launch window, or an empty string if they aren't.
+func checkConflict(booker Booker, launches LaunchConflicts, launchPad LaunchPad, booking Booking) (string, error) {
+	entry, err := booker.FindScheduledFlight(booking.Direction, booking.LaunchPadId, booking.DestinationId, booking.LaunchDate)
+	if errors.Is(err, ErrNotFound) {
+		entry = nil
+	} else if err != nil {
+		return "", err
+	}
+
+	window, err := NewLaunchWindow(launchPad, entry, booking.LaunchDate)
+	if err != nil {
+		return "", err
+	}
+
+	clash, err := launches.HasLaunch(launchPad.SpaceXLaunchPadId, window)
+	if err != nil || !clash {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s between %s and %s", spaceXConflictReason, window.Opens.UTC().Format(time.RFC3339),
+		window.Closes.UTC().Format(time.RFC3339)), nil
+}
@@ -0,0 +1,193 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Why a booking was cancelled.
+const (
+	// CancelledByCustomer bookings are refunded according to the RefundPolicy.
+	CancelledByCustomer = "customer"
+	// CancelledForSpaceXConflict bookings were cancelled because SpaceX scheduled a launch from the launchpad during
+	// the flight's launch window after the booking was made. They're always refunded in full.
+	CancelledForSpaceXConflict = "spacex_conflict"
+)
+
+// ErrCancellationPending is returned when a booking has been claimed for cancellation but hasn't been cancelled yet,
+// e.g. because the payment gateway couldn't refund it. It's cancelled once its refund is made.
+var ErrCancellationPending = errors.New("cancellation not finished")
+
+// ValidateCancellationReason checks the reason is one of the reasons a booking can be cancelled for.
+func ValidateCancellationReason(reason string) error {
+	switch reason {
+	case CancelledByCustomer, CancelledForSpaceXConflict:
+		return nil
+	default:
+		return ValidationError{Reason: fmt.Sprintf("unrecognised reason %q, use %s or %s", reason, CancelledByCustomer, CancelledForSpaceXConflict)}
+	}
+}
+
+// DefaultRefundPolicy refunds 100% of the price more than 30 days before departure, 75% more than 7 days before, 50%
+// more than 24 hours before and nothing after that.
+var DefaultRefundPolicy = RefundPolicy{
+	{Before: 30 * 24 * time.Hour, Percent: 100},
+	{Before: 7 * 24 * time.Hour, Percent: 75},
+	{Before: 24 * time.Hour, Percent: 50},
+}
+
+// RefundTier refunds Percent of the price of bookings cancelled at least Before their departure.
+type RefundTier struct {
+	Before  time.Duration
+	Percent int
+}
+
+// RefundPolicy decides how much of the price is refunded when a booking is cancelled. Its tiers are ordered from the
+// longest time before departure to the shortest, and bookings cancelled later than the last tier get nothing back.
+type RefundPolicy []RefundTier
+
+// ParseRefundPolicy parses a policy written as comma separated tiers of a duration before departure and a percentage,
+// e.g. "720h:100,168h:75,24h:50". An empty string returns DefaultRefundPolicy.
+func ParseRefundPolicy(s string) (RefundPolicy, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return DefaultRefundPolicy, nil
+	}
+
+	var policy RefundPolicy
+	for _, tier := range strings.Split(s, ",") {
+		before, percent, ok := strings.Cut(strings.TrimSpace(tier), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid refund tier %q, use <duration>:<percent>", tier)
+		}
+
+		duration, err := time.ParseDuration(before)
+		if err != nil || duration < 0 {
+			return nil, fmt.Errorf("invalid refund tier duration %q", before)
+		}
+
+		value, err := strconv.Atoi(percent)
+		if err != nil || value < 0 || value > 100 {
+			return nil, fmt.Errorf("invalid refund tier percentage %q, must be between 0 and 100", percent)
+		}
+
+		policy = append(policy, RefundTier{Before: duration, Percent: value})
+	}
+
+	sort.Slice(policy, func(i, j int) bool { return policy[i].Before > policy[j].Before })
+
+	return policy, nil
+}
+
+// Percent returns the percentage of the price refunded for a booking departing at departure, cancelled now for the
+// reason.
+func (p RefundPolicy) Percent(reason string, departure, now time.Time) int {
+	if reason == CancelledForSpaceXConflict {
+		return 100
+	}
+
+	notice := departure.Sub(now)
+	for _, tier := range p {
+		if notice >= tier.Before {
+			return tier.Percent
+		}
+	}
+
+	return 0
+}
+
+// RefundFor returns the amount refunded for the booking, in its currency's minor unit, when it's cancelled now for the
+// reason. The time to launch is measured to the booking's departure, or the start of its launch date if it doesn't
+// have one. Refunds are rounded down.
+func (p RefundPolicy) RefundFor(booking Booking, reason string, now time.Time) int64 {
+	if booking.Price == nil {
+		return 0
+	}
+
+	return *booking.Price * int64(p.Percent(reason, booking.DepartsAt(), now)) / 100
+}
+
+// CancelBooking cancels the booking for the reason, refunding what the policy allows through the gateway, and returns
+// the cancelled booking. Bookings that didn't need paying for are cancelled without a refund. Deleted bookings return
+// ErrNotFound, and bookings that have boarded or missed their flight return ErrInvalidTransition. Bookings still waiting
+// for their payment to be settled return ErrPaymentPending, as they may have been paid for. Customers cancelling a
+// booking SpaceX disrupted are refunded in full, as it's cancelled for the conflict.
+// The booking is claimed as StatusCancelling in a transaction before it's refunded, so it's only refunded once however
+// many times it's cancelled at the same time. Claimed bookings return ErrCancellationPending until they're cancelled.
+func CancelBooking(booker Booker, gateway PaymentGateway, policy RefundPolicy, bookingId, reason string, now time.Time) (*Booking, error) {
+	var claimed *Booking
+	err := booker.InTransaction(func(tx Booker) error {
+		booking, err := tx.Get(bookingId)
+		if err != nil {
+			return err
+		}
+
+		if booking.Deleted {
+			return fmt.Errorf("booking %s has been deleted: %w", booking.Id, ErrNotFound)
+		}
+
+		if booking.Status == StatusCancelling {
+			return fmt.Errorf("booking %s is already being cancelled: %w", booking.Id, ErrCancellationPending)
+		}
+
+		if !CanMove(booking.Status, StatusCancelled) {
+			return fmt.Errorf("booking %s is %s, it can't be cancelled: %w", booking.Id, booking.Status, ErrInvalidTransition)
+		}
+
+		if booking.Status == StatusPending {
+			return fmt.Errorf("booking %s can't be cancelled until its payment is settled: %w", booking.Id, ErrPaymentPending)
+		}
+
+		if reason == CancelledByCustomer && booking.Status == StatusDisrupted {
+			reason = CancelledForSpaceXConflict
+		}
+
+		rowsAffected, err := tx.ClaimCancellation(booking.Id, booking.Status, reason)
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("booking %s is no longer %s: %w", booking.Id, booking.Status, ErrInvalidTransition)
+		}
+
+		claimed, err = tx.Get(booking.Id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FinishCancellation(booker, gateway, policy, *claimed, now)
+}
+
+// FinishCancellation refunds a booking claimed as StatusCancelling and marks it as cancelled, working the refund out as
+// if it was cancelled at cancelledAt. The booking's id is the refund's reference, so finishing a cancellation again
+// after the gateway or the store failed doesn't refund it twice. If either fails the booking is left claimed and
+// ErrCancellationPending is returned, so it can be finished later.
+func FinishCancellation(booker Booker, gateway PaymentGateway, policy RefundPolicy, booking Booking, cancelledAt time.Time) (*Booking, error) {
+	var refundAmount *int64
+	var refundId string
+
+	if booking.Price != nil && len(booking.PaymentId) > 0 {
+		amount := policy.RefundFor(booking, booking.CancellationReason, cancelledAt)
+		refundAmount = &amount
+
+		if amount > 0 {
+			refund, err := gateway.Refund(Refund{PaymentId: booking.PaymentId, Reference: booking.Id, Amount: amount, Currency: booking.Currency})
+			if err != nil {
+				return nil, fmt.Errorf("%w, could not refund booking %s: %w", ErrCancellationPending, booking.Id, err)
+			}
+			refundId = refund.Id
+		}
+	}
+
+	if _, err := booker.Cancel(booking.Id, booking.CancellationReason, refundAmount, refundId); err != nil {
+		return nil, fmt.Errorf("%w, booking %s refunded but not cancelled: %w", ErrCancellationPending, booking.Id, err)
+	}
+
+	return booker.Get(booking.Id)
+}
@@ -0,0 +1,106 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefundPolicy_RefundFor(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	price := int64(1001)
+
+	tests := []struct {
+		name      string
+		departure time.Time
+		reason    string
+		want      int64
+	}{
+		{
+			name:      "1. More than 30 days out, full refund",
+			departure: now.Add(31 * 24 * time.Hour),
+			reason:    CancelledByCustomer,
+			want:      1001,
+		},
+		{
+			name:      "2. Between 7 and 30 days out, 75% refund rounded down",
+			departure: now.Add(10 * 24 * time.Hour),
+			reason:    CancelledByCustomer,
+			want:      750,
+		},
+		{
+			name:      "3. Within 7 days, 50% refund",
+			departure: now.Add(2 * 24 * time.Hour),
+			reason:    CancelledByCustomer,
+			want:      500,
+		},
+		{
+			name:      "4. Within 24 hours, no refund",
+			departure: now.Add(time.Hour),
+			reason:    CancelledByCustomer,
+			want:      0,
+		},
+		{
+			name:      "5. SpaceX conflict within 24 hours, full refund",
+			departure: now.Add(time.Hour),
+			reason:    CancelledForSpaceXConflict,
+			want:      1001,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := Booking{Price: &price, DepartureAt: &tt.departure}
+			if got := DefaultRefundPolicy.RefundFor(booking, tt.reason, now); got != tt.want {
+				t.Errorf("wrong refund, got %d want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRefundPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    RefundPolicy
+		wantErr bool
+	}{
+		{
+			name:   "1. Empty policy, default returned",
+			policy: "",
+			want:   DefaultRefundPolicy,
+		},
+		{
+			name:   "2. Tiers sorted from the longest notice",
+			policy: "48h:25, 336h:100",
+			want:   RefundPolicy{{Before: 336 * time.Hour, Percent: 100}, {Before: 48 * time.Hour, Percent: 25}},
+		},
+		{
+			name:    "3. Percentage over 100",
+			policy:  "24h:150",
+			wantErr: true,
+		},
+		{
+			name:    "4. Missing percentage",
+			policy:  "24h",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRefundPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("wrong policy, got %+v want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("wrong policy, got %+v want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
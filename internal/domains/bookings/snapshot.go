@@ -0,0 +1,71 @@
+package bookings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExternalLaunch is a SpaceX launch loaded from a snapshot of the SpaceX API's /v4/launches endpoint.
+type ExternalLaunch struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// LaunchPadId is the SpaceX launchpad id, not ours.
+	LaunchPadId string    `json:"launchpad"`
+	DateUTC     time.Time `json:"date_utc"`
+}
+
+// ExternalLaunches defines the methods an object needs to implement to store a snapshot of SpaceX's launches, so
+// bookings can be checked against SpaceX without the SpaceX API. ReplaceExternalLaunches deletes the launches already
+// stored before storing the new ones, so it should be run in a transaction. CountExternalLaunches counts the launches
+// from the SpaceX launchpad at or after from and before to.
+type ExternalLaunches interface {
+	ReplaceExternalLaunches(launches []ExternalLaunch) (int64, error)
+	CountExternalLaunches(spaceXLaunchPadId string, from, to time.Time) (int, error)
+}
+
+// ParseLaunchSnapshot reads a SpaceX launches JSON export, the array returned by the /v4/launches endpoint. Fields
+// other than the id, name, launchpad and date are ignored. Every launch must have an id, launchpad and date.
+func ParseLaunchSnapshot(r io.Reader) ([]ExternalLaunch, error) {
+	var launches []ExternalLaunch
+	if err := json.NewDecoder(r).Decode(&launches); err != nil {
+		return nil, fmt.Errorf("could not parse launch snapshot: %w", err)
+	}
+
+	for i, launch := range launches {
+		if len(launch.Id) == 0 || len(launch.LaunchPadId) == 0 || launch.DateUTC.IsZero() {
+			return nil, fmt.Errorf("launch %d in snapshot needs an id, launchpad and date_utc", i)
+		}
+	}
+
+	return launches, nil
+}
+
+// ImportLaunchSnapshot replaces the stored snapshot of SpaceX's launches with launches in one transaction, so
+// conflicts are never checked against a half-imported snapshot. It returns how many launches were imported.
+func ImportLaunchSnapshot(booker Booker, launches []ExternalLaunch) (int64, error) {
+	var imported int64
+	err := booker.InTransaction(func(tx Booker) error {
+		var err error
+		imported, err = tx.ReplaceExternalLaunches(launches)
+		return err
+	})
+
+	return imported, err
+}
+
+// LaunchSnapshot checks for SpaceX launches against the stored snapshot of SpaceX's launches instead of the SpaceX API.
+type LaunchSnapshot struct {
+	Launches ExternalLaunches
+}
+
+// HasLaunch returns true if the snapshot has a launch from the SpaceX launchpad during the launch window.
+func (s LaunchSnapshot) HasLaunch(spaceXLaunchPadId string, window LaunchWindow) (bool, error) {
+	count, err := s.Launches.CountExternalLaunches(spaceXLaunchPadId, window.Opens.UTC(), window.Closes.UTC())
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,76 @@
+package bookings
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLaunchSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "1. SpaceX launches export, extra fields ignored",
+			json: `[{"fairings":null,"id":"5eb87cdeffd86e000604b330","name":"COTS 1","launchpad":"5e9e4501f509094ba4566f84",` +
+				`"date_utc":"2010-12-08T15:43:00.000Z","date_precision":"hour","upcoming":false}]`,
+			want: 1,
+		},
+		{name: "2. Empty export", json: `[]`, want: 0},
+		{name: "3. Launch without a launchpad", json: `[{"id":"1","name":"x","launchpad":null,"date_utc":"2010-12-08T15:43:00.000Z"}]`, wantErr: true},
+		{name: "4. Not an array", json: `{"docs":[]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLaunchSnapshot(strings.NewReader(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got %v, want error %t", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("wrong number of launches, got %d want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+// externalLaunchesMock records the range it was asked to count launches in.
+type externalLaunchesMock struct {
+	count    int
+	from, to time.Time
+}
+
+func (m *externalLaunchesMock) ReplaceExternalLaunches(launches []ExternalLaunch) (int64, error) {
+	return int64(len(launches)), nil
+}
+
+func (m *externalLaunchesMock) CountExternalLaunches(spaceXLaunchPadId string, from, to time.Time) (int, error) {
+	m.from, m.to = from, to
+	return m.count, nil
+}
+
+func TestLaunchSnapshot_HasLaunch(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	opens := time.Date(2010, 12, 8, 9, 0, 0, 0, newYork)
+	window := LaunchWindow{Opens: opens, Closes: opens.Add(12 * time.Hour)}
+
+	launches := &externalLaunchesMock{count: 1}
+	clash, err := LaunchSnapshot{Launches: launches}.HasLaunch("pad", window)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !clash {
+		t.Errorf("expected a clash")
+	}
+	if launches.from.Location() != time.UTC || !launches.from.Equal(window.Opens) || !launches.to.Equal(window.Closes) {
+		t.Errorf("wrong range, got %s to %s", launches.from, launches.to)
+	}
+
+	launches.count = 0
+	if clash, _ := (LaunchSnapshot{Launches: launches}).HasLaunch("pad", window); clash {
+		t.Errorf("unexpected clash")
+	}
+}
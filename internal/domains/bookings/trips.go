@@ -0,0 +1,87 @@
+package bookings
+
+import (
+	"errors"
+	"time"
+)
+
+// The directions a flight can go in.
+const (
+	// DirectionOutbound is a flight from a launchpad on Earth to a destination.
+	DirectionOutbound = "outbound"
+	// DirectionReturn is a flight from a destination back to Earth, landing at a launchpad.
+	DirectionReturn = "return"
+)
+
+var (
+	// ErrReturnScheduleInvalid is returned when there's no return flight from the destination to the launchpad on the
+	// requested day.
+	ErrReturnScheduleInvalid = errors.New("no return flight from the destination lands at the launchpad on the requested day")
+	// ErrReturnBeforeArrival is returned when a return flight would leave the destination before the outbound flight
+	// gets there.
+	ErrReturnBeforeArrival = errors.New("return flight departs before the outbound flight arrives")
+	// ErrReturnAlreadyBooked is returned when the outbound booking already has a return flight.
+	ErrReturnAlreadyBooked = errors.New("a return flight has already been booked for this booking")
+	// ErrNotOutbound is returned when booking a return flight for a booking that is itself a return flight.
+	ErrNotOutbound = errors.New("return flights can only be booked for outbound flights")
+)
+
+// TravelTime overrides the destination's travel time for flights between one launchpad and the destination.
+type TravelTime struct {
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	TravelDays    int       `json:"travel_days"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate checks the travel time names a launchpad and destination and isn't negative.
+func (t TravelTime) Validate() error {
+	if len(t.LaunchPadId) == 0 || len(t.DestinationId) == 0 {
+		return ValidationError{Reason: "launch_pad_id and destination_id are required"}
+	}
+
+	if t.TravelDays < 0 {
+		return ValidationError{Reason: "travel_days must not be negative"}
+	}
+
+	return nil
+}
+
+// EstimateArrival returns the date a flight leaving on the launch date and taking travelDays arrives.
+func EstimateArrival(launchDate time.Time, travelDays int) time.Time {
+	return launchDate.AddDate(0, 0, travelDays)
+}
+
+// NewReturnBooking returns a booking for the outbound booking's customer to fly back from its destination on the
+// launch date, landing at the launchpad. An empty launchPadId lands at the launchpad the outbound flight left from.
+// The return flight can't leave before the outbound flight is expected to arrive. It shares the outbound booking's
+// access token, so the customer uses the same token for both flights.
+func NewReturnBooking(outbound Booking, launchPadId string, launchDate time.Time) (Booking, error) {
+	if outbound.Direction == DirectionReturn {
+		return Booking{}, ErrNotOutbound
+	}
+
+	arrival := outbound.LaunchDate
+	if outbound.EstimatedArrival != nil {
+		arrival = *outbound.EstimatedArrival
+	}
+
+	if launchDate.Before(arrival) {
+		return Booking{}, ErrReturnBeforeArrival
+	}
+
+	if len(launchPadId) == 0 {
+		launchPadId = outbound.LaunchPadId
+	}
+
+	return Booking{
+		Customer:          outbound.Customer,
+		LaunchPadId:       launchPadId,
+		DestinationId:     outbound.DestinationId,
+		LaunchDate:        launchDate,
+		Direction:         DirectionReturn,
+		OutboundBookingId: outbound.Id,
+		AccessToken:       outbound.AccessToken,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewReturnBooking(t *testing.T) {
+	launchDate, _ := time.Parse(time.DateOnly, "2024-01-01")
+	arrival := EstimateArrival(launchDate, 3)
+
+	outbound := Booking{
+		Id:               "outbound",
+		Customer:         Customer{FirstName: "Ian", LastName: "Thomson"},
+		LaunchPadId:      "pad",
+		DestinationId:    "moon",
+		LaunchDate:       launchDate,
+		EstimatedArrival: &arrival,
+		Direction:        DirectionOutbound,
+	}
+
+	tests := []struct {
+		name            string
+		outbound        Booking
+		launchPadId     string
+		launchDate      time.Time
+		wantLaunchPadId string
+		wantErr         error
+	}{
+		{
+			name:            "1. Returns to the launchpad the outbound flight left from",
+			outbound:        outbound,
+			launchDate:      arrival,
+			wantLaunchPadId: "pad",
+		},
+		{
+			name:            "2. Lands at a different launchpad",
+			outbound:        outbound,
+			launchPadId:     "other",
+			launchDate:      arrival.AddDate(0, 0, 7),
+			wantLaunchPadId: "other",
+		},
+		{
+			name:       "3. Leaves before the outbound flight arrives",
+			outbound:   outbound,
+			launchDate: arrival.AddDate(0, 0, -1),
+			wantErr:    ErrReturnBeforeArrival,
+		},
+		{
+			name:       "4. Booking is already a return flight",
+			outbound:   Booking{Id: "return", LaunchDate: launchDate, Direction: DirectionReturn},
+			launchDate: arrival,
+			wantErr:    ErrNotOutbound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewReturnBooking(tt.outbound, tt.launchPadId, tt.launchDate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("wrong error, got %v want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.Direction != DirectionReturn || got.OutboundBookingId != tt.outbound.Id {
+				t.Errorf("return booking not linked to the outbound booking, got %+v", got)
+			}
+			if got.LaunchPadId != tt.wantLaunchPadId || got.DestinationId != "moon" || got.FirstName != "Ian" {
+				t.Errorf("wrong return booking, got %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package bookings
+
+import (
+	"errors"
+	"time"
+)
+
+// Why a customer joined a waitlist.
+const (
+	// WaitlistFlightFull entries were turned away because the flight had no seats left.
+	WaitlistFlightFull = "flight_full"
+	// WaitlistSpaceXConflict entries were turned away because SpaceX were launching from the launchpad during the
+	// flight's launch window.
+	WaitlistSpaceXConflict = "spacex_conflict"
+)
+
+// The states a waitlist entry moves through.
+const (
+	// WaitlistWaiting entries are waiting for a seat.
+	WaitlistWaiting = "waiting"
+	// WaitlistPromoted entries have been booked on their flight. Their BookingId is the booking.
+	WaitlistPromoted = "promoted"
+	// WaitlistPaymentFailed entries were given a seat but couldn't be charged for it, so the seat was released.
+	WaitlistPaymentFailed = "payment_failed"
+	// WaitlistFlightUnavailable entries were waiting for a flight that no longer goes ahead, e.g. because it's been
+	// taken out of the schedule or its launchpad has been retired, so they'll never be given a seat.
+	WaitlistFlightUnavailable = "flight_unavailable"
+)
+
+// ErrSpaceXConflict is returned when SpaceX are launching from the launchpad during the flight's launch window.
+var ErrSpaceXConflict = errors.New("flight overlaps with a SpaceX launch")
+
+// WaitlistEntry is a customer waiting for a seat on a flight that was full, or that overlapped with a SpaceX launch,
+// when they tried to book it. Entries are promoted to bookings in the order they joined the waitlist.
+type WaitlistEntry struct {
+	Id string `json:"id"`
+	Customer
+	LaunchPadId   string    `json:"launch_pad_id"`
+	DestinationId string    `json:"destination_id"`
+	LaunchDate    time.Time `json:"launch_date"`
+	Direction     string    `json:"direction"`
+	PromoCode     string    `json:"promo_code"`
+	Reason        string    `json:"reason"`
+	Status        string    `json:"status"`
+	BookingId     string    `json:"booking_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// WaitlistReason returns the reason to waitlist a booking that was rejected with err, or false if the rejection can't
+// be waited out, e.g. because the flight isn't scheduled.
+func WaitlistReason(err error) (string, bool) {
+	switch {
+	case errors.Is(err, ErrFlightFull):
+		return WaitlistFlightFull, true
+	case errors.Is(err, ErrSpaceXConflict):
+		return WaitlistSpaceXConflict, true
+	default:
+		return "", false
+	}
+}
+
+// FlightUnavailable returns true if a booking was rejected with err because its flight no longer goes ahead, rather
+// than for something that can be waited out.
+func FlightUnavailable(err error) bool {
+	for _, unavailable := range []error{ErrLaunchScheduleInvalid, ErrReturnScheduleInvalid, ErrFlightCancelled,
+		ErrLaunchPadClosed, ErrDestinationUnavailable, ErrLaunchPadRetired, ErrDestinationRetired} {
+		if errors.Is(err, unavailable) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewWaitlistEntry returns a waiting entry for the customer and flight of a booking that was rejected for the reason.
+func NewWaitlistEntry(booking Booking, reason string) WaitlistEntry {
+	return WaitlistEntry{
+		Customer:      booking.Customer,
+		LaunchPadId:   booking.LaunchPadId,
+		DestinationId: booking.DestinationId,
+		LaunchDate:    booking.LaunchDate,
+		Direction:     booking.Direction,
+		PromoCode:     booking.PromoCode,
+		Reason:        reason,
+		Status:        WaitlistWaiting,
+	}
+}
+
+// Booking returns a booking for the entry's customer on its flight.
+func (e WaitlistEntry) Booking() Booking {
+	return Booking{
+		Customer:      e.Customer,
+		LaunchPadId:   e.LaunchPadId,
+		DestinationId: e.DestinationId,
+		LaunchDate:    e.LaunchDate,
+		Direction:     e.Direction,
+		PromoCode:     e.PromoCode,
+	}
+}
+
+// ForFlight returns true if the entry is waiting for the booking's flight.
+func (e WaitlistEntry) ForFlight(booking Booking) bool {
+	return e.Direction == booking.Direction && e.LaunchPadId == booking.LaunchPadId && e.DestinationId == booking.DestinationId &&
+		e.LaunchDate.Equal(booking.LaunchDate)
+}
+
+// Waitlist defines the methods an object needs to implement to store waitlists.
+// GetWaitlist returns the entries still waiting for flights launching on or after from, oldest first. SetWaitlistStatus
+// leaves the entry's booking id as it is when bookingId is empty.
+type Waitlist interface {
+	GetWaitlist(from time.Time) ([]WaitlistEntry, error)
+	GetWaitlistEntry(id string) (*WaitlistEntry, error)
+	CreateWaitlistEntry(entry WaitlistEntry) (*WaitlistEntry, error)
+	SetWaitlistStatus(id, status, bookingId string) (int64, error)
+}
@@ -0,0 +1,78 @@
+package bookings
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWaitlistReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantOk     bool
+	}{
+		{name: "1. Flight full", err: fmt.Errorf("booking: %w", ErrFlightFull), wantReason: WaitlistFlightFull, wantOk: true},
+		{name: "2. SpaceX conflict", err: ErrSpaceXConflict, wantReason: WaitlistSpaceXConflict, wantOk: true},
+		{name: "3. Flight not scheduled", err: ErrLaunchScheduleInvalid},
+		{name: "4. Booked", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := WaitlistReason(tt.err)
+			if reason != tt.wantReason || ok != tt.wantOk {
+				t.Errorf("wrong reason, got %q, %v want %q, %v", reason, ok, tt.wantReason, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFlightUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "1. Flight not scheduled", err: fmt.Errorf("booking: %w", ErrLaunchScheduleInvalid), want: true},
+		{name: "2. Flight cancelled", err: ErrFlightCancelled, want: true},
+		{name: "3. Launchpad retired", err: ErrLaunchPadRetired, want: true},
+		{name: "4. Flight full", err: ErrFlightFull},
+		{name: "5. SpaceX conflict", err: ErrSpaceXConflict},
+		{name: "6. Booked", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FlightUnavailable(tt.err); got != tt.want {
+				t.Errorf("wrong result, got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitlistEntry_ForFlight(t *testing.T) {
+	launchDate, _ := time.Parse(time.DateOnly, "2024-01-08")
+	booking := Booking{
+		Customer:      Customer{FirstName: "Ian"},
+		LaunchPadId:   "pad",
+		DestinationId: "moon",
+		LaunchDate:    launchDate,
+		Direction:     DirectionOutbound,
+		PromoCode:     "SPRING",
+	}
+
+	entry := NewWaitlistEntry(booking, WaitlistFlightFull)
+	if entry.Status != WaitlistWaiting || entry.Booking() != booking {
+		t.Errorf("wrong entry, got %+v", entry)
+	}
+
+	if !entry.ForFlight(Booking{LaunchPadId: "pad", DestinationId: "moon", LaunchDate: launchDate, Direction: DirectionOutbound}) {
+		t.Errorf("entry not waiting for its own flight")
+	}
+
+	if entry.ForFlight(Booking{LaunchPadId: "pad", DestinationId: "moon", LaunchDate: launchDate.AddDate(0, 0, 7), Direction: DirectionOutbound}) {
+		t.Errorf("entry waiting for the following week's flight")
+	}
+}
@@ -9,6 +9,8 @@ import (
 )
 
 const (
+	storageBackendEnvVar          = "STORAGE_BACKEND"
+	sqlitePathEnvVar              = "SQLITE_PATH"
 	dBUsernameEnvVar              = "DB_USERNAME"
 	dbPasswordEnvVar              = "DB_PASSWORD"
 	dbNameEnvVar                  = "DB_NAME"
@@ -29,9 +31,46 @@ const (
 	tlsHandshakeTimeoutSecsEnvVar = "TLS_HANDSHAKE_TIMEOUT_SECS"
 	disableKeepAlivesEnvVar       = "DISABLE_KEEP_ALIVES"
 	spaceXAPIEndpointEnvVar       = "SPACEX_API_ENDPOINT"
+	spaceXSnapshotPathEnvVar      = "SPACEX_SNAPSHOT_PATH"
+	adminAPIKeyEnvVar             = "ADMIN_API_KEY"
+	paymentGatewayEnvVar          = "PAYMENT_GATEWAY"
+	paymentGatewayEndpointEnvVar  = "PAYMENT_GATEWAY_ENDPOINT"
+	refundPolicyEnvVar            = "REFUND_POLICY"
+	boardingPassKeyEnvVar         = "BOARDING_PASS_KEY"
+	checkInOpensEnvVar            = "CHECK_IN_OPENS"
+	notifierEnvVar                = "NOTIFIER"
+	smtpAddrEnvVar                = "SMTP_ADDR"
+	smtpFromEnvVar                = "SMTP_FROM"
+	smtpUsernameEnvVar            = "SMTP_USERNAME"
+	smtpPasswordEnvVar            = "SMTP_PASSWORD"
+)
+
+const (
+	// StorageBackendPostgres stores bookings in Postgres.
+	StorageBackendPostgres = "postgres"
+	// StorageBackendMemory stores bookings in memory, so the API can run without a database.
+	StorageBackendMemory = "memory"
+	// StorageBackendSQLite stores bookings in an embedded SQLite database file.
+	StorageBackendSQLite = "sqlite"
+)
+
+const (
+	// PaymentGatewayFake takes payments in process, so the API can run without a payment provider.
+	PaymentGatewayFake = "fake"
+	// PaymentGatewayHTTP takes payments through a payment provider's API, or a local stand-in for it.
+	PaymentGatewayHTTP = "http"
+)
+
+const (
+	// NotifierLog writes customer notifications to the log, so the API can run without a mail server.
+	NotifierLog = "log"
+	// NotifierSMTP emails customer notifications through a mail server, or a local SMTP sink.
+	NotifierSMTP = "smtp"
 )
 
 type Config struct {
+	StorageBackend          string
+	SQLitePath              string
 	DBUsername              string
 	DBPassword              string
 	DBName                  string
@@ -52,53 +91,42 @@ type Config struct {
 	TLSHandshakeTimeoutSecs int
 	DisableKeepAlives       bool
 	SpaceXAPIEndpoint       string
+	SpaceXSnapshotPath      string
+	AdminAPIKey             string
+	PaymentGateway          string
+	PaymentGatewayEndpoint  string
+	RefundPolicy            string
+	BoardingPassKey         string
+	CheckInOpens            string
+	Notifier                string
+	SMTPAddr                string
+	SMTPFrom                string
+	SMTPUsername            string
+	SMTPPassword            string
 }
 
 // Get retrieves config from environment variables.
 func Get() (Config, error) {
-	username := os.Getenv(dBUsernameEnvVar)
-	if len(username) == 0 {
-		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", dBUsernameEnvVar)
+	storageBackend := os.Getenv(storageBackendEnvVar)
+	if len(storageBackend) == 0 {
+		storageBackend = StorageBackendPostgres
 	}
 
-	password := os.Getenv(dbPasswordEnvVar)
-	if len(password) == 0 {
-		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", dbPasswordEnvVar)
-	}
-
-	name := os.Getenv(dbNameEnvVar)
-	if len(name) == 0 {
-		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", dbNameEnvVar)
-	}
-
-	host := os.Getenv(dbHostEnvVar)
-	if len(host) == 0 {
-		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", dbHostEnvVar)
-	}
-
-	openConns, err := getEnvVarInt(dbMaxOpenConnsEnvVar)
-	if err != nil {
-		return Config{}, err
-	}
-
-	idleConns, err := getEnvVarInt(dbMaxIdleConnsEnvVar)
-	if err != nil {
-		return Config{}, err
-	}
-
-	connLifetime, err := getEnvVarInt(dbConnMaxLifetimeSecsEnvVar)
-	if err != nil {
-		return Config{}, err
-	}
-
-	retries, err := getEnvVarInt(dbConnRetriesEnvVar)
-	if err != nil {
-		return Config{}, err
-	}
-
-	interval, err := getEnvVarInt(dbRetryIntervalEnvVar)
-	if err != nil {
-		return Config{}, err
+	cfg := Config{StorageBackend: storageBackend}
+
+	switch storageBackend {
+	case StorageBackendPostgres:
+		if err := getDBConfig(&cfg); err != nil {
+			return Config{}, err
+		}
+	case StorageBackendSQLite:
+		cfg.SQLitePath = os.Getenv(sqlitePathEnvVar)
+		if len(cfg.SQLitePath) == 0 {
+			return Config{}, fmt.Errorf("unrecognised value for environment variable %s", sqlitePathEnvVar)
+		}
+	case StorageBackendMemory:
+	default:
+		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", storageBackendEnvVar)
 	}
 
 	port := os.Getenv(apiPortEnvVar)
@@ -157,39 +185,155 @@ func Get() (Config, error) {
 		return Config{}, err
 	}
 
+	// A snapshot of SpaceX's launches is optional, but the SpaceX API is only needed without one.
+	spaceXSnapshotPath := os.Getenv(spaceXSnapshotPathEnvVar)
 	spaceXAPIEndpoint := os.Getenv(spaceXAPIEndpointEnvVar)
-	if len(spaceXAPIEndpoint) == 0 {
+	if len(spaceXAPIEndpoint) == 0 && len(spaceXSnapshotPath) == 0 {
 		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", spaceXAPIEndpointEnvVar)
 	}
 
-	cfg := Config{
-		DBUsername:              username,
-		DBPassword:              password,
-		DBName:                  name,
-		DBHost:                  host,
-		DBMaxOpenConns:          openConns,
-		DBMaxIdleConns:          idleConns,
-		DBConnMaxLifetimeSecs:   connLifetime,
-		DBConnRetries:           retries,
-		DBConnRetryIntervalSecs: interval,
-		APIPort:                 port,
-		SwaggerPort:             swagPort,
-		HTTPTimeout:             httpTimeout,
-		MaxIdleConns:            maxIdleConns,
-		MaxConnsPerHost:         maxConnsPerHost,
-		IdleConnTimeoutSecs:     idleConnTimeoutSecs,
-		DialerTimeoutSecs:       dialerTimeoutSecs,
-		DialerKeepAliveSecs:     dialerKeepAliveSecs,
-		TLSHandshakeTimeoutSecs: tlsHandshakeTimeoutSecs,
-		DisableKeepAlives:       disableKeepAlives,
-		SpaceXAPIEndpoint:       spaceXAPIEndpoint,
+	paymentGateway := os.Getenv(paymentGatewayEnvVar)
+	if len(paymentGateway) == 0 {
+		paymentGateway = PaymentGatewayFake
+	}
+
+	var paymentGatewayEndpoint string
+	switch paymentGateway {
+	case PaymentGatewayFake:
+	case PaymentGatewayHTTP:
+		paymentGatewayEndpoint = os.Getenv(paymentGatewayEndpointEnvVar)
+		if len(paymentGatewayEndpoint) == 0 {
+			return Config{}, fmt.Errorf("unrecognised value for environment variable %s", paymentGatewayEndpointEnvVar)
+		}
+	default:
+		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", paymentGatewayEnvVar)
+	}
+
+	notifier := os.Getenv(notifierEnvVar)
+	if len(notifier) == 0 {
+		notifier = NotifierLog
+	}
+
+	switch notifier {
+	case NotifierLog:
+	case NotifierSMTP:
+		if err := getSMTPConfig(&cfg); err != nil {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("unrecognised value for environment variable %s", notifierEnvVar)
 	}
 
+	cfg.APIPort = port
+	cfg.SwaggerPort = swagPort
+	cfg.HTTPTimeout = httpTimeout
+	cfg.MaxIdleConns = maxIdleConns
+	cfg.MaxConnsPerHost = maxConnsPerHost
+	cfg.IdleConnTimeoutSecs = idleConnTimeoutSecs
+	cfg.DialerTimeoutSecs = dialerTimeoutSecs
+	cfg.DialerKeepAliveSecs = dialerKeepAliveSecs
+	cfg.TLSHandshakeTimeoutSecs = tlsHandshakeTimeoutSecs
+	cfg.DisableKeepAlives = disableKeepAlives
+	cfg.SpaceXAPIEndpoint = spaceXAPIEndpoint
+	cfg.SpaceXSnapshotPath = spaceXSnapshotPath
+	// The admin API is optional, it's disabled when no key is set.
+	cfg.AdminAPIKey = os.Getenv(adminAPIKeyEnvVar)
+	cfg.PaymentGateway = paymentGateway
+	cfg.PaymentGatewayEndpoint = paymentGatewayEndpoint
+	// The refund policy is optional, the default policy is used when it's not set.
+	cfg.RefundPolicy = os.Getenv(refundPolicyEnvVar)
+	// The boarding pass key is optional, a temporary key is generated when it's not set.
+	cfg.BoardingPassKey = os.Getenv(boardingPassKeyEnvVar)
+	// How long before departure check-in opens is optional, it's 24 hours when it's not set.
+	cfg.CheckInOpens = os.Getenv(checkInOpensEnvVar)
+	cfg.Notifier = notifier
+
 	log.Println("Config loaded from environment variables")
 
 	return cfg, nil
 }
 
+// getSMTPConfig retrieves the mail server config from environment variables. The username and password are optional,
+// as a local SMTP sink doesn't need them.
+func getSMTPConfig(cfg *Config) error {
+	addr := os.Getenv(smtpAddrEnvVar)
+	if len(addr) == 0 {
+		return fmt.Errorf("unrecognised value for environment variable %s", smtpAddrEnvVar)
+	}
+
+	from := os.Getenv(smtpFromEnvVar)
+	if len(from) == 0 {
+		return fmt.Errorf("unrecognised value for environment variable %s", smtpFromEnvVar)
+	}
+
+	cfg.SMTPAddr = addr
+	cfg.SMTPFrom = from
+	cfg.SMTPUsername = os.Getenv(smtpUsernameEnvVar)
+	cfg.SMTPPassword = os.Getenv(smtpPasswordEnvVar)
+
+	return nil
+}
+
+// getDBConfig retrieves the database config from environment variables.
+func getDBConfig(cfg *Config) error {
+	username := os.Getenv(dBUsernameEnvVar)
+	if len(username) == 0 {
+		return fmt.Errorf("unrecognised value for environment variable %s", dBUsernameEnvVar)
+	}
+
+	password := os.Getenv(dbPasswordEnvVar)
+	if len(password) == 0 {
+		return fmt.Errorf("unrecognised value for environment variable %s", dbPasswordEnvVar)
+	}
+
+	name := os.Getenv(dbNameEnvVar)
+	if len(name) == 0 {
+		return fmt.Errorf("unrecognised value for environment variable %s", dbNameEnvVar)
+	}
+
+	host := os.Getenv(dbHostEnvVar)
+	if len(host) == 0 {
+		return fmt.Errorf("unrecognised value for environment variable %s", dbHostEnvVar)
+	}
+
+	openConns, err := getEnvVarInt(dbMaxOpenConnsEnvVar)
+	if err != nil {
+		return err
+	}
+
+	idleConns, err := getEnvVarInt(dbMaxIdleConnsEnvVar)
+	if err != nil {
+		return err
+	}
+
+	connLifetime, err := getEnvVarInt(dbConnMaxLifetimeSecsEnvVar)
+	if err != nil {
+		return err
+	}
+
+	retries, err := getEnvVarInt(dbConnRetriesEnvVar)
+	if err != nil {
+		return err
+	}
+
+	interval, err := getEnvVarInt(dbRetryIntervalEnvVar)
+	if err != nil {
+		return err
+	}
+
+	cfg.DBUsername = username
+	cfg.DBPassword = password
+	cfg.DBName = name
+	cfg.DBHost = host
+	cfg.DBMaxOpenConns = openConns
+	cfg.DBMaxIdleConns = idleConns
+	cfg.DBConnMaxLifetimeSecs = connLifetime
+	cfg.DBConnRetries = retries
+	cfg.DBConnRetryIntervalSecs = interval
+
+	return nil
+}
+
 func getEnvVarInt(name string) (int, error) {
 	valueStr := os.Getenv(name)
 	if len(valueStr) == 0 {
@@ -41,10 +41,15 @@ func Test_Get(t *testing.T) {
 		disableKeepAlivesStr       = "true"
 		disableKeepAlives          = true
 		spaceXAPIEndpoint          = "https://api.spacexdata.com"
+		paymentGatewayEndpoint     = "http://localhost:9000"
+		spaceXSnapshotPath         = "testdata/launches.json"
+		smtpAddr                   = "localhost:1025"
+		smtpFrom                   = "bookings@spacetickets.example"
 	)
 
 	tests := []struct {
 		name                    string
+		storageBackend          string
 		dbUserName              string
 		dbPassword              string
 		dbName                  string
@@ -65,6 +70,12 @@ func Test_Get(t *testing.T) {
 		tlsHandshakeTimeoutSecs string
 		disableKeepAlives       string
 		spaceXAPIEndpoint       string
+		spaceXSnapshotPath      string
+		paymentGateway          string
+		paymentGatewayEndpoint  string
+		notifier                string
+		smtpAddr                string
+		smtpFrom                string
 		want                    Config
 		wantErr                 string
 	}{
@@ -91,6 +102,7 @@ func Test_Get(t *testing.T) {
 			disableKeepAlives:       disableKeepAlivesStr,
 			spaceXAPIEndpoint:       spaceXAPIEndpoint,
 			want: Config{
+				StorageBackend:          StorageBackendPostgres,
 				DBUsername:              user,
 				DBPassword:              pwd,
 				DBName:                  name,
@@ -111,6 +123,8 @@ func Test_Get(t *testing.T) {
 				TLSHandshakeTimeoutSecs: tlsHandshakeTimeoutSecs,
 				DisableKeepAlives:       disableKeepAlives,
 				SpaceXAPIEndpoint:       spaceXAPIEndpoint,
+				PaymentGateway:          PaymentGatewayFake,
+				Notifier:                NotifierLog,
 			},
 			wantErr: "",
 		},
@@ -123,11 +137,197 @@ func Test_Get(t *testing.T) {
 			want:       Config{},
 			wantErr:    "unrecognised value for environment variable DB_USERNAME",
 		},
+		{
+			name:                    "3. Memory storage backend, DB env vars not required",
+			storageBackend:          StorageBackendMemory,
+			port:                    port,
+			swagPort:                swagPort,
+			httpTimeout:             httpTimeoutStr,
+			maxIdleConns:            maxIdleConnsStr,
+			maxConnsPerHost:         maxConnsPerHostStr,
+			idleConnTimeoutSecs:     idleConnTimeoutSecsStr,
+			dialerTimeoutSecs:       dialerTimeoutSecsStr,
+			dialerKeepAliveSecs:     dialerKeepAliveSecsStr,
+			tlsHandshakeTimeoutSecs: tlsHandshakeTimeoutSecsStr,
+			disableKeepAlives:       disableKeepAlivesStr,
+			spaceXAPIEndpoint:       spaceXAPIEndpoint,
+			want: Config{
+				StorageBackend:          StorageBackendMemory,
+				APIPort:                 port,
+				SwaggerPort:             swagPort,
+				HTTPTimeout:             httpTimeout,
+				MaxIdleConns:            maxIdleConns,
+				MaxConnsPerHost:         maxConnsPerHost,
+				IdleConnTimeoutSecs:     idleConnTimeoutSecs,
+				DialerTimeoutSecs:       dialerTimeoutSecs,
+				DialerKeepAliveSecs:     dialerKeepAliveSecs,
+				TLSHandshakeTimeoutSecs: tlsHandshakeTimeoutSecs,
+				DisableKeepAlives:       disableKeepAlives,
+				SpaceXAPIEndpoint:       spaceXAPIEndpoint,
+				PaymentGateway:          PaymentGatewayFake,
+				Notifier:                NotifierLog,
+			},
+			wantErr: "",
+		},
+		{
+			name:           "4. SQLite storage backend without a path, empty Config and an error returned",
+			storageBackend: StorageBackendSQLite,
+			want:           Config{},
+			wantErr:        "unrecognised value for environment variable SQLITE_PATH",
+		},
+		{
+			name:           "5. Unrecognised storage backend, empty Config and an error returned",
+			storageBackend: "cassette-tape",
+			want:           Config{},
+			wantErr:        "unrecognised value for environment variable STORAGE_BACKEND",
+		},
+		{
+			name:                    "6. HTTP payment gateway, endpoint returned",
+			storageBackend:          StorageBackendMemory,
+			port:                    port,
+			swagPort:                swagPort,
+			httpTimeout:             httpTimeoutStr,
+			maxIdleConns:            maxIdleConnsStr,
+			maxConnsPerHost:         maxConnsPerHostStr,
+			idleConnTimeoutSecs:     idleConnTimeoutSecsStr,
+			dialerTimeoutSecs:       dialerTimeoutSecsStr,
+			dialerKeepAliveSecs:     dialerKeepAliveSecsStr,
+			tlsHandshakeTimeoutSecs: tlsHandshakeTimeoutSecsStr,
+			disableKeepAlives:       disableKeepAlivesStr,
+			spaceXAPIEndpoint:       spaceXAPIEndpoint,
+			paymentGateway:          PaymentGatewayHTTP,
+			paymentGatewayEndpoint:  paymentGatewayEndpoint,
+			want: Config{
+				StorageBackend:          StorageBackendMemory,
+				APIPort:                 port,
+				SwaggerPort:             swagPort,
+				HTTPTimeout:             httpTimeout,
+				MaxIdleConns:            maxIdleConns,
+				MaxConnsPerHost:         maxConnsPerHost,
+				IdleConnTimeoutSecs:     idleConnTimeoutSecs,
+				DialerTimeoutSecs:       dialerTimeoutSecs,
+				DialerKeepAliveSecs:     dialerKeepAliveSecs,
+				TLSHandshakeTimeoutSecs: tlsHandshakeTimeoutSecs,
+				DisableKeepAlives:       disableKeepAlives,
+				SpaceXAPIEndpoint:       spaceXAPIEndpoint,
+				PaymentGateway:          PaymentGatewayHTTP,
+				PaymentGatewayEndpoint:  paymentGatewayEndpoint,
+				Notifier:                NotifierLog,
+			},
+			wantErr: "",
+		},
+		{
+			name:                    "7. HTTP payment gateway without an endpoint, empty Config and an error returned",
+			storageBackend:          StorageBackendMemory,
+			port:                    port,
+			swagPort:                swagPort,
+			httpTimeout:             httpTimeoutStr,
+			maxIdleConns:            maxIdleConnsStr,
+			maxConnsPerHost:         maxConnsPerHostStr,
+			idleConnTimeoutSecs:     idleConnTimeoutSecsStr,
+			dialerTimeoutSecs:       dialerTimeoutSecsStr,
+			dialerKeepAliveSecs:     dialerKeepAliveSecsStr,
+			tlsHandshakeTimeoutSecs: tlsHandshakeTimeoutSecsStr,
+			disableKeepAlives:       disableKeepAlivesStr,
+			spaceXAPIEndpoint:       spaceXAPIEndpoint,
+			paymentGateway:          PaymentGatewayHTTP,
+			want:                    Config{},
+			wantErr:                 "unrecognised value for environment variable PAYMENT_GATEWAY_ENDPOINT",
+		},
+		{
+			name:                    "8. SpaceX snapshot, SpaceX API endpoint not required",
+			storageBackend:          StorageBackendMemory,
+			port:                    port,
+			swagPort:                swagPort,
+			httpTimeout:             httpTimeoutStr,
+			maxIdleConns:            maxIdleConnsStr,
+			maxConnsPerHost:         maxConnsPerHostStr,
+			idleConnTimeoutSecs:     idleConnTimeoutSecsStr,
+			dialerTimeoutSecs:       dialerTimeoutSecsStr,
+			dialerKeepAliveSecs:     dialerKeepAliveSecsStr,
+			tlsHandshakeTimeoutSecs: tlsHandshakeTimeoutSecsStr,
+			disableKeepAlives:       disableKeepAlivesStr,
+			spaceXSnapshotPath:      spaceXSnapshotPath,
+			want: Config{
+				StorageBackend:          StorageBackendMemory,
+				APIPort:                 port,
+				SwaggerPort:             swagPort,
+				HTTPTimeout:             httpTimeout,
+				MaxIdleConns:            maxIdleConns,
+				MaxConnsPerHost:         maxConnsPerHost,
+				IdleConnTimeoutSecs:     idleConnTimeoutSecs,
+				DialerTimeoutSecs:       dialerTimeoutSecs,
+				DialerKeepAliveSecs:     dialerKeepAliveSecs,
+				TLSHandshakeTimeoutSecs: tlsHandshakeTimeoutSecs,
+				DisableKeepAlives:       disableKeepAlives,
+				SpaceXSnapshotPath:      spaceXSnapshotPath,
+				PaymentGateway:          PaymentGatewayFake,
+				Notifier:                NotifierLog,
+			},
+			wantErr: "",
+		},
+		{
+			name:                    "9. SMTP notifier, mail server returned",
+			storageBackend:          StorageBackendMemory,
+			port:                    port,
+			swagPort:                swagPort,
+			httpTimeout:             httpTimeoutStr,
+			maxIdleConns:            maxIdleConnsStr,
+			maxConnsPerHost:         maxConnsPerHostStr,
+			idleConnTimeoutSecs:     idleConnTimeoutSecsStr,
+			dialerTimeoutSecs:       dialerTimeoutSecsStr,
+			dialerKeepAliveSecs:     dialerKeepAliveSecsStr,
+			tlsHandshakeTimeoutSecs: tlsHandshakeTimeoutSecsStr,
+			disableKeepAlives:       disableKeepAlivesStr,
+			spaceXAPIEndpoint:       spaceXAPIEndpoint,
+			notifier:                NotifierSMTP,
+			smtpAddr:                smtpAddr,
+			smtpFrom:                smtpFrom,
+			want: Config{
+				StorageBackend:          StorageBackendMemory,
+				APIPort:                 port,
+				SwaggerPort:             swagPort,
+				HTTPTimeout:             httpTimeout,
+				MaxIdleConns:            maxIdleConns,
+				MaxConnsPerHost:         maxConnsPerHost,
+				IdleConnTimeoutSecs:     idleConnTimeoutSecs,
+				DialerTimeoutSecs:       dialerTimeoutSecs,
+				DialerKeepAliveSecs:     dialerKeepAliveSecs,
+				TLSHandshakeTimeoutSecs: tlsHandshakeTimeoutSecs,
+				DisableKeepAlives:       disableKeepAlives,
+				SpaceXAPIEndpoint:       spaceXAPIEndpoint,
+				PaymentGateway:          PaymentGatewayFake,
+				Notifier:                NotifierSMTP,
+				SMTPAddr:                smtpAddr,
+				SMTPFrom:                smtpFrom,
+			},
+			wantErr: "",
+		},
+		{
+			name:                    "10. SMTP notifier without a mail server, empty Config and an error returned",
+			storageBackend:          StorageBackendMemory,
+			port:                    port,
+			swagPort:                swagPort,
+			httpTimeout:             httpTimeoutStr,
+			maxIdleConns:            maxIdleConnsStr,
+			maxConnsPerHost:         maxConnsPerHostStr,
+			idleConnTimeoutSecs:     idleConnTimeoutSecsStr,
+			dialerTimeoutSecs:       dialerTimeoutSecsStr,
+			dialerKeepAliveSecs:     dialerKeepAliveSecsStr,
+			tlsHandshakeTimeoutSecs: tlsHandshakeTimeoutSecsStr,
+			disableKeepAlives:       disableKeepAlivesStr,
+			spaceXAPIEndpoint:       spaceXAPIEndpoint,
+			notifier:                NotifierSMTP,
+			smtpFrom:                smtpFrom,
+			want:                    Config{},
+			wantErr:                 "unrecognised value for environment variable SMTP_ADDR",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			defer func() {
+				os.Unsetenv(storageBackendEnvVar)
 				os.Unsetenv(dBUsernameEnvVar)
 				os.Unsetenv(dbPasswordEnvVar)
 				os.Unsetenv(dbNameEnvVar)
@@ -148,9 +348,18 @@ func Test_Get(t *testing.T) {
 				os.Unsetenv(tlsHandshakeTimeoutSecsEnvVar)
 				os.Unsetenv(disableKeepAlivesEnvVar)
 				os.Unsetenv(spaceXAPIEndpointEnvVar)
+				os.Unsetenv(spaceXSnapshotPathEnvVar)
+				os.Unsetenv(paymentGatewayEnvVar)
+				os.Unsetenv(paymentGatewayEndpointEnvVar)
+				os.Unsetenv(notifierEnvVar)
+				os.Unsetenv(smtpAddrEnvVar)
+				os.Unsetenv(smtpFromEnvVar)
 
 			}()
 
+			if len(tt.storageBackend) > 0 {
+				os.Setenv(storageBackendEnvVar, tt.storageBackend)
+			}
 			if len(tt.dbUserName) > 0 {
 				os.Setenv(dBUsernameEnvVar, tt.dbUserName)
 			}
@@ -211,6 +420,24 @@ func Test_Get(t *testing.T) {
 			if len(tt.spaceXAPIEndpoint) > 0 {
 				os.Setenv(spaceXAPIEndpointEnvVar, tt.spaceXAPIEndpoint)
 			}
+			if len(tt.spaceXSnapshotPath) > 0 {
+				os.Setenv(spaceXSnapshotPathEnvVar, tt.spaceXSnapshotPath)
+			}
+			if len(tt.paymentGateway) > 0 {
+				os.Setenv(paymentGatewayEnvVar, tt.paymentGateway)
+			}
+			if len(tt.paymentGatewayEndpoint) > 0 {
+				os.Setenv(paymentGatewayEndpointEnvVar, tt.paymentGatewayEndpoint)
+			}
+			if len(tt.notifier) > 0 {
+				os.Setenv(notifierEnvVar, tt.notifier)
+			}
+			if len(tt.smtpAddr) > 0 {
+				os.Setenv(smtpAddrEnvVar, tt.smtpAddr)
+			}
+			if len(tt.smtpFrom) > 0 {
+				os.Setenv(smtpFromEnvVar, tt.smtpFrom)
+			}
 
 			got, err := Get()
 
@@ -1,14 +1,162 @@
 package database
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/petherin/spacetickets/internal/domains/bookings"
 )
 
+const bookingColumns = `id, first_name, last_name, gender, birthday, email, phone, launchpad_id, destination_id, launch_date, departure_at, estimated_arrival, direction, outbound_booking_id, group_id, price, currency, promo_code, status, payment_id, cancellation_reason, refund_amount, refund_id, review_required, review_reason, checked_in_at, boarded_at, access_token, deleted, created_at, updated_at`
+
 // Get returns all bookings that aren't marked as deleted.
-func (p *PostGres) GetAll() ([]bookings.Booking, error) {
-	rows, err := p.Repo.Query(`SELECT id, first_name, last_name, gender, birthday, launchpad_id, destination_id, launch_date, created_at, updated_at FROM bookings WHERE deleted = false`)
+func (s *sqlStore) GetAll() ([]bookings.Booking, error) {
+	return s.queryBookings(`SELECT ` + bookingColumns + ` FROM bookings WHERE deleted = false`)
+}
+
+// Get retrieves the requested booking, even if it's marked as deleted.
+func (s *sqlStore) Get(id string) (*bookings.Booking, error) {
+	result, err := scanBooking(s.conn.QueryRow(`SELECT `+bookingColumns+` FROM bookings WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("booking %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// GetReturn returns the booking that isn't marked as deleted for the return flight of the outbound booking, or
+// bookings.ErrNotFound if it doesn't have one.
+func (s *sqlStore) GetReturn(outboundBookingId string) (*bookings.Booking, error) {
+	result, err := scanBooking(s.conn.QueryRow(`SELECT `+bookingColumns+` FROM bookings WHERE outbound_booking_id = $1 AND deleted = false`,
+		outboundBookingId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("return flight for booking %s: %w", outboundBookingId, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// GetUpcoming returns the bookings that aren't marked as deleted for flights from the launchpad on or after the date.
+func (s *sqlStore) GetUpcoming(launchPadId string, from time.Time) ([]bookings.Booking, error) {
+	return s.queryBookings(`SELECT `+bookingColumns+` FROM bookings WHERE launchpad_id = $1 AND launch_date >= $2 AND deleted = false ORDER BY launch_date`,
+		launchPadId, from)
+}
+
+// FlagForReview marks a booking as needing review, recording why.
+func (s *sqlStore) FlagForReview(id, reason string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE bookings SET review_required = true, review_reason = $2, updated_at = $3 WHERE id = $1`,
+		id, reason, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not flag booking for review: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// SetStatus moves a booking to the status, recording the payment id unless it's empty.
+func (s *sqlStore) SetStatus(id, status, paymentId string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE bookings SET status = $2, payment_id = COALESCE(NULLIF($3, ''), payment_id), updated_at = $4 WHERE id = $1`,
+		id, status, paymentId, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not set booking status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ClaimCancellation moves a booking that isn't deleted from the status from to cancelling, recording why it's being
+// cancelled.
+func (s *sqlStore) ClaimCancellation(id, from, reason string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE bookings SET status = $3, cancellation_reason = $4, updated_at = $5 WHERE id = $1 AND status = $2 AND deleted = false`,
+		id, from, bookings.StatusCancelling, reason, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not claim booking for cancellation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ClearDisruption moves a disrupted booking that isn't deleted back to the status, and clears its review flag.
+func (s *sqlStore) ClearDisruption(id, status string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE bookings SET status = $3, review_required = false, review_reason = '', updated_at = $4 WHERE id = $1 AND status = $2 AND deleted = false`,
+		id, bookings.StatusDisrupted, status, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not clear booking disruption: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Cancel marks a booking that isn't deleted as cancelled and deleted, recording why and what was refunded.
+func (s *sqlStore) Cancel(id, reason string, refundAmount *int64, refundId string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE bookings SET status = $2, cancellation_reason = $3, refund_amount = $4, refund_id = $5, deleted = true, updated_at = $6
+	 WHERE id = $1 AND deleted = false`,
+		id, bookings.StatusCancelled, reason, refundAmount, refundId, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not cancel booking: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// MoveStatus moves a booking that isn't deleted from one status to another, recording when it checked in or boarded.
+func (s *sqlStore) MoveStatus(id, from, to string, at time.Time) (int64, error) {
+	query := `UPDATE bookings SET status = $3, updated_at = $4`
+	args := []any{id, from, to, time.Now().UTC()}
+
+	switch to {
+	case bookings.StatusCheckedIn:
+		query += `, checked_in_at = $5`
+		args = append(args, at.UTC())
+	case bookings.StatusBoarded:
+		query += `, boarded_at = $5`
+		args = append(args, at.UTC())
+	}
+
+	result, err := s.conn.Exec(query+` WHERE id = $1 AND status = $2 AND deleted = false`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("could not move booking status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (s *sqlStore) queryBookings(query string, args ...any) ([]bookings.Booking, error) {
+	rows, err := s.conn.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying bookings: %w", err)
 	}
@@ -17,22 +165,11 @@ func (p *PostGres) GetAll() ([]bookings.Booking, error) {
 	results := []bookings.Booking{}
 
 	for rows.Next() {
-		var result bookings.Booking
-		if err := rows.Scan(
-			&result.Id,
-			&result.FirstName,
-			&result.LastName,
-			&result.Gender,
-			&result.Birthday,
-			&result.LaunchPadId,
-			&result.DestinationId,
-			&result.LaunchDate,
-			&result.CreatedAt,
-			&result.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("error scanning bookings: %w", err)
+		result, err := scanBooking(rows)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, result)
+		results = append(results, *result)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating over bookings rows: %w", err)
@@ -41,49 +178,102 @@ func (p *PostGres) GetAll() ([]bookings.Booking, error) {
 	return results, nil
 }
 
-// Get retrieves the requested booking.
-func (p *PostGres) Get(id string) (*bookings.Booking, error) {
+func scanBooking(row scanner) (*bookings.Booking, error) {
 	var result bookings.Booking
+	var departureAt, estimatedArrival, checkedInAt, boardedAt sql.NullTime
+	var outboundBookingId, groupId sql.NullString
+	var price, refundAmount sql.NullInt64
 
-	err := p.Repo.QueryRow(`SELECT id, first_name, last_name, gender, birthday, launchpad_id, destination_id, launch_date, created_at, updated_at FROM bookings WHERE id = $1`, id).
-		Scan(
-			&result.Id,
-			&result.FirstName,
-			&result.LastName,
-			&result.Gender,
-			&result.Birthday,
-			&result.LaunchPadId,
-			&result.DestinationId,
-			&result.LaunchDate,
-			&result.CreatedAt,
-			&result.UpdatedAt,
-		)
-	if err != nil {
+	if err := row.Scan(
+		&result.Id,
+		&result.FirstName,
+		&result.LastName,
+		&result.Gender,
+		&result.Birthday,
+		&result.Email,
+		&result.Phone,
+		&result.LaunchPadId,
+		&result.DestinationId,
+		&result.LaunchDate,
+		&departureAt,
+		&estimatedArrival,
+		&result.Direction,
+		&outboundBookingId,
+		&groupId,
+		&price,
+		&result.Currency,
+		&result.PromoCode,
+		&result.Status,
+		&result.PaymentId,
+		&result.CancellationReason,
+		&refundAmount,
+		&result.RefundId,
+		&result.ReviewRequired,
+		&result.ReviewReason,
+		&checkedInAt,
+		&boardedAt,
+		&result.AccessToken,
+		&result.Deleted,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
 		return nil, fmt.Errorf("error scanning booking: %w", err)
 	}
 
+	if departureAt.Valid {
+		result.DepartureAt = &departureAt.Time
+	}
+	if estimatedArrival.Valid {
+		result.EstimatedArrival = &estimatedArrival.Time
+	}
+	result.OutboundBookingId = outboundBookingId.String
+	result.GroupId = groupId.String
+	if price.Valid {
+		result.Price = &price.Int64
+	}
+	if refundAmount.Valid {
+		result.RefundAmount = &refundAmount.Int64
+	}
+	if checkedInAt.Valid {
+		result.CheckedInAt = &checkedInAt.Time
+	}
+	if boardedAt.Valid {
+		result.BoardedAt = &boardedAt.Time
+	}
+
 	return &result, nil
 }
 
-// Create adds a new booking.
-func (p *PostGres) Create(booking bookings.Booking) (*bookings.Booking, error) {
+// Create adds a new booking. A booking without a direction is an outbound flight, and one without a status is
+// pending until it's paid for.
+func (s *sqlStore) Create(booking bookings.Booking) (*bookings.Booking, error) {
 	var insertedID string
 
-	err := p.Repo.QueryRow(`INSERT INTO bookings (first_name, last_name, gender, birthday, launchpad_id, destination_id, launch_date, created_at, updated_at)
-	 VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW()) RETURNING id`,
-		booking.FirstName, booking.LastName, booking.Gender, booking.Birthday, booking.LaunchPadId, booking.DestinationId, booking.LaunchDate).Scan(&insertedID)
+	if len(booking.Direction) == 0 {
+		booking.Direction = bookings.DirectionOutbound
+	}
+
+	if len(booking.Status) == 0 {
+		booking.Status = bookings.StatusPending
+	}
+
+	err := s.conn.QueryRow(`INSERT INTO bookings (id, first_name, last_name, gender, birthday, email, phone, launchpad_id, destination_id, launch_date, departure_at, estimated_arrival, direction, outbound_booking_id, group_id, price, currency, promo_code, status, payment_id, access_token, created_at, updated_at)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $22) RETURNING id`,
+		uuid.NewString(), booking.FirstName, booking.LastName, booking.Gender, booking.Birthday, booking.Email, booking.Phone, booking.LaunchPadId, booking.DestinationId, booking.LaunchDate, utc(booking.DepartureAt),
+		booking.EstimatedArrival, booking.Direction, nullString(booking.OutboundBookingId), nullString(booking.GroupId), booking.Price, booking.Currency, booking.PromoCode,
+		booking.Status, booking.PaymentId, booking.AccessToken, time.Now().UTC()).Scan(&insertedID)
 	if err != nil {
 		return nil, fmt.Errorf("error creating booking: %w", err)
 	}
 
-	return p.Get(insertedID)
+	return s.Get(insertedID)
 }
 
 // Delete marks a booking as deleted.
-func (p *PostGres) Delete(id string) (int64, error) {
+func (s *sqlStore) Delete(id string) (int64, error) {
 	query := `UPDATE bookings SET deleted = true WHERE id = $1`
 
-	result, err := p.Repo.Exec(query, id)
+	result, err := s.conn.Exec(query, id)
 	if err != nil {
 		return 0, fmt.Errorf("could not mark booking as deleted: %w", err)
 	}
@@ -96,34 +286,101 @@ func (p *PostGres) Delete(id string) (int64, error) {
 	return rowsAffected, nil
 }
 
-// GetLaunchPad gets a launchpad by id.
-func (p *PostGres) GetLaunchPad(id string) (*bookings.LaunchPad, error) {
-	var result bookings.LaunchPad
+// GetLaunchPad gets a launchpad by id. Inside a transaction the launchpad is locked until the transaction ends.
+func (s *sqlStore) GetLaunchPad(id string) (*bookings.LaunchPad, error) {
+	result, err := scanLaunchPad(s.conn.QueryRow(`SELECT `+launchPadColumns+` FROM launchpads WHERE id = $1`+s.lock(), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("launchpad %s: %w", id, bookings.ErrNotFound)
+	}
 
-	err := p.Repo.QueryRow(`SELECT id, full_name, spacex_launchpad_id, created_at, updated_at FROM launchpads WHERE id = $1`, id).
-		Scan(
-			&result.Id,
-			&result.FullName,
-			&result.SpaceXLaunchPadId,
-			&result.CreatedAt,
-			&result.UpdatedAt,
-		)
-	if err != nil {
-		return nil, fmt.Errorf("error scanning launchpad: %w", err)
+	return result, err
+}
+
+// FindScheduledFlight returns the schedule entry in force on the launch date for the launchpad's flight in the direction
+// to or from the destination on that day of the week, or bookings.ErrNotFound if there isn't one.
+func (s *sqlStore) FindScheduledFlight(direction, launchPadId, destinationId string, launchDate time.Time) (*bookings.ScheduleEntry, error) {
+	row := s.conn.QueryRow(`SELECT `+scheduleColumns+` FROM launchpad_schedule WHERE direction = $1 AND launchpad_id = $2 AND destination_id = $3 AND day_of_week = $4
+	 AND (effective_from IS NULL OR effective_from <= $5) AND (effective_to IS NULL OR effective_to >= $5)`,
+		direction, launchPadId, destinationId, launchDate.Weekday().String(), launchDate)
+
+	result, err := scanScheduleEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("scheduled flight from launchpad %s: %w", launchPadId, bookings.ErrNotFound)
 	}
 
-	return &result, nil
+	return result, err
 }
 
-// IsLaunchScheduleValid returns true if there is a launch from the requesed launch pad, day of the week, and destination.
-func (p *PostGres) IsLaunchScheduleValid(launchPadId, dayOfWeek, destinationId string) (bool, error) {
+// IsLaunchScheduleValid returns true if the schedule in force on the launch date has a flight in the direction between
+// the requested launch pad and the destination on that day of the week.
+func (s *sqlStore) IsLaunchScheduleValid(direction, launchPadId, destinationId string, launchDate time.Time) (bool, error) {
 	var count int
 
-	err := p.Repo.QueryRow(`SELECT count(*)	FROM launchpad_schedule WHERE launchpad_id = $1 AND destination_id = $2 AND day_of_week = $3`,
-		launchPadId, destinationId, dayOfWeek).Scan(&count)
+	err := s.conn.QueryRow(`SELECT count(*) FROM launchpad_schedule WHERE direction = $1 AND launchpad_id = $2 AND destination_id = $3 AND day_of_week = $4
+	 AND (effective_from IS NULL OR effective_from <= $5) AND (effective_to IS NULL OR effective_to >= $5)`,
+		direction, launchPadId, destinationId, launchDate.Weekday().String(), launchDate).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("error scanning launchpad_schedule: %w", err)
 	}
 
-	return count == 1, nil
+	return count > 0, nil
+}
+
+// CountBookings returns how many seats have been booked on the flight in the direction from or to the launchpad on the
+// given date.
+func (s *sqlStore) CountBookings(direction, launchPadId string, launchDate time.Time) (int, error) {
+	var count int
+
+	err := s.conn.QueryRow(`SELECT count(*) FROM bookings WHERE direction = $1 AND launchpad_id = $2 AND launch_date = $3 AND deleted = false`,
+		direction, launchPadId, launchDate).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting bookings: %w", err)
+	}
+
+	return count, nil
+}
+
+// InTransaction runs fn inside a database transaction, committing it if fn succeeds and rolling it back otherwise.
+func (s *sqlStore) InTransaction(fn func(tx bookings.Booker) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.Repo.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	txStore := &sqlStore{Repo: s.Repo, conn: tx, tx: tx, forUpdate: s.forUpdate}
+	if err := fn(txStore); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to roll back transaction: %v\n", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lock returns the clause that locks selected rows, when called inside a transaction.
+func (s *sqlStore) lock() string {
+	if s.tx == nil {
+		return ""
+	}
+
+	return s.forUpdate
+}
+
+// utc converts an optional time to UTC, so it's stored the same way as the other timestamps.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
 }
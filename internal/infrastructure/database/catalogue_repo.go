@@ -0,0 +1,343 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const launchPadColumns = `id, full_name, spacex_launchpad_id, seat_capacity, timezone, active, created_at, updated_at`
+
+// GetLaunchPads returns every launchpad, including retired ones.
+func (s *sqlStore) GetLaunchPads() ([]bookings.LaunchPad, error) {
+	rows, err := s.conn.Query(`SELECT ` + launchPadColumns + ` FROM launchpads ORDER BY full_name`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying launchpads: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.LaunchPad{}
+
+	for rows.Next() {
+		result, err := scanLaunchPad(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over launchpads rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// CreateLaunchPad adds a new, active launchpad.
+func (s *sqlStore) CreateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	id := uuid.NewString()
+
+	_, err := s.conn.Exec(`INSERT INTO launchpads (`+launchPadColumns+`)
+	 VALUES ($1, $2, $3, $4, $5, true, $6, $6)`,
+		id, launchPad.FullName, launchPad.SpaceXLaunchPadId, launchPad.SeatCapacity, launchPad.Timezone, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating launchpad: %w", err)
+	}
+
+	return s.GetLaunchPad(id)
+}
+
+// UpdateLaunchPad changes a launchpad's name, SpaceX launchpad id, seat capacity, timezone and whether it's active.
+func (s *sqlStore) UpdateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	result, err := s.conn.Exec(`UPDATE launchpads SET full_name = $2, spacex_launchpad_id = $3, seat_capacity = $4, timezone = $5, active = $6, updated_at = $7 WHERE id = $1`,
+		launchPad.Id, launchPad.FullName, launchPad.SpaceXLaunchPadId, launchPad.SeatCapacity, launchPad.Timezone, launchPad.Active, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error updating launchpad: %w", err)
+	}
+
+	if err := checkRowsAffected(result, "launchpad", launchPad.Id); err != nil {
+		return nil, err
+	}
+
+	return s.GetLaunchPad(launchPad.Id)
+}
+
+// RetireLaunchPad marks a launchpad as inactive so no more flights can be booked from it.
+func (s *sqlStore) RetireLaunchPad(id string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE launchpads SET active = false, updated_at = $2 WHERE id = $1`, id, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not retire launchpad: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetDestinations returns every destination, including retired ones.
+func (s *sqlStore) GetDestinations() ([]bookings.Destination, error) {
+	rows, err := s.conn.Query(`SELECT id, name, travel_days, active, created_at, updated_at FROM destinations ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying destinations: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.Destination{}
+
+	for rows.Next() {
+		var result bookings.Destination
+		if err := rows.Scan(
+			&result.Id,
+			&result.Name,
+			&result.TravelDays,
+			&result.Active,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning destinations: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over destinations rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetDestination gets a destination by id.
+func (s *sqlStore) GetDestination(id string) (*bookings.Destination, error) {
+	var result bookings.Destination
+
+	err := s.conn.QueryRow(`SELECT id, name, travel_days, active, created_at, updated_at FROM destinations WHERE id = $1`, id).
+		Scan(
+			&result.Id,
+			&result.Name,
+			&result.TravelDays,
+			&result.Active,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+		)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("destination %s: %w", id, bookings.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning destination: %w", err)
+	}
+
+	return &result, nil
+}
+
+// CreateDestination adds a new, active destination.
+func (s *sqlStore) CreateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	id := uuid.NewString()
+
+	_, err := s.conn.Exec(`INSERT INTO destinations (id, name, travel_days, active, created_at, updated_at) VALUES ($1, $2, $3, true, $4, $4)`,
+		id, destination.Name, destination.TravelDays, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating destination: %w", err)
+	}
+
+	return s.GetDestination(id)
+}
+
+// UpdateDestination changes a destination's name, travel time and whether it's active.
+func (s *sqlStore) UpdateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	result, err := s.conn.Exec(`UPDATE destinations SET name = $2, travel_days = $3, active = $4, updated_at = $5 WHERE id = $1`,
+		destination.Id, destination.Name, destination.TravelDays, destination.Active, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error updating destination: %w", err)
+	}
+
+	if err := checkRowsAffected(result, "destination", destination.Id); err != nil {
+		return nil, err
+	}
+
+	return s.GetDestination(destination.Id)
+}
+
+// RetireDestination marks a destination as inactive so no more flights can be booked to it.
+func (s *sqlStore) RetireDestination(id string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE destinations SET active = false, updated_at = $2 WHERE id = $1`, id, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not retire destination: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetSchedule returns the launchpad's schedule, or every launchpad's schedule if launchPadId is empty.
+func (s *sqlStore) GetSchedule(launchPadId string) ([]bookings.ScheduleEntry, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM launchpad_schedule`
+	var args []any
+	if len(launchPadId) > 0 {
+		query += ` WHERE launchpad_id = $1`
+		args = append(args, launchPadId)
+	}
+
+	rows, err := s.conn.Query(query+` ORDER BY launchpad_id, day_of_week, effective_from`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying launchpad_schedule: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.ScheduleEntry{}
+
+	for rows.Next() {
+		result, err := scanScheduleEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over launchpad_schedule rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// CreateScheduleEntry adds a new weekly flight to the schedule. An entry without a direction is an outbound flight.
+func (s *sqlStore) CreateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	id := uuid.NewString()
+
+	if len(entry.Direction) == 0 {
+		entry.Direction = bookings.DirectionOutbound
+	}
+
+	opens, closes := entry.Window()
+
+	_, err := s.conn.Exec(`INSERT INTO launchpad_schedule (`+scheduleColumns+`)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)`,
+		id, entry.LaunchPadId, entry.DayOfWeek, entry.DestinationId, entry.Direction, entry.EffectiveFrom, entry.EffectiveTo, opens, closes, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating launchpad_schedule entry: %w", err)
+	}
+
+	return s.getScheduleEntry(id)
+}
+
+// UpdateScheduleEntry changes the launchpad, day of the week, destination, direction, effective dates and launch window
+// of a weekly flight.
+func (s *sqlStore) UpdateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	opens, closes := entry.Window()
+
+	result, err := s.conn.Exec(`UPDATE launchpad_schedule SET launchpad_id = $2, day_of_week = $3, destination_id = $4, direction = $5, effective_from = $6, effective_to = $7, window_opens = $8, window_closes = $9, updated_at = $10 WHERE id = $1`,
+		entry.Id, entry.LaunchPadId, entry.DayOfWeek, entry.DestinationId, entry.Direction, entry.EffectiveFrom, entry.EffectiveTo, opens, closes, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error updating launchpad_schedule entry: %w", err)
+	}
+
+	if err := checkRowsAffected(result, "schedule entry", entry.Id); err != nil {
+		return nil, err
+	}
+
+	return s.getScheduleEntry(entry.Id)
+}
+
+// DeleteScheduleEntry removes a weekly flight from the schedule.
+func (s *sqlStore) DeleteScheduleEntry(id string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM launchpad_schedule WHERE id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete launchpad_schedule entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (s *sqlStore) getScheduleEntry(id string) (*bookings.ScheduleEntry, error) {
+	row := s.conn.QueryRow(`SELECT `+scheduleColumns+` FROM launchpad_schedule WHERE id = $1`, id)
+
+	result, err := scanScheduleEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("schedule entry %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLaunchPad(row scanner) (*bookings.LaunchPad, error) {
+	var result bookings.LaunchPad
+
+	if err := row.Scan(
+		&result.Id,
+		&result.FullName,
+		&result.SpaceXLaunchPadId,
+		&result.SeatCapacity,
+		&result.Timezone,
+		&result.Active,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning launchpad: %w", err)
+	}
+
+	return &result, nil
+}
+
+const scheduleColumns = `id, launchpad_id, day_of_week, destination_id, direction, effective_from, effective_to, window_opens, window_closes, created_at, updated_at`
+
+func scanScheduleEntry(row scanner) (*bookings.ScheduleEntry, error) {
+	var result bookings.ScheduleEntry
+	var effectiveFrom, effectiveTo sql.NullTime
+
+	if err := row.Scan(
+		&result.Id,
+		&result.LaunchPadId,
+		&result.DayOfWeek,
+		&result.DestinationId,
+		&result.Direction,
+		&effectiveFrom,
+		&effectiveTo,
+		&result.WindowOpens,
+		&result.WindowCloses,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning launchpad_schedule: %w", err)
+	}
+
+	if effectiveFrom.Valid {
+		result.EffectiveFrom = &effectiveFrom.Time
+	}
+	if effectiveTo.Valid {
+		result.EffectiveTo = &effectiveTo.Time
+	}
+
+	return &result, nil
+}
+
+// checkRowsAffected returns bookings.ErrNotFound if an update didn't change any rows.
+func checkRowsAffected(result sql.Result, name, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s %s: %w", name, id, bookings.ErrNotFound)
+	}
+
+	return nil
+}
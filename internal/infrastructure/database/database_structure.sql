@@ -25,6 +25,9 @@ CREATE TABLE launchpads (
     id uuid DEFAULT uuid_generate_v4() NOT NULL,
     full_name character varying NOT NULL,
     spacex_launchpad_id char(24) NOT NULL,
+    seat_capacity integer NOT NULL,
+    timezone character varying NOT NULL DEFAULT 'UTC',
+    active boolean NOT NULL DEFAULT true,
     created_at timestamp without time zone NOT NULL,
     updated_at timestamp without time zone NOT NULL
 );
@@ -32,6 +35,8 @@ CREATE TABLE launchpads (
 CREATE TABLE destinations (
     id uuid DEFAULT uuid_generate_v4() NOT NULL,
     name character varying NOT NULL,
+    travel_days integer NOT NULL DEFAULT 0,
+    active boolean NOT NULL DEFAULT true,
     created_at timestamp without time zone NOT NULL,
     updated_at timestamp without time zone NOT NULL
 );
@@ -42,10 +47,30 @@ CREATE TABLE bookings (
     last_name character varying NOT NULL,
     gender character varying NOT NULL,
     birthday date NOT NULL,
+    email character varying NOT NULL DEFAULT '',
+    phone character varying NOT NULL DEFAULT '',
     launchpad_id uuid NOT NULL,
     destination_id uuid NOT NULL,
     launch_date date NOT NULL,
-    deleted boolean DEFAULT false, 
+    departure_at timestamp without time zone,
+    estimated_arrival date,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    outbound_booking_id uuid,
+    group_id uuid,
+    price bigint,
+    currency character varying NOT NULL DEFAULT '',
+    promo_code character varying NOT NULL DEFAULT '',
+    status text CHECK (status IN ('pending', 'paid', 'confirmed', 'payment_failed', 'cancelling', 'cancelled', 'disrupted', 'checked_in', 'boarded', 'flown', 'no_show')) NOT NULL DEFAULT 'pending',
+    payment_id character varying NOT NULL DEFAULT '',
+    cancellation_reason character varying NOT NULL DEFAULT '',
+    refund_amount bigint,
+    refund_id character varying NOT NULL DEFAULT '',
+    deleted boolean NOT NULL DEFAULT false,
+    review_required boolean NOT NULL DEFAULT false,
+    review_reason character varying NOT NULL DEFAULT '',
+    checked_in_at timestamp without time zone,
+    boarded_at timestamp without time zone,
+    access_token character varying NOT NULL DEFAULT '',
     created_at timestamp without time zone NOT NULL,
     updated_at timestamp without time zone NOT NULL
 );
@@ -55,10 +80,116 @@ CREATE TABLE launchpad_schedule (
     launchpad_id uuid NOT NULL,
     day_of_week text CHECK (day_of_week IN ('Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday')) NOT NULL,
     destination_id uuid NOT NULL,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    effective_from date,
+    effective_to date,
+    window_opens char(5) NOT NULL DEFAULT '00:00',
+    window_closes char(5) NOT NULL DEFAULT '24:00',
     created_at timestamp without time zone NOT NULL,
     updated_at timestamp without time zone NOT NULL
 );
 
+CREATE TABLE schedule_exceptions (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    kind text CHECK (kind IN ('extra_flight', 'cancelled_flight', 'launchpad_closed', 'destination_unavailable')) NOT NULL,
+    launchpad_id uuid,
+    destination_id uuid,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    start_date date NOT NULL,
+    end_date date NOT NULL,
+    reason character varying NOT NULL DEFAULT '',
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE travel_times (
+    launchpad_id uuid NOT NULL,
+    destination_id uuid NOT NULL,
+    travel_days integer NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE holds (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    launchpad_id uuid NOT NULL,
+    destination_id uuid NOT NULL,
+    launch_date date NOT NULL,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    expires_at timestamp without time zone NOT NULL,
+    created_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE fares (
+    launchpad_id uuid NOT NULL,
+    destination_id uuid NOT NULL,
+    base_price bigint NOT NULL,
+    currency char(3) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE fare_rules (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    kind text CHECK (kind IN ('day_of_week', 'season')) NOT NULL,
+    launchpad_id uuid,
+    destination_id uuid,
+    day_of_week text CHECK (day_of_week IN ('Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday')),
+    start_date date,
+    end_date date,
+    multiplier numeric(6, 3) NOT NULL,
+    reason character varying NOT NULL DEFAULT '',
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE eligibility_rules (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    kind text CHECK (kind IN ('min_age', 'max_age', 'max_trips_per_year')) NOT NULL,
+    destination_id uuid,
+    value integer CHECK (value > 0) NOT NULL,
+    reason character varying NOT NULL DEFAULT '',
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE promo_codes (
+    code character varying NOT NULL,
+    percent_off integer CHECK (percent_off BETWEEN 1 AND 100) NOT NULL,
+    valid_from date NOT NULL,
+    valid_to date NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE waitlist (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    first_name character varying NOT NULL,
+    last_name character varying NOT NULL,
+    gender character varying NOT NULL,
+    birthday date NOT NULL,
+    email character varying NOT NULL DEFAULT '',
+    phone character varying NOT NULL DEFAULT '',
+    launchpad_id uuid NOT NULL,
+    destination_id uuid NOT NULL,
+    launch_date date NOT NULL,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    promo_code character varying,
+    reason text CHECK (reason IN ('flight_full', 'spacex_conflict')) NOT NULL,
+    status text CHECK (status IN ('waiting', 'promoted', 'payment_failed', 'flight_unavailable')) NOT NULL DEFAULT 'waiting',
+    booking_id uuid,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE external_launches (
+    id character varying NOT NULL,
+    name character varying NOT NULL,
+    launchpad_id character varying NOT NULL,
+    date_utc timestamp without time zone NOT NULL,
+    imported_at timestamp without time zone NOT NULL
+);
+
 ALTER TABLE ONLY launchpads
     ADD CONSTRAINT launchpads_pkey PRIMARY KEY (id);
 
@@ -71,25 +202,52 @@ ALTER TABLE ONLY bookings
 ALTER TABLE ONLY launchpad_schedule
     ADD CONSTRAINT launchpad_schedule_pkey PRIMARY KEY (id);
 
-INSERT INTO launchpads(id, full_name, spacex_launchpad_id, created_at, updated_at) VALUES
-    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', 'Vandenberg Space Force Base Space Launch Complex 3W', '5e9e4501f5090910d4566f83', NOW(), NOW()),
-    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Cape Canaveral Space Force Station Space Launch Complex 40', '5e9e4501f509094ba4566f84', NOW(), NOW()),
-    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', 'SpaceX South Texas Launch Site', '5e9e4502f5090927f8566f85', NOW(), NOW()),
-    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', 'Kwajalein Atoll Omelek Island', '5e9e4502f5090995de566f86', NOW(), NOW()),
-    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', 'Vandenberg Space Force Base Space Launch Complex 4E', '5e9e4502f509092b78566f87', NOW(), NOW()),
-    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Kennedy Space Center Historic Launch Complex 39A', '5e9e4502f509094188566f88', NOW(), NOW());
-
-INSERT INTO destinations(id, name, created_at, updated_at) VALUES
-    ('466fc378-14eb-4ed9-8bec-d29abe54c5a9', 'Moon', NOW(), NOW()),
-    ('f47eef79-675f-46da-86f9-ee598185d204', 'Mars', NOW(), NOW()),
-    ('fbd40165-03c7-47a5-be72-c79f81ebbf67', 'Pluto', NOW(), NOW()),
-    ('13b91e0c-cdb4-4108-9c48-5a49d8ded732', 'Asteroid Belt', NOW(), NOW()),
-    ('998f4a82-5a1c-4542-8497-e3fa24618d79', 'Europa', NOW(), NOW()),
-    ('12549fca-d086-4e9f-b14e-dcb3b0d09c63', 'Titan', NOW(), NOW()),
-    ('3840d5ce-b939-4af7-9dd8-ac12c09d1493', 'Ganymede', NOW(), NOW());
-
-INSERT INTO bookings(first_name, last_name, gender, birthday, launchpad_id, destination_id, launch_date, created_at, updated_at) VALUES (
-    'Brian', 'Blessed', 'Male', '1936-10-09', 'd95c83bb-be3f-4bdb-93fe-77015d95f759', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', '2021-12-01', NOW(), NOW()
+ALTER TABLE ONLY schedule_exceptions
+    ADD CONSTRAINT schedule_exceptions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY travel_times
+    ADD CONSTRAINT travel_times_pkey PRIMARY KEY (launchpad_id, destination_id);
+
+ALTER TABLE ONLY holds
+    ADD CONSTRAINT holds_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY fares
+    ADD CONSTRAINT fares_pkey PRIMARY KEY (launchpad_id, destination_id);
+
+ALTER TABLE ONLY fare_rules
+    ADD CONSTRAINT fare_rules_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY eligibility_rules
+    ADD CONSTRAINT eligibility_rules_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY promo_codes
+    ADD CONSTRAINT promo_codes_pkey PRIMARY KEY (code);
+
+ALTER TABLE ONLY waitlist
+    ADD CONSTRAINT waitlist_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY external_launches
+    ADD CONSTRAINT external_launches_pkey PRIMARY KEY (id);
+
+INSERT INTO launchpads(id, full_name, spacex_launchpad_id, seat_capacity, timezone, created_at, updated_at) VALUES
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', 'Vandenberg Space Force Base Space Launch Complex 3W', '5e9e4501f5090910d4566f83', 50, 'America/Los_Angeles', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Cape Canaveral Space Force Station Space Launch Complex 40', '5e9e4501f509094ba4566f84', 100, 'America/New_York', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', 'SpaceX South Texas Launch Site', '5e9e4502f5090927f8566f85', 100, 'America/Chicago', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', 'Kwajalein Atoll Omelek Island', '5e9e4502f5090995de566f86', 20, 'Pacific/Kwajalein', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', 'Vandenberg Space Force Base Space Launch Complex 4E', '5e9e4502f509092b78566f87', 50, 'America/Los_Angeles', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Kennedy Space Center Historic Launch Complex 39A', '5e9e4502f509094188566f88', 100, 'America/New_York', NOW(), NOW());
+
+INSERT INTO destinations(id, name, travel_days, created_at, updated_at) VALUES
+    ('466fc378-14eb-4ed9-8bec-d29abe54c5a9', 'Moon', 3, NOW(), NOW()),
+    ('f47eef79-675f-46da-86f9-ee598185d204', 'Mars', 210, NOW(), NOW()),
+    ('fbd40165-03c7-47a5-be72-c79f81ebbf67', 'Pluto', 3500, NOW(), NOW()),
+    ('13b91e0c-cdb4-4108-9c48-5a49d8ded732', 'Asteroid Belt', 450, NOW(), NOW()),
+    ('998f4a82-5a1c-4542-8497-e3fa24618d79', 'Europa', 2200, NOW(), NOW()),
+    ('12549fca-d086-4e9f-b14e-dcb3b0d09c63', 'Titan', 2600, NOW(), NOW()),
+    ('3840d5ce-b939-4af7-9dd8-ac12c09d1493', 'Ganymede', 2200, NOW(), NOW());
+
+INSERT INTO bookings(first_name, last_name, gender, birthday, launchpad_id, destination_id, launch_date, status, created_at, updated_at) VALUES (
+    'Brian', 'Blessed', 'Male', '1936-10-09', 'd95c83bb-be3f-4bdb-93fe-77015d95f759', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', '2021-12-01', 'confirmed', NOW(), NOW()
 );
 
 INSERT INTO launchpad_schedule(launchpad_id, day_of_week, destination_id, created_at, updated_at) VALUES
@@ -139,4 +297,79 @@ INSERT INTO launchpad_schedule(launchpad_id, day_of_week, destination_id, create
     ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Monday', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', NOW(), NOW()),
     ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Tuesday', '998f4a82-5a1c-4542-8497-e3fa24618d79', NOW(), NOW()),
     ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Wednesday', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', NOW(), NOW()),
-    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Thursday', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', NOW(), NOW());
\ No newline at end of file
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'Thursday', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', NOW(), NOW());
+
+INSERT INTO launchpad_schedule(launchpad_id, day_of_week, destination_id, direction, created_at, updated_at) VALUES
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Monday', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 'return', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Tuesday', 'f47eef79-675f-46da-86f9-ee598185d204', 'return', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Wednesday', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 'return', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Thursday', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 'return', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Friday', '998f4a82-5a1c-4542-8497-e3fa24618d79', 'return', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Saturday', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 'return', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'Sunday', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 'return', NOW(), NOW());
+
+INSERT INTO fares(launchpad_id, destination_id, base_price, currency, created_at, updated_at) VALUES
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 25000000, 'USD', NOW(), NOW()),
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', 'f47eef79-675f-46da-86f9-ee598185d204', 150000000, 'USD', NOW(), NOW()),
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 900000000, 'USD', NOW(), NOW()),
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 200000000, 'USD', NOW(), NOW()),
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', '998f4a82-5a1c-4542-8497-e3fa24618d79', 600000000, 'USD', NOW(), NOW()),
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 700000000, 'USD', NOW(), NOW()),
+    ('d95c83bb-be3f-4bdb-93fe-77015d95f759', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 600000000, 'USD', NOW(), NOW()),
+
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 25000000, 'USD', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'f47eef79-675f-46da-86f9-ee598185d204', 150000000, 'USD', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 900000000, 'USD', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 200000000, 'USD', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', '998f4a82-5a1c-4542-8497-e3fa24618d79', 600000000, 'USD', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 700000000, 'USD', NOW(), NOW()),
+    ('b542c0cf-7fe3-4bb1-a63f-7cbdf8359975', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 600000000, 'USD', NOW(), NOW()),
+
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 25000000, 'USD', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', 'f47eef79-675f-46da-86f9-ee598185d204', 150000000, 'USD', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 900000000, 'USD', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 200000000, 'USD', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', '998f4a82-5a1c-4542-8497-e3fa24618d79', 600000000, 'USD', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 700000000, 'USD', NOW(), NOW()),
+    ('b09e0b80-51ca-44ac-820a-d5b95b209cad', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 600000000, 'USD', NOW(), NOW()),
+
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 25000000, 'USD', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', 'f47eef79-675f-46da-86f9-ee598185d204', 150000000, 'USD', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 900000000, 'USD', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 200000000, 'USD', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', '998f4a82-5a1c-4542-8497-e3fa24618d79', 600000000, 'USD', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 700000000, 'USD', NOW(), NOW()),
+    ('e169113a-ae89-4c39-9a28-3cbc1c96e5e0', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 600000000, 'USD', NOW(), NOW()),
+
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 25000000, 'USD', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', 'f47eef79-675f-46da-86f9-ee598185d204', 150000000, 'USD', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 900000000, 'USD', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 200000000, 'USD', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', '998f4a82-5a1c-4542-8497-e3fa24618d79', 600000000, 'USD', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 700000000, 'USD', NOW(), NOW()),
+    ('9f8cb517-ca3b-4810-baef-80b48b8cf5e6', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 600000000, 'USD', NOW(), NOW()),
+
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', '466fc378-14eb-4ed9-8bec-d29abe54c5a9', 25000000, 'USD', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'f47eef79-675f-46da-86f9-ee598185d204', 150000000, 'USD', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 900000000, 'USD', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', '13b91e0c-cdb4-4108-9c48-5a49d8ded732', 200000000, 'USD', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', '998f4a82-5a1c-4542-8497-e3fa24618d79', 600000000, 'USD', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', '12549fca-d086-4e9f-b14e-dcb3b0d09c63', 700000000, 'USD', NOW(), NOW()),
+    ('4079f070-3e58-4e61-8af7-05c8de8e1fbf', '3840d5ce-b939-4af7-9dd8-ac12c09d1493', 600000000, 'USD', NOW(), NOW());
+
+INSERT INTO fare_rules(kind, day_of_week, multiplier, reason, created_at, updated_at) VALUES
+    ('day_of_week', 'Saturday', 1.2, 'weekend', NOW(), NOW()),
+    ('day_of_week', 'Sunday', 1.2, 'weekend', NOW(), NOW());
+
+INSERT INTO fare_rules(kind, start_date, end_date, multiplier, reason, created_at, updated_at) VALUES
+    ('season', '2026-12-19', '2027-01-03', 1.5, 'holiday season', NOW(), NOW());
+
+INSERT INTO eligibility_rules(kind, value, reason, created_at, updated_at) VALUES
+    ('min_age', 2, 'infants are too young for launch', NOW(), NOW()),
+    ('max_age', 90, 'passengers over 90 cannot get medical clearance', NOW(), NOW());
+
+INSERT INTO eligibility_rules(kind, destination_id, value, reason, created_at, updated_at) VALUES
+    ('min_age', 'fbd40165-03c7-47a5-be72-c79f81ebbf67', 18, 'no minors on the long flight to Pluto', NOW(), NOW());
+
+INSERT INTO promo_codes(code, percent_off, valid_from, valid_to, created_at, updated_at) VALUES
+    ('WELCOME10', 10, '2024-01-01', '2030-12-31', NOW(), NOW());
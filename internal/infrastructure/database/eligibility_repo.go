@@ -0,0 +1,108 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const eligibilityRuleColumns = `id, kind, destination_id, value, reason, created_at, updated_at`
+
+// GetEligibilityRules returns every eligibility rule.
+func (s *sqlStore) GetEligibilityRules() ([]bookings.EligibilityRule, error) {
+	rows, err := s.conn.Query(`SELECT ` + eligibilityRuleColumns + ` FROM eligibility_rules ORDER BY kind, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying eligibility_rules: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.EligibilityRule{}
+
+	for rows.Next() {
+		result, err := scanEligibilityRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over eligibility_rules rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// CreateEligibilityRule adds a new eligibility rule.
+func (s *sqlStore) CreateEligibilityRule(rule bookings.EligibilityRule) (*bookings.EligibilityRule, error) {
+	id := uuid.NewString()
+
+	_, err := s.conn.Exec(`INSERT INTO eligibility_rules (`+eligibilityRuleColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $6)`,
+		id, rule.Kind, nullString(rule.DestinationId), rule.Value, rule.Reason, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating eligibility rule: %w", err)
+	}
+
+	row := s.conn.QueryRow(`SELECT `+eligibilityRuleColumns+` FROM eligibility_rules WHERE id = $1`, id)
+
+	result, err := scanEligibilityRule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("eligibility rule %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// DeleteEligibilityRule removes an eligibility rule.
+func (s *sqlStore) DeleteEligibilityRule(id string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM eligibility_rules WHERE id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete eligibility rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// CountTrips counts the customer's outbound bookings that aren't deleted launching from from up to to. Names are
+// matched ignoring case.
+func (s *sqlStore) CountTrips(customer bookings.Customer, from, to time.Time) (int, error) {
+	var count int
+
+	err := s.conn.QueryRow(`SELECT count(*) FROM bookings WHERE lower(first_name) = lower($1) AND lower(last_name) = lower($2)
+	 AND birthday = $3 AND direction = $4 AND launch_date >= $5 AND launch_date < $6 AND deleted = false`,
+		customer.FirstName, customer.LastName, customer.Birthday, bookings.DirectionOutbound, from, to).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting trips: %w", err)
+	}
+
+	return count, nil
+}
+
+func scanEligibilityRule(row scanner) (*bookings.EligibilityRule, error) {
+	var result bookings.EligibilityRule
+	var destinationId sql.NullString
+
+	if err := row.Scan(
+		&result.Id,
+		&result.Kind,
+		&destinationId,
+		&result.Value,
+		&result.Reason,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning eligibility_rules: %w", err)
+	}
+
+	result.DestinationId = destinationId.String
+
+	return &result, nil
+}
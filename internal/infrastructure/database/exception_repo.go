@@ -0,0 +1,130 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const scheduleExceptionColumns = `id, kind, launchpad_id, destination_id, direction, start_date, end_date, reason, created_at, updated_at`
+
+// GetScheduleExceptions returns the exceptions covering the date, or every exception if the date is zero.
+func (s *sqlStore) GetScheduleExceptions(date time.Time) ([]bookings.ScheduleException, error) {
+	query := `SELECT ` + scheduleExceptionColumns + ` FROM schedule_exceptions`
+	var args []any
+	if !date.IsZero() {
+		query += ` WHERE start_date <= $1 AND end_date >= $1`
+		args = append(args, date)
+	}
+
+	rows, err := s.conn.Query(query+` ORDER BY start_date, kind`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schedule_exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.ScheduleException{}
+
+	for rows.Next() {
+		result, err := scanScheduleException(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over schedule_exceptions rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetScheduleException gets a schedule exception by id.
+func (s *sqlStore) GetScheduleException(id string) (*bookings.ScheduleException, error) {
+	row := s.conn.QueryRow(`SELECT `+scheduleExceptionColumns+` FROM schedule_exceptions WHERE id = $1`, id)
+
+	result, err := scanScheduleException(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("schedule exception %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// CreateScheduleException adds a new exception to the schedule.
+func (s *sqlStore) CreateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	id := uuid.NewString()
+
+	_, err := s.conn.Exec(`INSERT INTO schedule_exceptions (`+scheduleExceptionColumns+`)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)`,
+		id, exception.Kind, nullString(exception.LaunchPadId), nullString(exception.DestinationId), exception.Direction, exception.StartDate, exception.EndDate, exception.Reason, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating schedule exception: %w", err)
+	}
+
+	return s.GetScheduleException(id)
+}
+
+// UpdateScheduleException changes every field of a schedule exception.
+func (s *sqlStore) UpdateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	result, err := s.conn.Exec(`UPDATE schedule_exceptions SET kind = $2, launchpad_id = $3, destination_id = $4, direction = $5, start_date = $6, end_date = $7, reason = $8, updated_at = $9 WHERE id = $1`,
+		exception.Id, exception.Kind, nullString(exception.LaunchPadId), nullString(exception.DestinationId), exception.Direction, exception.StartDate, exception.EndDate, exception.Reason, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error updating schedule exception: %w", err)
+	}
+
+	if err := checkRowsAffected(result, "schedule exception", exception.Id); err != nil {
+		return nil, err
+	}
+
+	return s.GetScheduleException(exception.Id)
+}
+
+// DeleteScheduleException removes an exception, so the weekly schedule applies again.
+func (s *sqlStore) DeleteScheduleException(id string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM schedule_exceptions WHERE id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete schedule exception: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanScheduleException(row scanner) (*bookings.ScheduleException, error) {
+	var result bookings.ScheduleException
+	var launchPadId, destinationId sql.NullString
+
+	if err := row.Scan(
+		&result.Id,
+		&result.Kind,
+		&launchPadId,
+		&destinationId,
+		&result.Direction,
+		&result.StartDate,
+		&result.EndDate,
+		&result.Reason,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning schedule_exceptions: %w", err)
+	}
+
+	result.LaunchPadId = launchPadId.String
+	result.DestinationId = destinationId.String
+
+	return &result, nil
+}
+
+// nullString stores an empty id as NULL, since it isn't a valid uuid.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: len(value) > 0}
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// ReplaceExternalLaunches deletes the stored snapshot of SpaceX's launches and stores launches instead.
+func (s *sqlStore) ReplaceExternalLaunches(launches []bookings.ExternalLaunch) (int64, error) {
+	if _, err := s.conn.Exec(`DELETE FROM external_launches`); err != nil {
+		return 0, fmt.Errorf("could not delete external launches: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, launch := range launches {
+		_, err := s.conn.Exec(`INSERT INTO external_launches (id, name, launchpad_id, date_utc, imported_at) VALUES ($1, $2, $3, $4, $5)`,
+			launch.Id, launch.Name, launch.LaunchPadId, launch.DateUTC.UTC(), now)
+		if err != nil {
+			return 0, fmt.Errorf("error creating external launch %s: %w", launch.Id, err)
+		}
+	}
+
+	return int64(len(launches)), nil
+}
+
+// CountExternalLaunches counts the launches in the snapshot from the SpaceX launchpad at or after from and before to.
+func (s *sqlStore) CountExternalLaunches(spaceXLaunchPadId string, from, to time.Time) (int, error) {
+	var count int
+	err := s.conn.QueryRow(`SELECT count(*) FROM external_launches WHERE launchpad_id = $1 AND date_utc >= $2 AND date_utc < $3`,
+		spaceXLaunchPadId, from.UTC(), to.UTC()).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting external launches: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const (
+	fareColumns      = `launchpad_id, destination_id, base_price, currency, created_at, updated_at`
+	fareRuleColumns  = `id, kind, launchpad_id, destination_id, day_of_week, start_date, end_date, multiplier, reason, created_at, updated_at`
+	promoCodeColumns = `code, percent_off, valid_from, valid_to, created_at, updated_at`
+)
+
+// GetFares returns every fare.
+func (s *sqlStore) GetFares() ([]bookings.Fare, error) {
+	rows, err := s.conn.Query(`SELECT ` + fareColumns + ` FROM fares ORDER BY launchpad_id, destination_id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying fares: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.Fare{}
+
+	for rows.Next() {
+		result, err := scanFare(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over fares rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetFare gets the fare for flights between the launchpad and destination.
+func (s *sqlStore) GetFare(launchPadId, destinationId string) (*bookings.Fare, error) {
+	row := s.conn.QueryRow(`SELECT `+fareColumns+` FROM fares WHERE launchpad_id = $1 AND destination_id = $2`, launchPadId, destinationId)
+
+	result, err := scanFare(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("fare from launchpad %s to destination %s: %w", launchPadId, destinationId, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// SetFare sets the fare for flights between a launchpad and a destination, replacing any it already has.
+func (s *sqlStore) SetFare(fare bookings.Fare) (*bookings.Fare, error) {
+	_, err := s.conn.Exec(`INSERT INTO fares (`+fareColumns+`) VALUES ($1, $2, $3, $4, $5, $5)
+	 ON CONFLICT (launchpad_id, destination_id) DO UPDATE SET base_price = excluded.base_price, currency = excluded.currency, updated_at = excluded.updated_at`,
+		fare.LaunchPadId, fare.DestinationId, fare.BasePrice, fare.Currency, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error setting fare: %w", err)
+	}
+
+	return s.GetFare(fare.LaunchPadId, fare.DestinationId)
+}
+
+// DeleteFare removes the fare for flights between a launchpad and a destination.
+func (s *sqlStore) DeleteFare(launchPadId, destinationId string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM fares WHERE launchpad_id = $1 AND destination_id = $2`, launchPadId, destinationId)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete fare: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetFareRules returns every fare rule.
+func (s *sqlStore) GetFareRules() ([]bookings.FareRule, error) {
+	rows, err := s.conn.Query(`SELECT ` + fareRuleColumns + ` FROM fare_rules ORDER BY kind, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying fare_rules: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.FareRule{}
+
+	for rows.Next() {
+		result, err := scanFareRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over fare_rules rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// CreateFareRule adds a new fare rule.
+func (s *sqlStore) CreateFareRule(rule bookings.FareRule) (*bookings.FareRule, error) {
+	id := uuid.NewString()
+
+	_, err := s.conn.Exec(`INSERT INTO fare_rules (`+fareRuleColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)`,
+		id, rule.Kind, nullString(rule.LaunchPadId), nullString(rule.DestinationId), nullString(rule.DayOfWeek), rule.StartDate, rule.EndDate,
+		rule.Multiplier, rule.Reason, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating fare rule: %w", err)
+	}
+
+	row := s.conn.QueryRow(`SELECT `+fareRuleColumns+` FROM fare_rules WHERE id = $1`, id)
+
+	result, err := scanFareRule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("fare rule %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// DeleteFareRule removes a fare rule.
+func (s *sqlStore) DeleteFareRule(id string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM fare_rules WHERE id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete fare rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetPromoCodes returns every promo code.
+func (s *sqlStore) GetPromoCodes() ([]bookings.PromoCode, error) {
+	rows, err := s.conn.Query(`SELECT ` + promoCodeColumns + ` FROM promo_codes ORDER BY code`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying promo_codes: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.PromoCode{}
+
+	for rows.Next() {
+		result, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over promo_codes rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetPromoCode gets a promo code, whether or not it can be used today.
+func (s *sqlStore) GetPromoCode(code string) (*bookings.PromoCode, error) {
+	result, err := scanPromoCode(s.conn.QueryRow(`SELECT `+promoCodeColumns+` FROM promo_codes WHERE code = $1`, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("promo code %s: %w", code, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// SetPromoCode adds a promo code, replacing it if it already exists.
+func (s *sqlStore) SetPromoCode(promo bookings.PromoCode) (*bookings.PromoCode, error) {
+	_, err := s.conn.Exec(`INSERT INTO promo_codes (`+promoCodeColumns+`) VALUES ($1, $2, $3, $4, $5, $5)
+	 ON CONFLICT (code) DO UPDATE SET percent_off = excluded.percent_off, valid_from = excluded.valid_from, valid_to = excluded.valid_to, updated_at = excluded.updated_at`,
+		promo.Code, promo.PercentOff, promo.ValidFrom, promo.ValidTo, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error setting promo code: %w", err)
+	}
+
+	return s.GetPromoCode(promo.Code)
+}
+
+// DeletePromoCode removes a promo code. Bookings already made with it keep their price.
+func (s *sqlStore) DeletePromoCode(code string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM promo_codes WHERE code = $1`, code)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete promo code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanFare(row scanner) (*bookings.Fare, error) {
+	var result bookings.Fare
+
+	if err := row.Scan(
+		&result.LaunchPadId,
+		&result.DestinationId,
+		&result.BasePrice,
+		&result.Currency,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning fares: %w", err)
+	}
+
+	return &result, nil
+}
+
+func scanFareRule(row scanner) (*bookings.FareRule, error) {
+	var result bookings.FareRule
+	var launchPadId, destinationId, dayOfWeek sql.NullString
+	var startDate, endDate sql.NullTime
+
+	if err := row.Scan(
+		&result.Id,
+		&result.Kind,
+		&launchPadId,
+		&destinationId,
+		&dayOfWeek,
+		&startDate,
+		&endDate,
+		&result.Multiplier,
+		&result.Reason,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning fare_rules: %w", err)
+	}
+
+	result.LaunchPadId = launchPadId.String
+	result.DestinationId = destinationId.String
+	result.DayOfWeek = dayOfWeek.String
+	if startDate.Valid {
+		result.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		result.EndDate = &endDate.Time
+	}
+
+	return &result, nil
+}
+
+func scanPromoCode(row scanner) (*bookings.PromoCode, error) {
+	var result bookings.PromoCode
+
+	if err := row.Scan(
+		&result.Code,
+		&result.PercentOff,
+		&result.ValidFrom,
+		&result.ValidTo,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning promo_codes: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const holdColumns = `id, launchpad_id, destination_id, launch_date, direction, expires_at, created_at`
+
+// GetHold gets a hold by id, even if it has expired.
+func (s *sqlStore) GetHold(id string) (*bookings.Hold, error) {
+	result, err := scanHold(s.conn.QueryRow(`SELECT `+holdColumns+` FROM holds WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("hold %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// CreateHold adds a new hold. A hold without a direction is on an outbound flight.
+func (s *sqlStore) CreateHold(hold bookings.Hold) (*bookings.Hold, error) {
+	id := uuid.NewString()
+
+	if len(hold.Direction) == 0 {
+		hold.Direction = bookings.DirectionOutbound
+	}
+
+	_, err := s.conn.Exec(`INSERT INTO holds (`+holdColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		id, hold.LaunchPadId, hold.DestinationId, hold.LaunchDate, hold.Direction, hold.ExpiresAt.UTC(), time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating hold: %w", err)
+	}
+
+	return s.GetHold(id)
+}
+
+// DeleteHold removes a hold, releasing its seat.
+func (s *sqlStore) DeleteHold(id string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM holds WHERE id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// CountHolds returns how many seats are held on the flight in the direction from or to the launchpad on the given
+// date by holds that hadn't expired by now.
+func (s *sqlStore) CountHolds(direction, launchPadId string, launchDate, now time.Time) (int, error) {
+	var count int
+
+	err := s.conn.QueryRow(`SELECT count(*) FROM holds WHERE direction = $1 AND launchpad_id = $2 AND launch_date = $3 AND expires_at > $4`,
+		direction, launchPadId, launchDate, now.UTC()).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting holds: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteExpiredHolds removes the holds that had expired by now.
+func (s *sqlStore) DeleteExpiredHolds(now time.Time) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM holds WHERE expires_at <= $1`, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not delete expired holds: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanHold(row scanner) (*bookings.Hold, error) {
+	var result bookings.Hold
+
+	if err := row.Scan(
+		&result.Id,
+		&result.LaunchPadId,
+		&result.DestinationId,
+		&result.LaunchDate,
+		&result.Direction,
+		&result.ExpiresAt,
+		&result.CreatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning hold: %w", err)
+	}
+
+	return &result, nil
+}
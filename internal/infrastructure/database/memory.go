@@ -0,0 +1,533 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// Memory is a thread-safe, in-memory implementation of bookings.Booker for local development and tests.
+// It is seeded with the same launchpads, destinations, schedule and bookings as database_structure.sql.
+type Memory struct {
+	mu   sync.RWMutex
+	data *memoryData
+}
+
+// memoryData holds everything a Memory stores. Its methods expect the caller to hold the Memory's lock.
+type memoryData struct {
+	bookings     []bookings.Booking
+	launchPads   map[string]bookings.LaunchPad
+	destinations map[string]bookings.Destination
+	schedule     []bookings.ScheduleEntry
+	exceptions   []bookings.ScheduleException
+	travelTimes  []bookings.TravelTime
+	holds        []bookings.Hold
+	fares        []bookings.Fare
+	fareRules    []bookings.FareRule
+	promoCodes   map[string]bookings.PromoCode
+	waitlist     []bookings.WaitlistEntry
+	// eligibilityRules limit who can fly.
+	eligibilityRules []bookings.EligibilityRule
+	// externalLaunches is the snapshot of SpaceX's launches. It's only ever replaced whole, so clones can share it.
+	externalLaunches []bookings.ExternalLaunch
+}
+
+// memoryTx is the bookings.Booker passed to InTransaction callbacks. The Memory's write lock is held for the whole
+// transaction, so its methods use the data directly.
+type memoryTx struct {
+	data *memoryData
+}
+
+// NewMemory returns a new Memory, seeded with the same data as database_structure.sql.
+func NewMemory() (*Memory, error) {
+	data := &memoryData{
+		launchPads:   map[string]bookings.LaunchPad{},
+		destinations: map[string]bookings.Destination{},
+		promoCodes:   map[string]bookings.PromoCode{},
+	}
+
+	data.seed()
+
+	return &Memory{data: data}, nil
+}
+
+// Close does nothing, it exists so Memory can be used wherever a PostGres is.
+func (m *Memory) Close() {}
+
+// GetAll returns all bookings that aren't marked as deleted.
+func (m *Memory) GetAll() ([]bookings.Booking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getAll()
+}
+
+// Get retrieves the requested booking, even if it's marked as deleted.
+func (m *Memory) Get(id string) (*bookings.Booking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.get(id)
+}
+
+// GetReturn returns the booking that isn't marked as deleted for the return flight of the outbound booking, or
+// bookings.ErrNotFound if it doesn't have one.
+func (m *Memory) GetReturn(outboundBookingId string) (*bookings.Booking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getReturn(outboundBookingId)
+}
+
+// Create adds a new booking.
+func (m *Memory) Create(booking bookings.Booking) (*bookings.Booking, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.create(booking)
+}
+
+// Delete marks a booking as deleted.
+func (m *Memory) Delete(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.delete(id)
+}
+
+// GetLaunchPad gets a launchpad by id.
+func (m *Memory) GetLaunchPad(id string) (*bookings.LaunchPad, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getLaunchPad(id)
+}
+
+// FindScheduledFlight returns the schedule entry in force on the launch date for the launchpad's flight in the direction
+// to or from the destination on that day of the week, or bookings.ErrNotFound if there isn't one.
+func (m *Memory) FindScheduledFlight(direction, launchPadId, destinationId string, launchDate time.Time) (*bookings.ScheduleEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.findScheduledFlight(direction, launchPadId, destinationId, launchDate)
+}
+
+// IsLaunchScheduleValid returns true if the schedule in force on the launch date has a flight in the direction between
+// the requested launch pad and the destination on that day of the week.
+func (m *Memory) IsLaunchScheduleValid(direction, launchPadId, destinationId string, launchDate time.Time) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.isLaunchScheduleValid(direction, launchPadId, destinationId, launchDate)
+}
+
+// CountBookings returns how many seats have been booked on the flight in the direction from or to the launchpad on the
+// given date.
+func (m *Memory) CountBookings(direction, launchPadId string, launchDate time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.countBookings(direction, launchPadId, launchDate)
+}
+
+// GetUpcoming returns the bookings that aren't marked as deleted for flights from the launchpad on or after the date.
+func (m *Memory) GetUpcoming(launchPadId string, from time.Time) ([]bookings.Booking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getUpcoming(launchPadId, from)
+}
+
+// FlagForReview marks a booking as needing review, recording why.
+func (m *Memory) FlagForReview(id, reason string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.flagForReview(id, reason)
+}
+
+// ClearDisruption moves a disrupted booking that isn't deleted back to the status, and clears its review flag.
+func (m *Memory) ClearDisruption(id, status string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.clearDisruption(id, status)
+}
+
+// SetStatus moves a booking to the status, recording the payment id unless it's empty.
+func (m *Memory) SetStatus(id, status, paymentId string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.setStatus(id, status, paymentId)
+}
+
+// ClaimCancellation moves a booking that isn't deleted from the status from to cancelling, recording why it's being
+// cancelled.
+func (m *Memory) ClaimCancellation(id, from, reason string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.claimCancellation(id, from, reason)
+}
+
+// Cancel marks a booking that isn't deleted as cancelled and deleted, recording why and what was refunded.
+func (m *Memory) Cancel(id, reason string, refundAmount *int64, refundId string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.cancel(id, reason, refundAmount, refundId)
+}
+
+// MoveStatus moves a booking that isn't deleted from one status to another, recording when it checked in or boarded.
+func (m *Memory) MoveStatus(id, from, to string, at time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.moveStatus(id, from, to, at)
+}
+
+// InTransaction runs fn while holding the write lock, restoring the previous data if fn returns an error.
+func (m *Memory) InTransaction(fn func(tx bookings.Booker) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := m.data.clone()
+
+	if err := fn(memoryTx{data: m.data}); err != nil {
+		m.data = snapshot
+		return err
+	}
+
+	return nil
+}
+
+func (t memoryTx) GetAll() ([]bookings.Booking, error) { return t.data.getAll() }
+
+func (t memoryTx) Get(id string) (*bookings.Booking, error) { return t.data.get(id) }
+
+func (t memoryTx) GetReturn(outboundBookingId string) (*bookings.Booking, error) {
+	return t.data.getReturn(outboundBookingId)
+}
+
+func (t memoryTx) Create(booking bookings.Booking) (*bookings.Booking, error) {
+	return t.data.create(booking)
+}
+
+func (t memoryTx) Delete(id string) (int64, error) { return t.data.delete(id) }
+
+func (t memoryTx) GetLaunchPad(id string) (*bookings.LaunchPad, error) {
+	return t.data.getLaunchPad(id)
+}
+
+func (t memoryTx) FindScheduledFlight(direction, launchPadId, destinationId string, launchDate time.Time) (*bookings.ScheduleEntry, error) {
+	return t.data.findScheduledFlight(direction, launchPadId, destinationId, launchDate)
+}
+
+func (t memoryTx) IsLaunchScheduleValid(direction, launchPadId, destinationId string, launchDate time.Time) (bool, error) {
+	return t.data.isLaunchScheduleValid(direction, launchPadId, destinationId, launchDate)
+}
+
+func (t memoryTx) CountBookings(direction, launchPadId string, launchDate time.Time) (int, error) {
+	return t.data.countBookings(direction, launchPadId, launchDate)
+}
+
+func (t memoryTx) GetUpcoming(launchPadId string, from time.Time) ([]bookings.Booking, error) {
+	return t.data.getUpcoming(launchPadId, from)
+}
+
+func (t memoryTx) FlagForReview(id, reason string) (int64, error) {
+	return t.data.flagForReview(id, reason)
+}
+
+func (t memoryTx) ClearDisruption(id, status string) (int64, error) {
+	return t.data.clearDisruption(id, status)
+}
+
+func (t memoryTx) SetStatus(id, status, paymentId string) (int64, error) {
+	return t.data.setStatus(id, status, paymentId)
+}
+
+func (t memoryTx) ClaimCancellation(id, from, reason string) (int64, error) {
+	return t.data.claimCancellation(id, from, reason)
+}
+
+func (t memoryTx) Cancel(id, reason string, refundAmount *int64, refundId string) (int64, error) {
+	return t.data.cancel(id, reason, refundAmount, refundId)
+}
+
+func (t memoryTx) MoveStatus(id, from, to string, at time.Time) (int64, error) {
+	return t.data.moveStatus(id, from, to, at)
+}
+
+// InTransaction runs fn in the transaction that's already in progress.
+func (t memoryTx) InTransaction(fn func(tx bookings.Booker) error) error {
+	return fn(t)
+}
+
+func (d *memoryData) getAll() ([]bookings.Booking, error) {
+	results := []bookings.Booking{}
+	for _, booking := range d.bookings {
+		if !booking.Deleted {
+			results = append(results, booking)
+		}
+	}
+
+	return results, nil
+}
+
+func (d *memoryData) get(id string) (*bookings.Booking, error) {
+	for _, booking := range d.bookings {
+		if booking.Id == id {
+			return &booking, nil
+		}
+	}
+
+	return nil, fmt.Errorf("booking %s: %w", id, bookings.ErrNotFound)
+}
+
+func (d *memoryData) getReturn(outboundBookingId string) (*bookings.Booking, error) {
+	for _, booking := range d.bookings {
+		if !booking.Deleted && booking.OutboundBookingId == outboundBookingId {
+			return &booking, nil
+		}
+	}
+
+	return nil, fmt.Errorf("return flight for booking %s: %w", outboundBookingId, bookings.ErrNotFound)
+}
+
+func (d *memoryData) create(booking bookings.Booking) (*bookings.Booking, error) {
+	now := time.Now().UTC()
+	booking.Id = uuid.NewString()
+	if len(booking.Direction) == 0 {
+		booking.Direction = bookings.DirectionOutbound
+	}
+	if len(booking.Status) == 0 {
+		booking.Status = bookings.StatusPending
+	}
+	booking.CreatedAt = now
+	booking.UpdatedAt = now
+
+	d.bookings = append(d.bookings, booking)
+
+	return &booking, nil
+}
+
+func (d *memoryData) delete(id string) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		if d.bookings[i].Id == id {
+			d.bookings[i].Deleted = true
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) getLaunchPad(id string) (*bookings.LaunchPad, error) {
+	launchPad, ok := d.launchPads[id]
+	if !ok {
+		return nil, fmt.Errorf("launchpad %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return &launchPad, nil
+}
+
+func (d *memoryData) findScheduledFlight(direction, launchPadId, destinationId string, launchDate time.Time) (*bookings.ScheduleEntry, error) {
+	dayOfWeek := launchDate.Weekday().String()
+	for _, entry := range d.schedule {
+		if entry.Direction == direction && entry.LaunchPadId == launchPadId && entry.DestinationId == destinationId &&
+			entry.DayOfWeek == dayOfWeek && entry.InEffect(launchDate) {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("scheduled flight from launchpad %s: %w", launchPadId, bookings.ErrNotFound)
+}
+
+func (d *memoryData) isLaunchScheduleValid(direction, launchPadId, destinationId string, launchDate time.Time) (bool, error) {
+	_, err := d.findScheduledFlight(direction, launchPadId, destinationId, launchDate)
+	if errors.Is(err, bookings.ErrNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (d *memoryData) getUpcoming(launchPadId string, from time.Time) ([]bookings.Booking, error) {
+	results := []bookings.Booking{}
+	for _, booking := range d.bookings {
+		if !booking.Deleted && booking.LaunchPadId == launchPadId && !booking.LaunchDate.Before(from) {
+			results = append(results, booking)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].LaunchDate.Before(results[j].LaunchDate) })
+
+	return results, nil
+}
+
+func (d *memoryData) flagForReview(id, reason string) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		if d.bookings[i].Id == id {
+			d.bookings[i].ReviewRequired = true
+			d.bookings[i].ReviewReason = reason
+			d.bookings[i].UpdatedAt = time.Now().UTC()
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) clearDisruption(id, status string) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		if d.bookings[i].Id == id && d.bookings[i].Status == bookings.StatusDisrupted && !d.bookings[i].Deleted {
+			d.bookings[i].Status = status
+			d.bookings[i].ReviewRequired = false
+			d.bookings[i].ReviewReason = ""
+			d.bookings[i].UpdatedAt = time.Now().UTC()
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) setStatus(id, status, paymentId string) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		if d.bookings[i].Id == id {
+			d.bookings[i].Status = status
+			if len(paymentId) > 0 {
+				d.bookings[i].PaymentId = paymentId
+			}
+			d.bookings[i].UpdatedAt = time.Now().UTC()
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) claimCancellation(id, from, reason string) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		if d.bookings[i].Id == id && d.bookings[i].Status == from && !d.bookings[i].Deleted {
+			d.bookings[i].Status = bookings.StatusCancelling
+			d.bookings[i].CancellationReason = reason
+			d.bookings[i].UpdatedAt = time.Now().UTC()
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) cancel(id, reason string, refundAmount *int64, refundId string) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		if d.bookings[i].Id == id && !d.bookings[i].Deleted {
+			d.bookings[i].Status = bookings.StatusCancelled
+			d.bookings[i].CancellationReason = reason
+			d.bookings[i].RefundAmount = refundAmount
+			d.bookings[i].RefundId = refundId
+			d.bookings[i].Deleted = true
+			d.bookings[i].UpdatedAt = time.Now().UTC()
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) moveStatus(id, from, to string, at time.Time) (int64, error) {
+	var rowsAffected int64
+	for i := range d.bookings {
+		booking := &d.bookings[i]
+		if booking.Id == id && booking.Status == from && !booking.Deleted {
+			at := at.UTC()
+			switch to {
+			case bookings.StatusCheckedIn:
+				booking.CheckedInAt = &at
+			case bookings.StatusBoarded:
+				booking.BoardedAt = &at
+			}
+			booking.Status = to
+			booking.UpdatedAt = time.Now().UTC()
+			rowsAffected++
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+func (d *memoryData) countBookings(direction, launchPadId string, launchDate time.Time) (int, error) {
+	count := 0
+	for _, booking := range d.bookings {
+		if !booking.Deleted && booking.Direction == direction && booking.LaunchPadId == launchPadId && booking.LaunchDate.Equal(launchDate) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// clone returns a copy of the data that doesn't share any slices or maps with it.
+func (d *memoryData) clone() *memoryData {
+	c := &memoryData{
+		bookings:         append([]bookings.Booking(nil), d.bookings...),
+		launchPads:       make(map[string]bookings.LaunchPad, len(d.launchPads)),
+		destinations:     make(map[string]bookings.Destination, len(d.destinations)),
+		schedule:         append([]bookings.ScheduleEntry(nil), d.schedule...),
+		exceptions:       append([]bookings.ScheduleException(nil), d.exceptions...),
+		travelTimes:      append([]bookings.TravelTime(nil), d.travelTimes...),
+		holds:            append([]bookings.Hold(nil), d.holds...),
+		fares:            append([]bookings.Fare(nil), d.fares...),
+		fareRules:        append([]bookings.FareRule(nil), d.fareRules...),
+		promoCodes:       make(map[string]bookings.PromoCode, len(d.promoCodes)),
+		waitlist:         append([]bookings.WaitlistEntry(nil), d.waitlist...),
+		eligibilityRules: append([]bookings.EligibilityRule(nil), d.eligibilityRules...),
+		externalLaunches: d.externalLaunches,
+	}
+	for k, v := range d.launchPads {
+		c.launchPads[k] = v
+	}
+	for k, v := range d.destinations {
+		c.destinations[k] = v
+	}
+	for k, v := range d.promoCodes {
+		c.promoCodes[k] = v
+	}
+
+	return c
+}
+
+// seed loads the seed data, the same launchpads, destinations, schedule, pricing and bookings that
+// database_structure.sql inserts.
+func (d *memoryData) seed() {
+	s := newSeed(time.Now().UTC())
+
+	for _, launchPad := range s.launchPads {
+		d.launchPads[launchPad.Id] = launchPad
+	}
+	for _, destination := range s.destinations {
+		d.destinations[destination.Id] = destination
+	}
+	for _, promo := range s.promoCodes {
+		d.promoCodes[promo.Code] = promo
+	}
+
+	d.schedule = s.schedule
+	d.bookings = s.bookings
+	d.fares = s.fares
+	d.fareRules = s.fareRules
+	d.eligibilityRules = s.eligibilityRules
+}
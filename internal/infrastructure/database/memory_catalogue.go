@@ -0,0 +1,322 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetLaunchPads returns every launchpad, including retired ones.
+func (m *Memory) GetLaunchPads() ([]bookings.LaunchPad, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getLaunchPads()
+}
+
+// CreateLaunchPad adds a new, active launchpad.
+func (m *Memory) CreateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createLaunchPad(launchPad)
+}
+
+// UpdateLaunchPad changes a launchpad's name, SpaceX launchpad id, seat capacity, timezone and whether it's active.
+func (m *Memory) UpdateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.updateLaunchPad(launchPad)
+}
+
+// RetireLaunchPad marks a launchpad as inactive so no more flights can be booked from it.
+func (m *Memory) RetireLaunchPad(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.retireLaunchPad(id)
+}
+
+// GetDestinations returns every destination, including retired ones.
+func (m *Memory) GetDestinations() ([]bookings.Destination, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getDestinations()
+}
+
+// GetDestination gets a destination by id.
+func (m *Memory) GetDestination(id string) (*bookings.Destination, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getDestination(id)
+}
+
+// CreateDestination adds a new, active destination.
+func (m *Memory) CreateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createDestination(destination)
+}
+
+// UpdateDestination changes a destination's name, travel time and whether it's active.
+func (m *Memory) UpdateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.updateDestination(destination)
+}
+
+// RetireDestination marks a destination as inactive so no more flights can be booked to it.
+func (m *Memory) RetireDestination(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.retireDestination(id)
+}
+
+// GetSchedule returns the launchpad's schedule, or every launchpad's schedule if launchPadId is empty.
+func (m *Memory) GetSchedule(launchPadId string) ([]bookings.ScheduleEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getSchedule(launchPadId)
+}
+
+// CreateScheduleEntry adds a new weekly flight to the schedule. An entry without a direction is an outbound flight.
+func (m *Memory) CreateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createScheduleEntry(entry)
+}
+
+// UpdateScheduleEntry changes the launchpad, day of the week, destination, direction, effective dates and launch window
+// of a weekly flight.
+func (m *Memory) UpdateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.updateScheduleEntry(entry)
+}
+
+// DeleteScheduleEntry removes a weekly flight from the schedule.
+func (m *Memory) DeleteScheduleEntry(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteScheduleEntry(id)
+}
+
+func (t memoryTx) GetLaunchPads() ([]bookings.LaunchPad, error) { return t.data.getLaunchPads() }
+
+func (t memoryTx) CreateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	return t.data.createLaunchPad(launchPad)
+}
+
+func (t memoryTx) UpdateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	return t.data.updateLaunchPad(launchPad)
+}
+
+func (t memoryTx) RetireLaunchPad(id string) (int64, error) { return t.data.retireLaunchPad(id) }
+
+func (t memoryTx) GetDestinations() ([]bookings.Destination, error) { return t.data.getDestinations() }
+
+func (t memoryTx) GetDestination(id string) (*bookings.Destination, error) {
+	return t.data.getDestination(id)
+}
+
+func (t memoryTx) CreateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	return t.data.createDestination(destination)
+}
+
+func (t memoryTx) UpdateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	return t.data.updateDestination(destination)
+}
+
+func (t memoryTx) RetireDestination(id string) (int64, error) { return t.data.retireDestination(id) }
+
+func (t memoryTx) GetSchedule(launchPadId string) ([]bookings.ScheduleEntry, error) {
+	return t.data.getSchedule(launchPadId)
+}
+
+func (t memoryTx) CreateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	return t.data.createScheduleEntry(entry)
+}
+
+func (t memoryTx) UpdateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	return t.data.updateScheduleEntry(entry)
+}
+
+func (t memoryTx) DeleteScheduleEntry(id string) (int64, error) {
+	return t.data.deleteScheduleEntry(id)
+}
+
+func (d *memoryData) getLaunchPads() ([]bookings.LaunchPad, error) {
+	results := make([]bookings.LaunchPad, 0, len(d.launchPads))
+	for _, launchPad := range d.launchPads {
+		results = append(results, launchPad)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].FullName < results[j].FullName })
+
+	return results, nil
+}
+
+func (d *memoryData) createLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	now := time.Now().UTC()
+	launchPad.Id = uuid.NewString()
+	launchPad.Active = true
+	launchPad.CreatedAt = now
+	launchPad.UpdatedAt = now
+
+	d.launchPads[launchPad.Id] = launchPad
+
+	return &launchPad, nil
+}
+
+func (d *memoryData) updateLaunchPad(launchPad bookings.LaunchPad) (*bookings.LaunchPad, error) {
+	existing, ok := d.launchPads[launchPad.Id]
+	if !ok {
+		return nil, fmt.Errorf("launchpad %s: %w", launchPad.Id, bookings.ErrNotFound)
+	}
+
+	launchPad.CreatedAt = existing.CreatedAt
+	launchPad.UpdatedAt = time.Now().UTC()
+	d.launchPads[launchPad.Id] = launchPad
+
+	return &launchPad, nil
+}
+
+func (d *memoryData) retireLaunchPad(id string) (int64, error) {
+	launchPad, ok := d.launchPads[id]
+	if !ok {
+		return 0, nil
+	}
+
+	launchPad.Active = false
+	launchPad.UpdatedAt = time.Now().UTC()
+	d.launchPads[id] = launchPad
+
+	return 1, nil
+}
+
+func (d *memoryData) getDestinations() ([]bookings.Destination, error) {
+	results := make([]bookings.Destination, 0, len(d.destinations))
+	for _, destination := range d.destinations {
+		results = append(results, destination)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	return results, nil
+}
+
+func (d *memoryData) getDestination(id string) (*bookings.Destination, error) {
+	destination, ok := d.destinations[id]
+	if !ok {
+		return nil, fmt.Errorf("destination %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return &destination, nil
+}
+
+func (d *memoryData) createDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	now := time.Now().UTC()
+	destination.Id = uuid.NewString()
+	destination.Active = true
+	destination.CreatedAt = now
+	destination.UpdatedAt = now
+
+	d.destinations[destination.Id] = destination
+
+	return &destination, nil
+}
+
+func (d *memoryData) updateDestination(destination bookings.Destination) (*bookings.Destination, error) {
+	existing, ok := d.destinations[destination.Id]
+	if !ok {
+		return nil, fmt.Errorf("destination %s: %w", destination.Id, bookings.ErrNotFound)
+	}
+
+	destination.CreatedAt = existing.CreatedAt
+	destination.UpdatedAt = time.Now().UTC()
+	d.destinations[destination.Id] = destination
+
+	return &destination, nil
+}
+
+func (d *memoryData) retireDestination(id string) (int64, error) {
+	destination, ok := d.destinations[id]
+	if !ok {
+		return 0, nil
+	}
+
+	destination.Active = false
+	destination.UpdatedAt = time.Now().UTC()
+	d.destinations[id] = destination
+
+	return 1, nil
+}
+
+func (d *memoryData) getSchedule(launchPadId string) ([]bookings.ScheduleEntry, error) {
+	results := []bookings.ScheduleEntry{}
+	for _, entry := range d.schedule {
+		if len(launchPadId) == 0 || entry.LaunchPadId == launchPadId {
+			results = append(results, entry)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].LaunchPadId != results[j].LaunchPadId {
+			return results[i].LaunchPadId < results[j].LaunchPadId
+		}
+		return results[i].DayOfWeek < results[j].DayOfWeek
+	})
+
+	return results, nil
+}
+
+func (d *memoryData) createScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	entry.Id = uuid.NewString()
+	entry.WindowOpens, entry.WindowCloses = entry.Window()
+	if len(entry.Direction) == 0 {
+		entry.Direction = bookings.DirectionOutbound
+	}
+
+	now := time.Now().UTC()
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	d.schedule = append(d.schedule, entry)
+
+	return &entry, nil
+}
+
+func (d *memoryData) updateScheduleEntry(entry bookings.ScheduleEntry) (*bookings.ScheduleEntry, error) {
+	entry.WindowOpens, entry.WindowCloses = entry.Window()
+
+	for i := range d.schedule {
+		if d.schedule[i].Id == entry.Id {
+			entry.CreatedAt = d.schedule[i].CreatedAt
+			entry.UpdatedAt = time.Now().UTC()
+			d.schedule[i] = entry
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("schedule entry %s: %w", entry.Id, bookings.ErrNotFound)
+}
+
+func (d *memoryData) deleteScheduleEntry(id string) (int64, error) {
+	for i := range d.schedule {
+		if d.schedule[i].Id == id {
+			d.schedule = append(d.schedule[:i], d.schedule[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetEligibilityRules returns every eligibility rule.
+func (m *Memory) GetEligibilityRules() ([]bookings.EligibilityRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getEligibilityRules()
+}
+
+// CreateEligibilityRule adds a new eligibility rule.
+func (m *Memory) CreateEligibilityRule(rule bookings.EligibilityRule) (*bookings.EligibilityRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createEligibilityRule(rule)
+}
+
+// DeleteEligibilityRule removes an eligibility rule.
+func (m *Memory) DeleteEligibilityRule(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteEligibilityRule(id)
+}
+
+// CountTrips counts the customer's outbound bookings that aren't deleted launching from from up to to. Names are
+// matched ignoring case.
+func (m *Memory) CountTrips(customer bookings.Customer, from, to time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.countTrips(customer, from, to)
+}
+
+func (t memoryTx) GetEligibilityRules() ([]bookings.EligibilityRule, error) {
+	return t.data.getEligibilityRules()
+}
+
+func (t memoryTx) CreateEligibilityRule(rule bookings.EligibilityRule) (*bookings.EligibilityRule, error) {
+	return t.data.createEligibilityRule(rule)
+}
+
+func (t memoryTx) DeleteEligibilityRule(id string) (int64, error) {
+	return t.data.deleteEligibilityRule(id)
+}
+
+func (t memoryTx) CountTrips(customer bookings.Customer, from, to time.Time) (int, error) {
+	return t.data.countTrips(customer, from, to)
+}
+
+func (d *memoryData) getEligibilityRules() ([]bookings.EligibilityRule, error) {
+	results := append([]bookings.EligibilityRule{}, d.eligibilityRules...)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Kind < results[j].Kind })
+
+	return results, nil
+}
+
+func (d *memoryData) createEligibilityRule(rule bookings.EligibilityRule) (*bookings.EligibilityRule, error) {
+	now := time.Now().UTC()
+	rule.Id = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	d.eligibilityRules = append(d.eligibilityRules, rule)
+
+	return &rule, nil
+}
+
+func (d *memoryData) deleteEligibilityRule(id string) (int64, error) {
+	for i := range d.eligibilityRules {
+		if d.eligibilityRules[i].Id == id {
+			d.eligibilityRules = append(d.eligibilityRules[:i], d.eligibilityRules[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (d *memoryData) countTrips(customer bookings.Customer, from, to time.Time) (int, error) {
+	count := 0
+	for _, booking := range d.bookings {
+		if booking.Deleted || booking.Direction != bookings.DirectionOutbound || !booking.Birthday.Equal(customer.Birthday) ||
+			!strings.EqualFold(booking.FirstName, customer.FirstName) || !strings.EqualFold(booking.LastName, customer.LastName) {
+			continue
+		}
+
+		if !booking.LaunchDate.Before(from) && booking.LaunchDate.Before(to) {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetScheduleExceptions returns the exceptions covering the date, or every exception if the date is zero.
+func (m *Memory) GetScheduleExceptions(date time.Time) ([]bookings.ScheduleException, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getScheduleExceptions(date)
+}
+
+// GetScheduleException gets a schedule exception by id.
+func (m *Memory) GetScheduleException(id string) (*bookings.ScheduleException, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getScheduleException(id)
+}
+
+// CreateScheduleException adds a new exception to the schedule.
+func (m *Memory) CreateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createScheduleException(exception)
+}
+
+// UpdateScheduleException changes every field of a schedule exception.
+func (m *Memory) UpdateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.updateScheduleException(exception)
+}
+
+// DeleteScheduleException removes an exception, so the weekly schedule applies again.
+func (m *Memory) DeleteScheduleException(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteScheduleException(id)
+}
+
+func (t memoryTx) GetScheduleExceptions(date time.Time) ([]bookings.ScheduleException, error) {
+	return t.data.getScheduleExceptions(date)
+}
+
+func (t memoryTx) GetScheduleException(id string) (*bookings.ScheduleException, error) {
+	return t.data.getScheduleException(id)
+}
+
+func (t memoryTx) CreateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	return t.data.createScheduleException(exception)
+}
+
+func (t memoryTx) UpdateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	return t.data.updateScheduleException(exception)
+}
+
+func (t memoryTx) DeleteScheduleException(id string) (int64, error) {
+	return t.data.deleteScheduleException(id)
+}
+
+func (d *memoryData) getScheduleExceptions(date time.Time) ([]bookings.ScheduleException, error) {
+	results := []bookings.ScheduleException{}
+	for _, exception := range d.exceptions {
+		if date.IsZero() || exception.Covers(date) {
+			results = append(results, exception)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].StartDate.Equal(results[j].StartDate) {
+			return results[i].StartDate.Before(results[j].StartDate)
+		}
+		return results[i].Kind < results[j].Kind
+	})
+
+	return results, nil
+}
+
+func (d *memoryData) getScheduleException(id string) (*bookings.ScheduleException, error) {
+	for _, exception := range d.exceptions {
+		if exception.Id == id {
+			return &exception, nil
+		}
+	}
+
+	return nil, fmt.Errorf("schedule exception %s: %w", id, bookings.ErrNotFound)
+}
+
+func (d *memoryData) createScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	now := time.Now().UTC()
+	exception.Id = uuid.NewString()
+	exception.CreatedAt = now
+	exception.UpdatedAt = now
+
+	d.exceptions = append(d.exceptions, exception)
+
+	return &exception, nil
+}
+
+func (d *memoryData) updateScheduleException(exception bookings.ScheduleException) (*bookings.ScheduleException, error) {
+	for i := range d.exceptions {
+		if d.exceptions[i].Id == exception.Id {
+			exception.CreatedAt = d.exceptions[i].CreatedAt
+			exception.UpdatedAt = time.Now().UTC()
+			d.exceptions[i] = exception
+			return &exception, nil
+		}
+	}
+
+	return nil, fmt.Errorf("schedule exception %s: %w", exception.Id, bookings.ErrNotFound)
+}
+
+func (d *memoryData) deleteScheduleException(id string) (int64, error) {
+	for i := range d.exceptions {
+		if d.exceptions[i].Id == id {
+			d.exceptions = append(d.exceptions[:i], d.exceptions[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
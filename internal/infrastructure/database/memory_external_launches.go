@@ -0,0 +1,48 @@
+package database
+
+import (
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// ReplaceExternalLaunches deletes the stored snapshot of SpaceX's launches and stores launches instead.
+func (m *Memory) ReplaceExternalLaunches(launches []bookings.ExternalLaunch) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.replaceExternalLaunches(launches)
+}
+
+// CountExternalLaunches counts the launches in the snapshot from the SpaceX launchpad at or after from and before to.
+func (m *Memory) CountExternalLaunches(spaceXLaunchPadId string, from, to time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.countExternalLaunches(spaceXLaunchPadId, from, to)
+}
+
+func (t memoryTx) ReplaceExternalLaunches(launches []bookings.ExternalLaunch) (int64, error) {
+	return t.data.replaceExternalLaunches(launches)
+}
+
+func (t memoryTx) CountExternalLaunches(spaceXLaunchPadId string, from, to time.Time) (int, error) {
+	return t.data.countExternalLaunches(spaceXLaunchPadId, from, to)
+}
+
+func (d *memoryData) replaceExternalLaunches(launches []bookings.ExternalLaunch) (int64, error) {
+	d.externalLaunches = append([]bookings.ExternalLaunch(nil), launches...)
+
+	return int64(len(launches)), nil
+}
+
+func (d *memoryData) countExternalLaunches(spaceXLaunchPadId string, from, to time.Time) (int, error) {
+	count := 0
+	for _, launch := range d.externalLaunches {
+		if launch.LaunchPadId == spaceXLaunchPadId && !launch.DateUTC.Before(from) && launch.DateUTC.Before(to) {
+			count++
+		}
+	}
+
+	return count, nil
+}
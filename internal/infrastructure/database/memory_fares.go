@@ -0,0 +1,253 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetFares returns every fare.
+func (m *Memory) GetFares() ([]bookings.Fare, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getFares()
+}
+
+// GetFare gets the fare for flights between the launchpad and destination.
+func (m *Memory) GetFare(launchPadId, destinationId string) (*bookings.Fare, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getFare(launchPadId, destinationId)
+}
+
+// SetFare sets the fare for flights between a launchpad and a destination, replacing any it already has.
+func (m *Memory) SetFare(fare bookings.Fare) (*bookings.Fare, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.setFare(fare)
+}
+
+// DeleteFare removes the fare for flights between a launchpad and a destination.
+func (m *Memory) DeleteFare(launchPadId, destinationId string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteFare(launchPadId, destinationId)
+}
+
+// GetFareRules returns every fare rule.
+func (m *Memory) GetFareRules() ([]bookings.FareRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getFareRules()
+}
+
+// CreateFareRule adds a new fare rule.
+func (m *Memory) CreateFareRule(rule bookings.FareRule) (*bookings.FareRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createFareRule(rule)
+}
+
+// DeleteFareRule removes a fare rule.
+func (m *Memory) DeleteFareRule(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteFareRule(id)
+}
+
+// GetPromoCodes returns every promo code.
+func (m *Memory) GetPromoCodes() ([]bookings.PromoCode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getPromoCodes()
+}
+
+// GetPromoCode gets a promo code, whether or not it can be used today.
+func (m *Memory) GetPromoCode(code string) (*bookings.PromoCode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getPromoCode(code)
+}
+
+// SetPromoCode adds a promo code, replacing it if it already exists.
+func (m *Memory) SetPromoCode(promo bookings.PromoCode) (*bookings.PromoCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.setPromoCode(promo)
+}
+
+// DeletePromoCode removes a promo code. Bookings already made with it keep their price.
+func (m *Memory) DeletePromoCode(code string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deletePromoCode(code)
+}
+
+func (t memoryTx) GetFares() ([]bookings.Fare, error) { return t.data.getFares() }
+
+func (t memoryTx) GetFare(launchPadId, destinationId string) (*bookings.Fare, error) {
+	return t.data.getFare(launchPadId, destinationId)
+}
+
+func (t memoryTx) SetFare(fare bookings.Fare) (*bookings.Fare, error) { return t.data.setFare(fare) }
+
+func (t memoryTx) DeleteFare(launchPadId, destinationId string) (int64, error) {
+	return t.data.deleteFare(launchPadId, destinationId)
+}
+
+func (t memoryTx) GetFareRules() ([]bookings.FareRule, error) { return t.data.getFareRules() }
+
+func (t memoryTx) CreateFareRule(rule bookings.FareRule) (*bookings.FareRule, error) {
+	return t.data.createFareRule(rule)
+}
+
+func (t memoryTx) DeleteFareRule(id string) (int64, error) { return t.data.deleteFareRule(id) }
+
+func (t memoryTx) GetPromoCodes() ([]bookings.PromoCode, error) { return t.data.getPromoCodes() }
+
+func (t memoryTx) GetPromoCode(code string) (*bookings.PromoCode, error) {
+	return t.data.getPromoCode(code)
+}
+
+func (t memoryTx) SetPromoCode(promo bookings.PromoCode) (*bookings.PromoCode, error) {
+	return t.data.setPromoCode(promo)
+}
+
+func (t memoryTx) DeletePromoCode(code string) (int64, error) { return t.data.deletePromoCode(code) }
+
+func (d *memoryData) getFares() ([]bookings.Fare, error) {
+	results := append([]bookings.Fare{}, d.fares...)
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].LaunchPadId != results[j].LaunchPadId {
+			return results[i].LaunchPadId < results[j].LaunchPadId
+		}
+		return results[i].DestinationId < results[j].DestinationId
+	})
+
+	return results, nil
+}
+
+func (d *memoryData) getFare(launchPadId, destinationId string) (*bookings.Fare, error) {
+	for _, fare := range d.fares {
+		if fare.LaunchPadId == launchPadId && fare.DestinationId == destinationId {
+			return &fare, nil
+		}
+	}
+
+	return nil, fmt.Errorf("fare from launchpad %s to destination %s: %w", launchPadId, destinationId, bookings.ErrNotFound)
+}
+
+func (d *memoryData) setFare(fare bookings.Fare) (*bookings.Fare, error) {
+	now := time.Now().UTC()
+	fare.UpdatedAt = now
+
+	for i := range d.fares {
+		if d.fares[i].LaunchPadId == fare.LaunchPadId && d.fares[i].DestinationId == fare.DestinationId {
+			fare.CreatedAt = d.fares[i].CreatedAt
+			d.fares[i] = fare
+			return &fare, nil
+		}
+	}
+
+	fare.CreatedAt = now
+	d.fares = append(d.fares, fare)
+
+	return &fare, nil
+}
+
+func (d *memoryData) deleteFare(launchPadId, destinationId string) (int64, error) {
+	for i := range d.fares {
+		if d.fares[i].LaunchPadId == launchPadId && d.fares[i].DestinationId == destinationId {
+			d.fares = append(d.fares[:i], d.fares[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (d *memoryData) getFareRules() ([]bookings.FareRule, error) {
+	results := append([]bookings.FareRule{}, d.fareRules...)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Kind < results[j].Kind })
+
+	return results, nil
+}
+
+func (d *memoryData) createFareRule(rule bookings.FareRule) (*bookings.FareRule, error) {
+	now := time.Now().UTC()
+	rule.Id = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	d.fareRules = append(d.fareRules, rule)
+
+	return &rule, nil
+}
+
+func (d *memoryData) deleteFareRule(id string) (int64, error) {
+	for i := range d.fareRules {
+		if d.fareRules[i].Id == id {
+			d.fareRules = append(d.fareRules[:i], d.fareRules[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (d *memoryData) getPromoCodes() ([]bookings.PromoCode, error) {
+	results := make([]bookings.PromoCode, 0, len(d.promoCodes))
+	for _, promo := range d.promoCodes {
+		results = append(results, promo)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Code < results[j].Code })
+
+	return results, nil
+}
+
+func (d *memoryData) getPromoCode(code string) (*bookings.PromoCode, error) {
+	promo, ok := d.promoCodes[code]
+	if !ok {
+		return nil, fmt.Errorf("promo code %s: %w", code, bookings.ErrNotFound)
+	}
+
+	return &promo, nil
+}
+
+func (d *memoryData) setPromoCode(promo bookings.PromoCode) (*bookings.PromoCode, error) {
+	now := time.Now().UTC()
+	promo.CreatedAt = now
+	promo.UpdatedAt = now
+
+	if existing, ok := d.promoCodes[promo.Code]; ok {
+		promo.CreatedAt = existing.CreatedAt
+	}
+
+	d.promoCodes[promo.Code] = promo
+
+	return &promo, nil
+}
+
+func (d *memoryData) deletePromoCode(code string) (int64, error) {
+	if _, ok := d.promoCodes[code]; !ok {
+		return 0, nil
+	}
+
+	delete(d.promoCodes, code)
+
+	return 1, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetHold gets a hold by id, even if it has expired.
+func (m *Memory) GetHold(id string) (*bookings.Hold, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getHold(id)
+}
+
+// CreateHold adds a new hold.
+func (m *Memory) CreateHold(hold bookings.Hold) (*bookings.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createHold(hold)
+}
+
+// DeleteHold removes a hold, releasing its seat.
+func (m *Memory) DeleteHold(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteHold(id)
+}
+
+// CountHolds returns how many seats are held on the flight in the direction from or to the launchpad on the given
+// date by holds that hadn't expired by now.
+func (m *Memory) CountHolds(direction, launchPadId string, launchDate, now time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.countHolds(direction, launchPadId, launchDate, now)
+}
+
+// DeleteExpiredHolds removes the holds that had expired by now.
+func (m *Memory) DeleteExpiredHolds(now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteExpiredHolds(now)
+}
+
+func (t memoryTx) GetHold(id string) (*bookings.Hold, error) { return t.data.getHold(id) }
+
+func (t memoryTx) CreateHold(hold bookings.Hold) (*bookings.Hold, error) {
+	return t.data.createHold(hold)
+}
+
+func (t memoryTx) DeleteHold(id string) (int64, error) { return t.data.deleteHold(id) }
+
+func (t memoryTx) CountHolds(direction, launchPadId string, launchDate, now time.Time) (int, error) {
+	return t.data.countHolds(direction, launchPadId, launchDate, now)
+}
+
+func (t memoryTx) DeleteExpiredHolds(now time.Time) (int64, error) {
+	return t.data.deleteExpiredHolds(now)
+}
+
+func (d *memoryData) getHold(id string) (*bookings.Hold, error) {
+	for _, hold := range d.holds {
+		if hold.Id == id {
+			return &hold, nil
+		}
+	}
+
+	return nil, fmt.Errorf("hold %s: %w", id, bookings.ErrNotFound)
+}
+
+func (d *memoryData) createHold(hold bookings.Hold) (*bookings.Hold, error) {
+	hold.Id = uuid.NewString()
+	if len(hold.Direction) == 0 {
+		hold.Direction = bookings.DirectionOutbound
+	}
+	hold.ExpiresAt = hold.ExpiresAt.UTC()
+	hold.CreatedAt = time.Now().UTC()
+
+	d.holds = append(d.holds, hold)
+
+	return &hold, nil
+}
+
+func (d *memoryData) deleteHold(id string) (int64, error) {
+	for i := range d.holds {
+		if d.holds[i].Id == id {
+			d.holds = append(d.holds[:i], d.holds[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (d *memoryData) countHolds(direction, launchPadId string, launchDate, now time.Time) (int, error) {
+	count := 0
+	for _, hold := range d.holds {
+		if hold.Direction == direction && hold.LaunchPadId == launchPadId && hold.LaunchDate.Equal(launchDate) && !hold.Expired(now) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (d *memoryData) deleteExpiredHolds(now time.Time) (int64, error) {
+	var rowsAffected int64
+	kept := d.holds[:0]
+	for _, hold := range d.holds {
+		if hold.Expired(now) {
+			rowsAffected++
+			continue
+		}
+		kept = append(kept, hold)
+	}
+	d.holds = kept
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+func TestMemory_Seed(t *testing.T) {
+	m, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(m.data.launchPads); got != 6 {
+		t.Errorf("wrong number of launchpads, got %d want %d", got, 6)
+	}
+	if got := len(m.data.destinations); got != 7 {
+		t.Errorf("wrong number of destinations, got %d want %d", got, 7)
+	}
+	if got := len(m.data.schedule); got != 49 {
+		t.Errorf("wrong number of schedule entries, got %d want %d", got, 49)
+	}
+
+	launchPad, err := m.GetLaunchPad("4079f070-3e58-4e61-8af7-05c8de8e1fbf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if launchPad.SpaceXLaunchPadId != "5e9e4502f509094188566f88" {
+		t.Errorf("wrong spacex launchpad id, got %s want %s", launchPad.SpaceXLaunchPadId, "5e9e4502f509094188566f88")
+	}
+
+	all, err := m.GetAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 1 || all[0].FirstName != "Brian" {
+		t.Errorf("wrong seeded bookings, got %+v", all)
+	}
+}
+
+func TestMemory_IsLaunchScheduleValid(t *testing.T) {
+	tests := []struct {
+		name          string
+		launchPadId   string
+		launchDate    string
+		destinationId string
+		want          bool
+	}{
+		{
+			name:          "1. Cape Canaveral flies to the Moon on Mondays",
+			launchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+			launchDate:    "2024-01-01",
+			destinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+			want:          true,
+		},
+		{
+			name:          "2. Cape Canaveral doesn't fly to the Moon on Tuesdays",
+			launchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+			launchDate:    "2024-01-02",
+			destinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+			want:          false,
+		},
+		{
+			name:          "3. Cape Canaveral's Monday flight to the Moon ends after March",
+			launchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+			launchDate:    "2024-04-01",
+			destinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+			want:          false,
+		},
+		{
+			name:          "4. Cape Canaveral flies to the Moon on Thursdays from April",
+			launchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+			launchDate:    "2024-04-04",
+			destinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+			want:          true,
+		},
+	}
+
+	m, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	endOfMarch, _ := time.Parse(time.DateOnly, "2024-03-31")
+	april, _ := time.Parse(time.DateOnly, "2024-04-01")
+
+	schedule, _ := m.GetSchedule("b542c0cf-7fe3-4bb1-a63f-7cbdf8359975")
+	for _, entry := range schedule {
+		if entry.DayOfWeek == "Monday" || entry.DayOfWeek == "Thursday" {
+			entry.EffectiveTo = &endOfMarch
+			if _, err := m.UpdateScheduleEntry(entry); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if _, err := m.CreateScheduleEntry(bookings.ScheduleEntry{
+		LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+		DayOfWeek:     "Thursday",
+		DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+		EffectiveFrom: &april,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, tt.launchDate)
+
+			got, err := m.IsLaunchScheduleValid(bookings.DirectionOutbound, tt.launchPadId, tt.destinationId, launchDate)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("wrong value, got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemory_CreateAndDelete(t *testing.T) {
+	m, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+	var wg sync.WaitGroup
+	created := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			booking, err := m.Create(bookings.Booking{
+				Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson"},
+				LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+				DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+				LaunchDate:    launchDate,
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			created <- booking.Id
+		}()
+	}
+	wg.Wait()
+	close(created)
+
+	all, _ := m.GetAll()
+	if len(all) != 11 {
+		t.Fatalf("wrong number of bookings, got %d want %d", len(all), 11)
+	}
+
+	id := <-created
+	rowsAffected, err := m.Delete(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rowsAffected != 1 {
+		t.Errorf("wrong rows affected, got %d want %d", rowsAffected, 1)
+	}
+
+	all, _ = m.GetAll()
+	if len(all) != 10 {
+		t.Errorf("wrong number of bookings after delete, got %d want %d", len(all), 10)
+	}
+
+	rowsAffected, _ = m.Delete("unknown")
+	if rowsAffected != 0 {
+		t.Errorf("wrong rows affected, got %d want %d", rowsAffected, 0)
+	}
+}
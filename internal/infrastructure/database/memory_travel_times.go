@@ -0,0 +1,118 @@
+package database
+
+import (
+	"sort"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetTravelTimes returns the launchpad's travel time overrides, or every launchpad's if launchPadId is empty.
+func (m *Memory) GetTravelTimes(launchPadId string) ([]bookings.TravelTime, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getTravelTimes(launchPadId)
+}
+
+// GetTravelDays returns how long flights between the launchpad and destination take, using the launchpad's travel time
+// override if there is one and the destination's travel time otherwise.
+func (m *Memory) GetTravelDays(launchPadId, destinationId string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getTravelDays(launchPadId, destinationId)
+}
+
+// SetTravelTime sets the travel time between a launchpad and a destination, replacing any it already has.
+func (m *Memory) SetTravelTime(travelTime bookings.TravelTime) (*bookings.TravelTime, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.setTravelTime(travelTime)
+}
+
+// DeleteTravelTime removes the travel time between a launchpad and a destination, so the destination's applies again.
+func (m *Memory) DeleteTravelTime(launchPadId, destinationId string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.deleteTravelTime(launchPadId, destinationId)
+}
+
+func (t memoryTx) GetTravelTimes(launchPadId string) ([]bookings.TravelTime, error) {
+	return t.data.getTravelTimes(launchPadId)
+}
+
+func (t memoryTx) GetTravelDays(launchPadId, destinationId string) (int, error) {
+	return t.data.getTravelDays(launchPadId, destinationId)
+}
+
+func (t memoryTx) SetTravelTime(travelTime bookings.TravelTime) (*bookings.TravelTime, error) {
+	return t.data.setTravelTime(travelTime)
+}
+
+func (t memoryTx) DeleteTravelTime(launchPadId, destinationId string) (int64, error) {
+	return t.data.deleteTravelTime(launchPadId, destinationId)
+}
+
+func (d *memoryData) getTravelTimes(launchPadId string) ([]bookings.TravelTime, error) {
+	results := []bookings.TravelTime{}
+	for _, travelTime := range d.travelTimes {
+		if len(launchPadId) == 0 || travelTime.LaunchPadId == launchPadId {
+			results = append(results, travelTime)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].LaunchPadId != results[j].LaunchPadId {
+			return results[i].LaunchPadId < results[j].LaunchPadId
+		}
+		return results[i].DestinationId < results[j].DestinationId
+	})
+
+	return results, nil
+}
+
+func (d *memoryData) getTravelDays(launchPadId, destinationId string) (int, error) {
+	destination, err := d.getDestination(destinationId)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, travelTime := range d.travelTimes {
+		if travelTime.LaunchPadId == launchPadId && travelTime.DestinationId == destinationId {
+			return travelTime.TravelDays, nil
+		}
+	}
+
+	return destination.TravelDays, nil
+}
+
+func (d *memoryData) setTravelTime(travelTime bookings.TravelTime) (*bookings.TravelTime, error) {
+	now := time.Now().UTC()
+	travelTime.UpdatedAt = now
+
+	for i := range d.travelTimes {
+		if d.travelTimes[i].LaunchPadId == travelTime.LaunchPadId && d.travelTimes[i].DestinationId == travelTime.DestinationId {
+			travelTime.CreatedAt = d.travelTimes[i].CreatedAt
+			d.travelTimes[i] = travelTime
+			return &travelTime, nil
+		}
+	}
+
+	travelTime.CreatedAt = now
+	d.travelTimes = append(d.travelTimes, travelTime)
+
+	return &travelTime, nil
+}
+
+func (d *memoryData) deleteTravelTime(launchPadId, destinationId string) (int64, error) {
+	for i := range d.travelTimes {
+		if d.travelTimes[i].LaunchPadId == launchPadId && d.travelTimes[i].DestinationId == destinationId {
+			d.travelTimes = append(d.travelTimes[:i], d.travelTimes[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetWaitlist returns the entries still waiting for flights launching on or after from, oldest first.
+func (m *Memory) GetWaitlist(from time.Time) ([]bookings.WaitlistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getWaitlist(from)
+}
+
+// GetWaitlistEntry gets a waitlist entry by id, whatever its status.
+func (m *Memory) GetWaitlistEntry(id string) (*bookings.WaitlistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.data.getWaitlistEntry(id)
+}
+
+// CreateWaitlistEntry adds a new entry to the end of its flight's waitlist.
+func (m *Memory) CreateWaitlistEntry(entry bookings.WaitlistEntry) (*bookings.WaitlistEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.createWaitlistEntry(entry)
+}
+
+// SetWaitlistStatus moves a waitlist entry to the status, recording the booking it was promoted to if bookingId isn't
+// empty.
+func (m *Memory) SetWaitlistStatus(id, status, bookingId string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data.setWaitlistStatus(id, status, bookingId)
+}
+
+func (t memoryTx) GetWaitlist(from time.Time) ([]bookings.WaitlistEntry, error) {
+	return t.data.getWaitlist(from)
+}
+
+func (t memoryTx) GetWaitlistEntry(id string) (*bookings.WaitlistEntry, error) {
+	return t.data.getWaitlistEntry(id)
+}
+
+func (t memoryTx) CreateWaitlistEntry(entry bookings.WaitlistEntry) (*bookings.WaitlistEntry, error) {
+	return t.data.createWaitlistEntry(entry)
+}
+
+func (t memoryTx) SetWaitlistStatus(id, status, bookingId string) (int64, error) {
+	return t.data.setWaitlistStatus(id, status, bookingId)
+}
+
+// getWaitlist relies on entries being appended in the order they're created, so they're already oldest first.
+func (d *memoryData) getWaitlist(from time.Time) ([]bookings.WaitlistEntry, error) {
+	results := []bookings.WaitlistEntry{}
+	for _, entry := range d.waitlist {
+		if entry.Status == bookings.WaitlistWaiting && !entry.LaunchDate.Before(from) {
+			results = append(results, entry)
+		}
+	}
+
+	return results, nil
+}
+
+func (d *memoryData) getWaitlistEntry(id string) (*bookings.WaitlistEntry, error) {
+	for _, entry := range d.waitlist {
+		if entry.Id == id {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("waitlist entry %s: %w", id, bookings.ErrNotFound)
+}
+
+func (d *memoryData) createWaitlistEntry(entry bookings.WaitlistEntry) (*bookings.WaitlistEntry, error) {
+	now := time.Now().UTC()
+	entry.Id = uuid.NewString()
+	if len(entry.Direction) == 0 {
+		entry.Direction = bookings.DirectionOutbound
+	}
+	if len(entry.Status) == 0 {
+		entry.Status = bookings.WaitlistWaiting
+	}
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+
+	d.waitlist = append(d.waitlist, entry)
+
+	return &entry, nil
+}
+
+func (d *memoryData) setWaitlistStatus(id, status, bookingId string) (int64, error) {
+	for i := range d.waitlist {
+		if d.waitlist[i].Id == id {
+			d.waitlist[i].Status = status
+			if len(bookingId) > 0 {
+				d.waitlist[i].BookingId = bookingId
+			}
+			d.waitlist[i].UpdatedAt = time.Now().UTC()
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -14,7 +14,24 @@ const databaseDriver = "postgres"
 
 // PostGres encapsulates objects needed to interact with a PostGres database.
 type PostGres struct {
+	sqlStore
+}
+
+// sqlStore implements bookings.Booker with queries that run on both PostGres and SQLite.
+type sqlStore struct {
 	Repo *sql.DB
+	// conn runs queries, either directly against Repo or inside tx.
+	conn querier
+	tx   *sql.Tx
+	// forUpdate is appended to queries that lock rows inside a transaction.
+	forUpdate string
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
 }
 
 // New returns a new PostGres.
@@ -28,12 +45,12 @@ func New(cfg config.Config) (*PostGres, error) {
 	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
 	db.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeSecs) * time.Second)
 
-	return &PostGres{Repo: db}, nil
+	return &PostGres{sqlStore{Repo: db, conn: db, forUpdate: " FOR UPDATE"}}, nil
 }
 
 // Close closes the connection to the database.
-func (p *PostGres) Close() {
-	p.Repo.Close()
+func (s *sqlStore) Close() {
+	s.Repo.Close()
 }
 
 func connect(cfg config.Config) (*sql.DB, error) {
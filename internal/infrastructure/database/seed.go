@@ -0,0 +1,168 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// The ids of the seeded launchpads and destinations.
+const (
+	vandenberg3WId  = "d95c83bb-be3f-4bdb-93fe-77015d95f759"
+	capeCanaveralId = "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975"
+	southTexasId    = "b09e0b80-51ca-44ac-820a-d5b95b209cad"
+	omelekId        = "e169113a-ae89-4c39-9a28-3cbc1c96e5e0"
+	vandenberg4EId  = "9f8cb517-ca3b-4810-baef-80b48b8cf5e6"
+	kennedy39AId    = "4079f070-3e58-4e61-8af7-05c8de8e1fbf"
+
+	moonId         = "466fc378-14eb-4ed9-8bec-d29abe54c5a9"
+	marsId         = "f47eef79-675f-46da-86f9-ee598185d204"
+	plutoId        = "fbd40165-03c7-47a5-be72-c79f81ebbf67"
+	asteroidBeltId = "13b91e0c-cdb4-4108-9c48-5a49d8ded732"
+	europaId       = "998f4a82-5a1c-4542-8497-e3fa24618d79"
+	titanId        = "12549fca-d086-4e9f-b14e-dcb3b0d09c63"
+	ganymedeId     = "3840d5ce-b939-4af7-9dd8-ac12c09d1493"
+)
+
+// seedWeek is where a launchpad flies each day of the week, from Sunday.
+type seedWeek struct {
+	launchPadId  string
+	direction    string
+	destinations [7]string
+}
+
+// seedSchedule is the seeded weekly schedule. Every launchpad flies out to a destination each day, and Cape Canaveral
+// also has a return flight landing each day.
+var seedSchedule = []seedWeek{
+	{vandenberg3WId, bookings.DirectionOutbound, [7]string{moonId, marsId, plutoId, asteroidBeltId, europaId, titanId, ganymedeId}},
+	{capeCanaveralId, bookings.DirectionOutbound, [7]string{ganymedeId, moonId, marsId, plutoId, asteroidBeltId, europaId, titanId}},
+	{southTexasId, bookings.DirectionOutbound, [7]string{titanId, ganymedeId, moonId, marsId, plutoId, asteroidBeltId, europaId}},
+	{omelekId, bookings.DirectionOutbound, [7]string{europaId, titanId, ganymedeId, moonId, marsId, plutoId, asteroidBeltId}},
+	{vandenberg4EId, bookings.DirectionOutbound, [7]string{asteroidBeltId, europaId, titanId, ganymedeId, moonId, marsId, plutoId}},
+	{kennedy39AId, bookings.DirectionOutbound, [7]string{plutoId, asteroidBeltId, europaId, titanId, ganymedeId, moonId, marsId}},
+	{capeCanaveralId, bookings.DirectionReturn, [7]string{ganymedeId, moonId, marsId, plutoId, asteroidBeltId, europaId, titanId}},
+}
+
+// seedFares is the base price in US cents of a seat to each destination, from every launchpad.
+var seedFares = []struct {
+	destinationId string
+	basePrice     int64
+}{
+	{moonId, 25000000},
+	{marsId, 150000000},
+	{plutoId, 900000000},
+	{asteroidBeltId, 200000000},
+	{europaId, 600000000},
+	{titanId, 700000000},
+	{ganymedeId, 600000000},
+}
+
+// seed is the data every store starts with. database_structure.sql seeds Postgres with the same rows, so the two need
+// changing together, and TestSeed fails if they don't match.
+type seed struct {
+	launchPads       []bookings.LaunchPad
+	destinations     []bookings.Destination
+	schedule         []bookings.ScheduleEntry
+	bookings         []bookings.Booking
+	fares            []bookings.Fare
+	fareRules        []bookings.FareRule
+	promoCodes       []bookings.PromoCode
+	eligibilityRules []bookings.EligibilityRule
+}
+
+// newSeed returns the seed data, created and updated at now. Rows that aren't looked up by a fixed id are given new ids.
+func newSeed(now time.Time) seed {
+	s := seed{
+		launchPads: []bookings.LaunchPad{
+			{Id: vandenberg3WId, FullName: "Vandenberg Space Force Base Space Launch Complex 3W", SpaceXLaunchPadId: "5e9e4501f5090910d4566f83", SeatCapacity: 50, Timezone: "America/Los_Angeles"},
+			{Id: capeCanaveralId, FullName: "Cape Canaveral Space Force Station Space Launch Complex 40", SpaceXLaunchPadId: "5e9e4501f509094ba4566f84", SeatCapacity: 100, Timezone: "America/New_York"},
+			{Id: southTexasId, FullName: "SpaceX South Texas Launch Site", SpaceXLaunchPadId: "5e9e4502f5090927f8566f85", SeatCapacity: 100, Timezone: "America/Chicago"},
+			{Id: omelekId, FullName: "Kwajalein Atoll Omelek Island", SpaceXLaunchPadId: "5e9e4502f5090995de566f86", SeatCapacity: 20, Timezone: "Pacific/Kwajalein"},
+			{Id: vandenberg4EId, FullName: "Vandenberg Space Force Base Space Launch Complex 4E", SpaceXLaunchPadId: "5e9e4502f509092b78566f87", SeatCapacity: 50, Timezone: "America/Los_Angeles"},
+			{Id: kennedy39AId, FullName: "Kennedy Space Center Historic Launch Complex 39A", SpaceXLaunchPadId: "5e9e4502f509094188566f88", SeatCapacity: 100, Timezone: "America/New_York"},
+		},
+		destinations: []bookings.Destination{
+			{Id: moonId, Name: "Moon", TravelDays: 3},
+			{Id: marsId, Name: "Mars", TravelDays: 210},
+			{Id: plutoId, Name: "Pluto", TravelDays: 3500},
+			{Id: asteroidBeltId, Name: "Asteroid Belt", TravelDays: 450},
+			{Id: europaId, Name: "Europa", TravelDays: 2200},
+			{Id: titanId, Name: "Titan", TravelDays: 2600},
+			{Id: ganymedeId, Name: "Ganymede", TravelDays: 2200},
+		},
+		bookings: []bookings.Booking{
+			{
+				Id: uuid.NewString(),
+				Customer: bookings.Customer{FirstName: "Brian", LastName: "Blessed", Gender: "Male",
+					Birthday: time.Date(1936, time.October, 9, 0, 0, 0, 0, time.UTC)},
+				LaunchPadId:   vandenberg3WId,
+				DestinationId: moonId,
+				LaunchDate:    time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC),
+				Direction:     bookings.DirectionOutbound,
+				Status:        bookings.StatusConfirmed,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			},
+		},
+		fareRules: []bookings.FareRule{
+			{Id: uuid.NewString(), Kind: bookings.FareRuleDayOfWeek, DayOfWeek: "Saturday", Multiplier: 1.2, Reason: "weekend", CreatedAt: now, UpdatedAt: now},
+			{Id: uuid.NewString(), Kind: bookings.FareRuleDayOfWeek, DayOfWeek: "Sunday", Multiplier: 1.2, Reason: "weekend", CreatedAt: now, UpdatedAt: now},
+			{Id: uuid.NewString(), Kind: bookings.FareRuleSeason, StartDate: seedDate(2026, time.December, 19), EndDate: seedDate(2027, time.January, 3),
+				Multiplier: 1.5, Reason: "holiday season", CreatedAt: now, UpdatedAt: now},
+		},
+		promoCodes: []bookings.PromoCode{
+			{Code: "WELCOME10", PercentOff: 10, ValidFrom: *seedDate(2024, time.January, 1), ValidTo: *seedDate(2030, time.December, 31), CreatedAt: now, UpdatedAt: now},
+		},
+		eligibilityRules: []bookings.EligibilityRule{
+			{Id: uuid.NewString(), Kind: bookings.EligibilityMinAge, Value: 2, Reason: "infants are too young for launch", CreatedAt: now, UpdatedAt: now},
+			{Id: uuid.NewString(), Kind: bookings.EligibilityMaxAge, Value: 90, Reason: "passengers over 90 cannot get medical clearance", CreatedAt: now, UpdatedAt: now},
+			{Id: uuid.NewString(), Kind: bookings.EligibilityMinAge, DestinationId: plutoId, Value: 18, Reason: "no minors on the long flight to Pluto", CreatedAt: now, UpdatedAt: now},
+		},
+	}
+
+	for i := range s.launchPads {
+		s.launchPads[i].Active, s.launchPads[i].CreatedAt, s.launchPads[i].UpdatedAt = true, now, now
+	}
+
+	for i := range s.destinations {
+		s.destinations[i].Active, s.destinations[i].CreatedAt, s.destinations[i].UpdatedAt = true, now, now
+	}
+
+	for _, week := range seedSchedule {
+		for day, destinationId := range week.destinations {
+			s.schedule = append(s.schedule, bookings.ScheduleEntry{
+				Id:            uuid.NewString(),
+				LaunchPadId:   week.launchPadId,
+				DayOfWeek:     time.Weekday(day).String(),
+				DestinationId: destinationId,
+				Direction:     week.direction,
+				WindowOpens:   bookings.DefaultWindowOpens,
+				WindowCloses:  bookings.DefaultWindowCloses,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+	}
+
+	for _, launchPad := range s.launchPads {
+		for _, fare := range seedFares {
+			s.fares = append(s.fares, bookings.Fare{
+				LaunchPadId:   launchPad.Id,
+				DestinationId: fare.destinationId,
+				BasePrice:     fare.basePrice,
+				Currency:      "USD",
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+	}
+
+	return s
+}
+
+// seedDate returns a pointer to midnight UTC on the date.
+func seedDate(year int, month time.Month, day int) *time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
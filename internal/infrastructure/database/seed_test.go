@@ -0,0 +1,157 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// newStructureSQLite returns an SQLite store holding only the rows database_structure.sql inserts into Postgres, so they
+// can be compared with the Go seed without a Postgres server.
+func newStructureSQLite(t *testing.T) *SQLite {
+	t.Helper()
+
+	structure, err := os.ReadFile("database_structure.sql")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_time_format=sqlite", filepath.Join(t.TempDir(), "structure.db"))
+	db, err := sql.Open(sqliteDriver, dsn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	// Postgres generates the ids the inserts leave out, so the SQLite tables are given a default id to do the same.
+	tables := strings.ReplaceAll(sqliteStructureSQL, "id text PRIMARY KEY NOT NULL",
+		"id text PRIMARY KEY NOT NULL DEFAULT (lower(hex(randomblob(16))))")
+	if _, err := db.Exec(tables); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, statement := range strings.Split(string(structure), ";\n") {
+		statement = strings.TrimSpace(statement)
+		if !strings.HasPrefix(statement, "INSERT INTO") {
+			continue
+		}
+
+		if _, err := db.Exec(strings.ReplaceAll(statement, "NOW()", "CURRENT_TIMESTAMP")); err != nil {
+			t.Fatalf("unexpected error running %.60q: %v", statement, err)
+		}
+	}
+
+	return &SQLite{sqlStore{Repo: db, conn: db}}
+}
+
+// TestSeed checks the Go seed data the in-memory and SQLite stores start with matches the rows database_structure.sql
+// inserts into Postgres.
+func TestSeed(t *testing.T) {
+	structure := newStructureSQLite(t)
+
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		rows func(booker bookings.Booker) ([]string, error)
+	}{
+		{name: "1. Launchpads", rows: func(booker bookings.Booker) ([]string, error) {
+			launchPads, err := booker.GetLaunchPads()
+			return seedKeys(launchPads, func(l bookings.LaunchPad) string {
+				return fmt.Sprint(l.Id, l.FullName, l.SpaceXLaunchPadId, l.SeatCapacity, l.Timezone, l.Active)
+			}), err
+		}},
+		{name: "2. Destinations", rows: func(booker bookings.Booker) ([]string, error) {
+			destinations, err := booker.GetDestinations()
+			return seedKeys(destinations, func(d bookings.Destination) string {
+				return fmt.Sprint(d.Id, d.Name, d.TravelDays, d.Active)
+			}), err
+		}},
+		{name: "3. Schedule", rows: func(booker bookings.Booker) ([]string, error) {
+			schedule, err := booker.GetSchedule("")
+			return seedKeys(schedule, func(e bookings.ScheduleEntry) string {
+				return fmt.Sprint(e.LaunchPadId, e.DayOfWeek, e.DestinationId, e.Direction, e.WindowOpens, e.WindowCloses)
+			}), err
+		}},
+		{name: "4. Bookings", rows: func(booker bookings.Booker) ([]string, error) {
+			all, err := booker.GetAll()
+			return seedKeys(all, func(b bookings.Booking) string {
+				return fmt.Sprint(b.FirstName, b.LastName, b.Gender, b.Birthday.Format(time.DateOnly), b.LaunchPadId, b.DestinationId,
+					b.LaunchDate.Format(time.DateOnly), b.Direction, b.Status)
+			}), err
+		}},
+		{name: "5. Fares", rows: func(booker bookings.Booker) ([]string, error) {
+			fares, err := booker.GetFares()
+			return seedKeys(fares, func(f bookings.Fare) string {
+				return fmt.Sprint(f.LaunchPadId, f.DestinationId, f.BasePrice, f.Currency)
+			}), err
+		}},
+		{name: "6. Fare rules", rows: func(booker bookings.Booker) ([]string, error) {
+			rules, err := booker.GetFareRules()
+			return seedKeys(rules, func(r bookings.FareRule) string {
+				return fmt.Sprint(r.Kind, r.DayOfWeek, keyDate(r.StartDate), keyDate(r.EndDate), r.Multiplier, r.Reason)
+			}), err
+		}},
+		{name: "7. Promo codes", rows: func(booker bookings.Booker) ([]string, error) {
+			promoCodes, err := booker.GetPromoCodes()
+			return seedKeys(promoCodes, func(p bookings.PromoCode) string {
+				return fmt.Sprint(p.Code, p.PercentOff, p.ValidFrom.Format(time.DateOnly), p.ValidTo.Format(time.DateOnly))
+			}), err
+		}},
+		{name: "8. Eligibility rules", rows: func(booker bookings.Booker) ([]string, error) {
+			rules, err := booker.GetEligibilityRules()
+			return seedKeys(rules, func(r bookings.EligibilityRule) string {
+				return fmt.Sprint(r.Kind, r.DestinationId, r.Value, r.Reason)
+			}), err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := tt.rows(structure)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := tt.rows(memory)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(want) == 0 || !slices.Equal(got, want) {
+				t.Errorf("seed data doesn't match database_structure.sql, got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+// seedKeys returns the key of each row, sorted, so rows can be compared whatever order the store returns them in.
+func seedKeys[T any](rows []T, key func(T) string) []string {
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, key(row))
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// keyDate formats an optional date, or returns an empty string if it's not set.
+func keyDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+
+	return date.Format(time.DateOnly)
+}
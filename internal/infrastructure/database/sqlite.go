@@ -0,0 +1,152 @@
+package database
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteDriver = "sqlite"
+
+//go:embed sqlite_structure.sql
+var sqliteStructureSQL string
+
+// SQLite encapsulates objects needed to interact with an embedded SQLite database.
+// It runs the same queries as PostGres, so has the same soft-delete and schedule behaviour.
+type SQLite struct {
+	sqlStore
+}
+
+// NewSQLite returns a new SQLite, creating and seeding the database file if it doesn't exist yet.
+func NewSQLite(cfg config.Config) (*SQLite, error) {
+	// Times are stored in SQLite's own format so that they compare and sort correctly as text.
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_txlock=immediate&_time_format=sqlite", cfg.SQLitePath)
+
+	db, err := sql.Open(sqliteDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer, so one connection avoids "database is locked" errors under load.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteStructureSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating sqlite tables: %w", err)
+	}
+
+	if err := seedSQLite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("Using SQLite database %s\n", cfg.SQLitePath)
+
+	// Transactions take SQLite's write lock when they begin (_txlock=immediate), so rows don't need locking individually.
+	return &SQLite{sqlStore{Repo: db, conn: db}}, nil
+}
+
+// seedSQLite inserts the seed data, the same rows as database_structure.sql, unless the database has already been
+// seeded.
+func seedSQLite(db *sql.DB) error {
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM launchpads`).Scan(&count); err != nil {
+		return fmt.Errorf("error counting launchpads: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting seed transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	s := newSeed(time.Now().UTC())
+
+	for _, l := range s.launchPads {
+		if err := seedInsert(tx, "launchpads", []string{"id", "full_name", "spacex_launchpad_id", "seat_capacity", "timezone", "active", "created_at", "updated_at"},
+			l.Id, l.FullName, l.SpaceXLaunchPadId, l.SeatCapacity, l.Timezone, l.Active, l.CreatedAt, l.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range s.destinations {
+		if err := seedInsert(tx, "destinations", []string{"id", "name", "travel_days", "active", "created_at", "updated_at"},
+			d.Id, d.Name, d.TravelDays, d.Active, d.CreatedAt, d.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range s.bookings {
+		if err := seedInsert(tx, "bookings", []string{"id", "first_name", "last_name", "gender", "birthday", "launchpad_id", "destination_id", "launch_date", "direction", "status", "created_at", "updated_at"},
+			b.Id, b.FirstName, b.LastName, b.Gender, b.Birthday, b.LaunchPadId, b.DestinationId, b.LaunchDate, b.Direction, b.Status, b.CreatedAt, b.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range s.schedule {
+		if err := seedInsert(tx, "launchpad_schedule", []string{"id", "launchpad_id", "day_of_week", "destination_id", "direction", "window_opens", "window_closes", "created_at", "updated_at"},
+			e.Id, e.LaunchPadId, e.DayOfWeek, e.DestinationId, e.Direction, e.WindowOpens, e.WindowCloses, e.CreatedAt, e.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range s.fares {
+		if err := seedInsert(tx, "fares", []string{"launchpad_id", "destination_id", "base_price", "currency", "created_at", "updated_at"},
+			f.LaunchPadId, f.DestinationId, f.BasePrice, f.Currency, f.CreatedAt, f.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range s.fareRules {
+		if err := seedInsert(tx, "fare_rules", []string{"id", "kind", "day_of_week", "start_date", "end_date", "multiplier", "reason", "created_at", "updated_at"},
+			r.Id, r.Kind, nullString(r.DayOfWeek), r.StartDate, r.EndDate, r.Multiplier, r.Reason, r.CreatedAt, r.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range s.promoCodes {
+		if err := seedInsert(tx, "promo_codes", []string{"code", "percent_off", "valid_from", "valid_to", "created_at", "updated_at"},
+			p.Code, p.PercentOff, p.ValidFrom, p.ValidTo, p.CreatedAt, p.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range s.eligibilityRules {
+		if err := seedInsert(tx, "eligibility_rules", []string{"id", "kind", "destination_id", "value", "reason", "created_at", "updated_at"},
+			r.Id, r.Kind, nullString(r.DestinationId), r.Value, r.Reason, r.CreatedAt, r.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing seed transaction: %w", err)
+	}
+
+	log.Println("Seeded SQLite database")
+
+	return nil
+}
+
+// seedInsert inserts a row of seed data into the table, the args being the values of the columns in order.
+func seedInsert(tx *sql.Tx, table string, columns []string, args ...any) error {
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("error seeding %s: %w", table, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+CREATE TABLE IF NOT EXISTS launchpads (
+    id text PRIMARY KEY NOT NULL,
+    full_name text NOT NULL,
+    spacex_launchpad_id char(24) NOT NULL,
+    seat_capacity integer NOT NULL,
+    timezone text NOT NULL DEFAULT 'UTC',
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS destinations (
+    id text PRIMARY KEY NOT NULL,
+    name text NOT NULL,
+    travel_days integer NOT NULL DEFAULT 0,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bookings (
+    id text PRIMARY KEY NOT NULL,
+    first_name text NOT NULL,
+    last_name text NOT NULL,
+    gender text NOT NULL,
+    birthday date NOT NULL,
+    email text NOT NULL DEFAULT '',
+    phone text NOT NULL DEFAULT '',
+    launchpad_id text NOT NULL,
+    destination_id text NOT NULL,
+    launch_date date NOT NULL,
+    departure_at timestamp,
+    estimated_arrival date,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    outbound_booking_id text,
+    group_id text,
+    price integer,
+    currency text NOT NULL DEFAULT '',
+    promo_code text NOT NULL DEFAULT '',
+    status text CHECK (status IN ('pending', 'paid', 'confirmed', 'payment_failed', 'cancelling', 'cancelled', 'disrupted', 'checked_in', 'boarded', 'flown', 'no_show')) NOT NULL DEFAULT 'pending',
+    payment_id text NOT NULL DEFAULT '',
+    cancellation_reason text NOT NULL DEFAULT '',
+    refund_amount integer,
+    refund_id text NOT NULL DEFAULT '',
+    deleted boolean NOT NULL DEFAULT false,
+    review_required boolean NOT NULL DEFAULT false,
+    review_reason text NOT NULL DEFAULT '',
+    checked_in_at timestamp,
+    boarded_at timestamp,
+    access_token text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS launchpad_schedule (
+    id text PRIMARY KEY NOT NULL,
+    launchpad_id text NOT NULL,
+    day_of_week text CHECK (day_of_week IN ('Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday')) NOT NULL,
+    destination_id text NOT NULL,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    effective_from date,
+    effective_to date,
+    window_opens text NOT NULL DEFAULT '00:00',
+    window_closes text NOT NULL DEFAULT '24:00',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS schedule_exceptions (
+    id text PRIMARY KEY NOT NULL,
+    kind text CHECK (kind IN ('extra_flight', 'cancelled_flight', 'launchpad_closed', 'destination_unavailable')) NOT NULL,
+    launchpad_id text,
+    destination_id text,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    start_date date NOT NULL,
+    end_date date NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS travel_times (
+    launchpad_id text NOT NULL,
+    destination_id text NOT NULL,
+    travel_days integer NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (launchpad_id, destination_id)
+);
+
+CREATE TABLE IF NOT EXISTS holds (
+    id text PRIMARY KEY NOT NULL,
+    launchpad_id text NOT NULL,
+    destination_id text NOT NULL,
+    launch_date date NOT NULL,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS fares (
+    launchpad_id text NOT NULL,
+    destination_id text NOT NULL,
+    base_price integer NOT NULL,
+    currency text NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (launchpad_id, destination_id)
+);
+
+CREATE TABLE IF NOT EXISTS fare_rules (
+    id text PRIMARY KEY NOT NULL,
+    kind text CHECK (kind IN ('day_of_week', 'season')) NOT NULL,
+    launchpad_id text,
+    destination_id text,
+    day_of_week text CHECK (day_of_week IN ('Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday')),
+    start_date date,
+    end_date date,
+    multiplier real NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS eligibility_rules (
+    id text PRIMARY KEY NOT NULL,
+    kind text CHECK (kind IN ('min_age', 'max_age', 'max_trips_per_year')) NOT NULL,
+    destination_id text,
+    value integer CHECK (value > 0) NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS promo_codes (
+    code text PRIMARY KEY NOT NULL,
+    percent_off integer CHECK (percent_off BETWEEN 1 AND 100) NOT NULL,
+    valid_from date NOT NULL,
+    valid_to date NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS waitlist (
+    id text PRIMARY KEY NOT NULL,
+    first_name text NOT NULL,
+    last_name text NOT NULL,
+    gender text NOT NULL,
+    birthday date NOT NULL,
+    email text NOT NULL DEFAULT '',
+    phone text NOT NULL DEFAULT '',
+    launchpad_id text NOT NULL,
+    destination_id text NOT NULL,
+    launch_date date NOT NULL,
+    direction text CHECK (direction IN ('outbound', 'return')) NOT NULL DEFAULT 'outbound',
+    promo_code text,
+    reason text CHECK (reason IN ('flight_full', 'spacex_conflict')) NOT NULL,
+    status text CHECK (status IN ('waiting', 'promoted', 'payment_failed', 'flight_unavailable')) NOT NULL DEFAULT 'waiting',
+    booking_id text,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS external_launches (
+    id text PRIMARY KEY NOT NULL,
+    name text NOT NULL,
+    launchpad_id text NOT NULL,
+    date_utc timestamp NOT NULL,
+    imported_at timestamp NOT NULL
+);
@@ -0,0 +1,458 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+)
+
+func newTestSQLite(t *testing.T) *SQLite {
+	t.Helper()
+
+	db, err := NewSQLite(config.Config{SQLitePath: filepath.Join(t.TempDir(), "spacetickets.db")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	return db
+}
+
+func TestSQLite_Seed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spacetickets.db")
+
+	for i := 0; i < 2; i++ {
+		db, err := NewSQLite(config.Config{SQLitePath: path})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var count int
+		if err := db.Repo.QueryRow(`SELECT count(*) FROM launchpad_schedule`).Scan(&count); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 49 {
+			t.Errorf("wrong number of schedule entries on open %d, got %d want %d", i+1, count, 49)
+		}
+
+		db.Close()
+	}
+}
+
+func TestSQLite_Bookings(t *testing.T) {
+	db := newTestSQLite(t)
+
+	all, err := db.GetAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 1 || all[0].FirstName != "Brian" {
+		t.Fatalf("wrong seeded bookings, got %+v", all)
+	}
+
+	birthday, _ := time.Parse(time.DateOnly, "2000-04-12")
+	launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+	departureAt := time.Date(2010, 12, 6, 23, 30, 0, 0, time.UTC)
+
+	created, err := db.Create(bookings.Booking{
+		Customer: bookings.Customer{
+			FirstName: "Ian",
+			LastName:  "Thomson",
+			Gender:    "Male",
+			Birthday:  birthday,
+		},
+		LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+		DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+		LaunchDate:    launchDate,
+		DepartureAt:   &departureAt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created.Id) == 0 || !created.LaunchDate.Equal(launchDate) || !created.Birthday.Equal(birthday) || created.DepartureAt == nil || !created.DepartureAt.Equal(departureAt) {
+		t.Errorf("wrong created booking, got %+v", created)
+	}
+
+	upcoming, err := db.GetUpcoming(created.LaunchPadId, launchDate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upcoming) != 1 || upcoming[0].Id != created.Id {
+		t.Errorf("wrong upcoming bookings, got %+v", upcoming)
+	}
+
+	if _, err := db.FlagForReview(created.Id, "timetable changed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flagged, err := db.Get(created.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !flagged.ReviewRequired || flagged.ReviewReason != "timetable changed" {
+		t.Errorf("booking not flagged for review, got %+v", flagged)
+	}
+
+	rowsAffected, err := db.Delete(created.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rowsAffected != 1 {
+		t.Errorf("wrong rows affected, got %d want %d", rowsAffected, 1)
+	}
+
+	all, _ = db.GetAll()
+	if len(all) != 1 {
+		t.Errorf("deleted booking still listed, got %+v", all)
+	}
+
+	deleted, err := db.Get(created.Id)
+	if err != nil {
+		t.Fatalf("deleted booking should still be retrievable: %v", err)
+	}
+	if deleted.Id != created.Id {
+		t.Errorf("wrong booking, got %s want %s", deleted.Id, created.Id)
+	}
+}
+
+func TestSQLite_Schedule(t *testing.T) {
+	db := newTestSQLite(t)
+
+	launchPad, err := db.GetLaunchPad("4079f070-3e58-4e61-8af7-05c8de8e1fbf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if launchPad.SpaceXLaunchPadId != "5e9e4502f509094188566f88" {
+		t.Errorf("wrong spacex launchpad id, got %s want %s", launchPad.SpaceXLaunchPadId, "5e9e4502f509094188566f88")
+	}
+	if launchPad.Timezone != "America/New_York" {
+		t.Errorf("wrong timezone, got %s want %s", launchPad.Timezone, "America/New_York")
+	}
+
+	monday, _ := time.Parse(time.DateOnly, "2024-01-01")
+
+	valid, err := db.IsLaunchScheduleValid(bookings.DirectionOutbound, "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", "466fc378-14eb-4ed9-8bec-d29abe54c5a9", monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !valid {
+		t.Errorf("Cape Canaveral should fly to the Moon on Mondays")
+	}
+
+	valid, _ = db.IsLaunchScheduleValid(bookings.DirectionOutbound, "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", "466fc378-14eb-4ed9-8bec-d29abe54c5a9", monday.AddDate(0, 0, 1))
+	if valid {
+		t.Errorf("Cape Canaveral shouldn't fly to the Moon on Tuesdays")
+	}
+}
+
+func TestSQLite_Catalogue(t *testing.T) {
+	db := newTestSQLite(t)
+
+	callisto, err := db.CreateDestination(bookings.Destination{Name: "Callisto"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !callisto.Active {
+		t.Errorf("new destination should be active")
+	}
+
+	launchPad, err := db.CreateLaunchPad(bookings.LaunchPad{FullName: "Starbase Pad B", SpaceXLaunchPadId: "5e9e4502f5090927f8566f99", SeatCapacity: 120, Timezone: "America/Chicago"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, err := db.CreateScheduleEntry(bookings.ScheduleEntry{LaunchPadId: launchPad.Id, DayOfWeek: "Friday", DestinationId: callisto.Id})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saturday, _ := time.Parse(time.DateOnly, "2024-01-06")
+	entry.DayOfWeek = "Saturday"
+	entry.EffectiveFrom = &saturday
+	entry.WindowOpens, entry.WindowCloses = "18:30", "21:00"
+	updated, err := db.UpdateScheduleEntry(*entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.EffectiveFrom == nil || !updated.EffectiveFrom.Equal(saturday) || updated.EffectiveTo != nil {
+		t.Errorf("wrong effective dates, got %v to %v", updated.EffectiveFrom, updated.EffectiveTo)
+	}
+	if updated.WindowOpens != "18:30" || updated.WindowCloses != "21:00" {
+		t.Errorf("wrong launch window, got %s to %s", updated.WindowOpens, updated.WindowCloses)
+	}
+
+	scheduled, err := db.FindScheduledFlight(bookings.DirectionOutbound, launchPad.Id, callisto.Id, saturday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.Id != entry.Id {
+		t.Errorf("wrong scheduled flight, got %s want %s", scheduled.Id, entry.Id)
+	}
+
+	valid, _ := db.IsLaunchScheduleValid(bookings.DirectionOutbound, launchPad.Id, callisto.Id, saturday)
+	if !valid {
+		t.Errorf("Starbase Pad B should fly to Callisto on Saturdays")
+	}
+
+	valid, _ = db.IsLaunchScheduleValid(bookings.DirectionOutbound, launchPad.Id, callisto.Id, saturday.AddDate(0, 0, -7))
+	if valid {
+		t.Errorf("Starbase Pad B shouldn't fly to Callisto before the entry takes effect")
+	}
+
+	rowsAffected, err := db.RetireLaunchPad(launchPad.Id)
+	if err != nil || rowsAffected != 1 {
+		t.Fatalf("wrong result retiring launchpad, got %d, %v", rowsAffected, err)
+	}
+
+	retired, err := db.GetLaunchPad(launchPad.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retired.Active {
+		t.Errorf("retired launchpad should be inactive")
+	}
+
+	if _, err := db.GetDestination("unknown"); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+
+	if _, err := db.UpdateDestination(bookings.Destination{Id: "unknown", Name: "Nowhere"}); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+}
+
+func TestSQLite_ScheduleExceptions(t *testing.T) {
+	db := newTestSQLite(t)
+
+	start, _ := time.Parse(time.DateOnly, "2024-01-01")
+	end, _ := time.Parse(time.DateOnly, "2024-01-07")
+
+	created, err := db.CreateScheduleException(bookings.ScheduleException{
+		Kind:        bookings.ExceptionLaunchPadClosed,
+		LaunchPadId: capeCanaveralId,
+		Direction:   bookings.DirectionReturn,
+		StartDate:   start,
+		EndDate:     end,
+		Reason:      "Maintenance",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created.DestinationId) != 0 || created.Direction != bookings.DirectionReturn || !created.StartDate.Equal(start) || !created.EndDate.Equal(end) {
+		t.Errorf("wrong created exception, got %+v", created)
+	}
+
+	for _, tt := range []struct {
+		date string
+		want int
+	}{
+		{date: "2023-12-31", want: 0},
+		{date: "2024-01-01", want: 1},
+		{date: "2024-01-07", want: 1},
+		{date: "2024-01-08", want: 0},
+	} {
+		date, _ := time.Parse(time.DateOnly, tt.date)
+		exceptions, err := db.GetScheduleExceptions(date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(exceptions) != tt.want {
+			t.Errorf("wrong number of exceptions on %s, got %d want %d", tt.date, len(exceptions), tt.want)
+		}
+	}
+
+	created.EndDate = start
+	if _, err := db.UpdateScheduleException(*created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rowsAffected, err := db.DeleteScheduleException(created.Id)
+	if err != nil || rowsAffected != 1 {
+		t.Fatalf("wrong result deleting exception, got %d, %v", rowsAffected, err)
+	}
+
+	if _, err := db.GetScheduleException(created.Id); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+}
+
+func TestSQLite_TravelTimes(t *testing.T) {
+	db := newTestSQLite(t)
+
+	travelDays, err := db.GetTravelDays(capeCanaveralId, moonId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if travelDays != 3 {
+		t.Errorf("wrong seeded travel days, got %d want %d", travelDays, 3)
+	}
+
+	for _, days := range []int{5, 4} {
+		if _, err := db.SetTravelTime(bookings.TravelTime{LaunchPadId: capeCanaveralId, DestinationId: moonId, TravelDays: days}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	travelTimes, err := db.GetTravelTimes(capeCanaveralId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(travelTimes) != 1 || travelTimes[0].TravelDays != 4 {
+		t.Errorf("travel time not replaced, got %+v", travelTimes)
+	}
+
+	travelDays, _ = db.GetTravelDays(capeCanaveralId, moonId)
+	if travelDays != 4 {
+		t.Errorf("override not used, got %d want %d", travelDays, 4)
+	}
+
+	rowsAffected, err := db.DeleteTravelTime(capeCanaveralId, moonId)
+	if err != nil || rowsAffected != 1 {
+		t.Fatalf("wrong result deleting travel time, got %d, %v", rowsAffected, err)
+	}
+
+	travelDays, _ = db.GetTravelDays(capeCanaveralId, moonId)
+	if travelDays != 3 {
+		t.Errorf("destination travel days not used after deleting override, got %d want %d", travelDays, 3)
+	}
+
+	if _, err := db.GetTravelDays(capeCanaveralId, "unknown"); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+}
+
+func TestSQLite_ReturnBooking(t *testing.T) {
+	db := newTestSQLite(t)
+
+	launchDate, _ := time.Parse(time.DateOnly, "2024-01-01")
+	arrival := bookings.EstimateArrival(launchDate, 3)
+
+	outbound, err := db.Create(bookings.Booking{
+		Customer:         bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: launchDate},
+		LaunchPadId:      "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+		DestinationId:    "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+		LaunchDate:       launchDate,
+		EstimatedArrival: &arrival,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outbound.Direction != bookings.DirectionOutbound || outbound.EstimatedArrival == nil || !outbound.EstimatedArrival.Equal(arrival) {
+		t.Errorf("wrong outbound booking, got %+v", outbound)
+	}
+
+	if _, err := db.GetReturn(outbound.Id); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+
+	returnBooking, err := bookings.NewReturnBooking(*outbound, "", launchDate.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created, err := db.Create(returnBooking)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err := db.GetReturn(outbound.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found.Id != created.Id || found.Direction != bookings.DirectionReturn || found.OutboundBookingId != outbound.Id {
+		t.Errorf("wrong return booking, got %+v", found)
+	}
+
+	valid, _ := db.IsLaunchScheduleValid(bookings.DirectionReturn, found.LaunchPadId, found.DestinationId, found.LaunchDate)
+	if !valid {
+		t.Errorf("seeded return flights from the Moon should land at Cape Canaveral on Mondays")
+	}
+
+	booked, _ := db.CountBookings(bookings.DirectionOutbound, found.LaunchPadId, found.LaunchDate)
+	if booked != 0 {
+		t.Errorf("return flight counted against the outbound flight's seats, got %d", booked)
+	}
+
+	if _, err := db.Get("00000000-0000-0000-0000-000000000000"); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+}
+
+func TestSQLite_Pricing(t *testing.T) {
+	db := newTestSQLite(t)
+
+	fares, err := db.GetFares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fares) != 42 {
+		t.Errorf("wrong number of seeded fares, got %d want %d", len(fares), 42)
+	}
+
+	if _, err := db.SetFare(bookings.Fare{LaunchPadId: capeCanaveralId, DestinationId: moonId, BasePrice: 100, Currency: "EUR"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fare, err := db.GetFare(capeCanaveralId, moonId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fare.BasePrice != 100 || fare.Currency != "EUR" {
+		t.Errorf("fare not replaced, got %+v", fare)
+	}
+
+	rules, err := db.GetFareRules()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Errorf("wrong number of seeded fare rules, got %d want %d", len(rules), 3)
+	}
+
+	start, _ := time.Parse(time.DateOnly, "2024-01-01")
+	end, _ := time.Parse(time.DateOnly, "2024-01-31")
+	rule, err := db.CreateFareRule(bookings.FareRule{Kind: bookings.FareRuleSeason, DestinationId: moonId, StartDate: &start, EndDate: &end, Multiplier: 1.25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Multiplier != 1.25 || rule.StartDate == nil || !rule.StartDate.Equal(start) || len(rule.LaunchPadId) > 0 || rule.DestinationId != moonId {
+		t.Errorf("wrong fare rule, got %+v", rule)
+	}
+
+	if rowsAffected, _ := db.DeleteFareRule(rule.Id); rowsAffected != 1 {
+		t.Errorf("fare rule not deleted, got %d rows affected", rowsAffected)
+	}
+
+	promo, err := db.GetPromoCode("WELCOME10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if promo.PercentOff != 10 {
+		t.Errorf("wrong seeded promo code, got %+v", promo)
+	}
+
+	if _, err := db.GetPromoCode("unknown"); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+	}
+
+	price := int64(90)
+	booking, err := db.Create(bookings.Booking{
+		Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: start},
+		LaunchPadId:   capeCanaveralId,
+		DestinationId: moonId,
+		LaunchDate:    start,
+		Price:         &price,
+		Currency:      "EUR",
+		PromoCode:     "WELCOME10",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.Price == nil || *booking.Price != 90 || booking.Currency != "EUR" || booking.PromoCode != "WELCOME10" {
+		t.Errorf("wrong booking price, got %v %s %s", booking.Price, booking.Currency, booking.PromoCode)
+	}
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+)
+
+// Store is a bookings.Booker that holds resources which must be released with Close.
+type Store interface {
+	bookings.Booker
+	Close()
+}
+
+// Open returns the Store selected by the StorageBackend config.
+func Open(cfg config.Config) (Store, error) {
+	switch cfg.StorageBackend {
+	case config.StorageBackendPostgres:
+		postgres, err := New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return postgres, nil
+	case config.StorageBackendSQLite:
+		sqlite, err := NewSQLite(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return sqlite, nil
+	case config.StorageBackendMemory:
+		memory, err := NewMemory()
+		if err != nil {
+			return nil, err
+		}
+		return memory, nil
+	default:
+		return nil, fmt.Errorf("unrecognised storage backend %s", cfg.StorageBackend)
+	}
+}
@@ -0,0 +1,691 @@
+package database
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const (
+	testLaunchPadId   = "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975"
+	testDestinationId = "466fc378-14eb-4ed9-8bec-d29abe54c5a9"
+)
+
+func TestInTransaction_ConcurrentBookingsRespectCapacity(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	launchPad := memory.data.launchPads[testLaunchPadId]
+	launchPad.SeatCapacity = 3
+	memory.data.launchPads[testLaunchPadId] = launchPad
+
+	sqlite := newTestSQLite(t)
+	if _, err := sqlite.Repo.Exec(`UPDATE launchpads SET seat_capacity = 3 WHERE id = $1`, testLaunchPadId); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: sqlite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			created, full := 0, 0
+
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := tt.store.InTransaction(func(tx bookings.Booker) error {
+						launchPad, err := tx.GetLaunchPad(testLaunchPadId)
+						if err != nil {
+							return err
+						}
+						booked, err := tx.CountBookings(bookings.DirectionOutbound, testLaunchPadId, launchDate)
+						if err != nil {
+							return err
+						}
+						if booked >= launchPad.SeatCapacity {
+							return bookings.ErrFlightFull
+						}
+						_, err = tx.Create(bookings.Booking{
+							Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson"},
+							LaunchPadId:   testLaunchPadId,
+							DestinationId: testDestinationId,
+							LaunchDate:    launchDate,
+						})
+						return err
+					})
+
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case err == nil:
+						created++
+					case errors.Is(err, bookings.ErrFlightFull):
+						full++
+					default:
+						t.Errorf("unexpected error: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if created != 3 || full != 7 {
+				t.Errorf("wrong outcome, got %d created and %d full, want 3 and 7", created, full)
+			}
+
+			booked, err := tt.store.CountBookings(bookings.DirectionOutbound, testLaunchPadId, launchDate)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if booked != 3 {
+				t.Errorf("wrong number of bookings, got %d want %d", booked, 3)
+			}
+		})
+	}
+}
+
+func TestInTransaction_RollsBackOnError(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantErr := errors.New("oops")
+
+			err := tt.store.InTransaction(func(tx bookings.Booker) error {
+				if _, err := tx.Create(bookings.Booking{LaunchPadId: testLaunchPadId, DestinationId: testDestinationId}); err != nil {
+					return err
+				}
+				return wantErr
+			})
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("wrong error, got %v want %v", err, wantErr)
+			}
+
+			all, err := tt.store.GetAll()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(all) != 1 {
+				t.Errorf("booking created in rolled back transaction, got %d bookings want %d", len(all), 1)
+			}
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+			now := time.Now().UTC()
+
+			hold := bookings.Hold{LaunchPadId: testLaunchPadId, DestinationId: testDestinationId, LaunchDate: launchDate}
+			for _, expiresAt := range []time.Time{now.Add(time.Minute), now.Add(-time.Minute)} {
+				hold.ExpiresAt = expiresAt
+				if _, err := tt.store.CreateHold(hold); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			held, err := tt.store.CountHolds(bookings.DirectionOutbound, testLaunchPadId, launchDate, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if held != 1 {
+				t.Errorf("expired hold counted, got %d want %d", held, 1)
+			}
+
+			rowsAffected, err := tt.store.DeleteExpiredHolds(now)
+			if err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result deleting expired holds, got %d, %v", rowsAffected, err)
+			}
+
+			hold.ExpiresAt = now.Add(time.Hour)
+			created, err := tt.store.CreateHold(hold)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := tt.store.GetHold(created.Id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Direction != bookings.DirectionOutbound || !got.LaunchDate.Equal(launchDate) {
+				t.Errorf("wrong hold, got %+v", got)
+			}
+
+			if rowsAffected, _ := tt.store.DeleteHold(created.Id); rowsAffected != 1 {
+				t.Errorf("hold not deleted, got %d rows affected", rowsAffected)
+			}
+
+			if _, err := tt.store.GetHold(created.Id); !errors.Is(err, bookings.ErrNotFound) {
+				t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+			}
+
+			held, _ = tt.store.CountHolds(bookings.DirectionOutbound, testLaunchPadId, launchDate, now)
+			if held != 1 {
+				t.Errorf("wrong holds remaining, got %d want %d", held, 1)
+			}
+		})
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+			created, err := tt.store.Create(bookings.Booking{
+				Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: launchDate},
+				LaunchPadId:   testLaunchPadId,
+				DestinationId: testDestinationId,
+				LaunchDate:    launchDate,
+				Status:        bookings.StatusPending,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created.Status != bookings.StatusPending {
+				t.Errorf("wrong status, got %q want %q", created.Status, bookings.StatusPending)
+			}
+
+			if rowsAffected, err := tt.store.SetStatus(created.Id, bookings.StatusPaid, "pay-1"); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result setting status, got %d, %v", rowsAffected, err)
+			}
+
+			// An empty payment id keeps the one the booking already has.
+			if _, err := tt.store.SetStatus(created.Id, bookings.StatusConfirmed, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := tt.store.Get(created.Id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != bookings.StatusConfirmed || got.PaymentId != "pay-1" {
+				t.Errorf("wrong status, got %q and payment id %q", got.Status, got.PaymentId)
+			}
+
+			if rowsAffected, _ := tt.store.SetStatus("unknown", bookings.StatusPaid, ""); rowsAffected != 0 {
+				t.Errorf("wrong rows affected for an unknown booking, got %d want %d", rowsAffected, 0)
+			}
+		})
+	}
+}
+
+func TestClearDisruption(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+			created, err := tt.store.Create(bookings.Booking{
+				Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: launchDate},
+				LaunchPadId:   testLaunchPadId,
+				DestinationId: testDestinationId,
+				LaunchDate:    launchDate,
+				Status:        bookings.StatusConfirmed,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Only disrupted bookings are restored.
+			if rowsAffected, err := tt.store.ClearDisruption(created.Id, bookings.StatusConfirmed); err != nil || rowsAffected != 0 {
+				t.Fatalf("wrong result clearing a booking that isn't disrupted, got %d, %v", rowsAffected, err)
+			}
+
+			if _, err := tt.store.SetStatus(created.Id, bookings.StatusDisrupted, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := tt.store.FlagForReview(created.Id, "SpaceX are launching"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if rowsAffected, err := tt.store.ClearDisruption(created.Id, bookings.StatusConfirmed); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result clearing the disruption, got %d, %v", rowsAffected, err)
+			}
+
+			got, err := tt.store.Get(created.Id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != bookings.StatusConfirmed || got.ReviewRequired || len(got.ReviewReason) > 0 {
+				t.Errorf("disruption not cleared, got status %q, review %v %q", got.Status, got.ReviewRequired, got.ReviewReason)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+			price := int64(1000)
+
+			created, err := tt.store.Create(bookings.Booking{
+				Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: launchDate},
+				LaunchPadId:   testLaunchPadId,
+				DestinationId: testDestinationId,
+				LaunchDate:    launchDate,
+				Price:         &price,
+				Currency:      "USD",
+				Status:        bookings.StatusConfirmed,
+				AccessToken:   "secret",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if rowsAffected, err := tt.store.ClaimCancellation(created.Id, bookings.StatusConfirmed, bookings.CancelledByCustomer); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result claiming, got %d, %v", rowsAffected, err)
+			}
+
+			// The booking is no longer confirmed, so it can't be claimed again.
+			if rowsAffected, _ := tt.store.ClaimCancellation(created.Id, bookings.StatusConfirmed, bookings.CancelledByCustomer); rowsAffected != 0 {
+				t.Errorf("wrong rows affected claiming twice, got %d want %d", rowsAffected, 0)
+			}
+
+			claimed, err := tt.store.Get(created.Id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claimed.Deleted || claimed.Status != bookings.StatusCancelling || claimed.CancellationReason != bookings.CancelledByCustomer ||
+				claimed.AccessToken != "secret" {
+				t.Errorf("booking not claimed, got %+v", claimed)
+			}
+
+			refund := int64(750)
+			if rowsAffected, err := tt.store.Cancel(created.Id, bookings.CancelledByCustomer, &refund, "refund-1"); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result cancelling, got %d, %v", rowsAffected, err)
+			}
+
+			got, err := tt.store.Get(created.Id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Deleted || got.Status != bookings.StatusCancelled || got.CancellationReason != bookings.CancelledByCustomer ||
+				got.RefundAmount == nil || *got.RefundAmount != refund || got.RefundId != "refund-1" {
+				t.Errorf("booking not cancelled, got %+v", got)
+			}
+
+			// A booking can only be cancelled once.
+			if rowsAffected, _ := tt.store.Cancel(created.Id, bookings.CancelledByCustomer, nil, ""); rowsAffected != 0 {
+				t.Errorf("wrong rows affected cancelling twice, got %d want %d", rowsAffected, 0)
+			}
+		})
+	}
+}
+
+func TestMoveStatus(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+			checkedInAt := launchDate.Add(-2 * time.Hour)
+			boardedAt := launchDate.Add(13 * time.Hour)
+
+			booking := bookings.Booking{
+				Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: launchDate},
+				LaunchPadId:   testLaunchPadId,
+				DestinationId: testDestinationId,
+				LaunchDate:    launchDate,
+				Status:        bookings.StatusConfirmed,
+			}
+
+			created, err := tt.store.Create(booking)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created.CheckedInAt != nil || created.BoardedAt != nil {
+				t.Errorf("new booking already checked in or boarded, got %+v", created)
+			}
+
+			if rowsAffected, err := tt.store.MoveStatus(created.Id, bookings.StatusConfirmed, bookings.StatusCheckedIn, checkedInAt); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result checking in, got %d, %v", rowsAffected, err)
+			}
+
+			// The booking is no longer confirmed, so checking in again doesn't move it.
+			if rowsAffected, _ := tt.store.MoveStatus(created.Id, bookings.StatusConfirmed, bookings.StatusCheckedIn, checkedInAt); rowsAffected != 0 {
+				t.Errorf("wrong rows affected checking in twice, got %d want %d", rowsAffected, 0)
+			}
+
+			if rowsAffected, err := tt.store.MoveStatus(created.Id, bookings.StatusCheckedIn, bookings.StatusBoarded, boardedAt); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result boarding, got %d, %v", rowsAffected, err)
+			}
+
+			if rowsAffected, err := tt.store.MoveStatus(created.Id, bookings.StatusBoarded, bookings.StatusFlown, boardedAt.Add(time.Hour)); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result flying, got %d, %v", rowsAffected, err)
+			}
+
+			got, err := tt.store.Get(created.Id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != bookings.StatusFlown {
+				t.Errorf("wrong status, got %s want %s", got.Status, bookings.StatusFlown)
+			}
+			if got.CheckedInAt == nil || !got.CheckedInAt.Equal(checkedInAt) {
+				t.Errorf("wrong checked in at, got %v want %s", got.CheckedInAt, checkedInAt)
+			}
+			if got.BoardedAt == nil || !got.BoardedAt.Equal(boardedAt) {
+				t.Errorf("wrong boarded at, got %v want %s", got.BoardedAt, boardedAt)
+			}
+
+			// Deleted bookings don't move.
+			deleted, err := tt.store.Create(booking)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := tt.store.Cancel(deleted.Id, bookings.CancelledByCustomer, nil, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rowsAffected, _ := tt.store.MoveStatus(deleted.Id, bookings.StatusCancelled, bookings.StatusCheckedIn, checkedInAt); rowsAffected != 0 {
+				t.Errorf("wrong rows affected checking in a cancelled booking, got %d want %d", rowsAffected, 0)
+			}
+		})
+	}
+}
+
+func TestWaitlist(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+			booking := bookings.Booking{
+				Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: launchDate},
+				LaunchPadId:   testLaunchPadId,
+				DestinationId: testDestinationId,
+				LaunchDate:    launchDate,
+			}
+
+			var ids []string
+			for _, reason := range []string{bookings.WaitlistFlightFull, bookings.WaitlistSpaceXConflict} {
+				created, err := tt.store.CreateWaitlistEntry(bookings.NewWaitlistEntry(booking, reason))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				ids = append(ids, created.Id)
+			}
+
+			waiting, err := tt.store.GetWaitlist(launchDate)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(waiting) != 2 || waiting[0].Id != ids[0] || waiting[1].Id != ids[1] {
+				t.Fatalf("waitlist not oldest first, got %+v", waiting)
+			}
+			if waiting[0].Direction != bookings.DirectionOutbound || waiting[0].Status != bookings.WaitlistWaiting || waiting[0].FirstName != "Ian" {
+				t.Errorf("wrong waitlist entry, got %+v", waiting[0])
+			}
+
+			if waiting, _ := tt.store.GetWaitlist(launchDate.AddDate(0, 0, 1)); len(waiting) != 0 {
+				t.Errorf("waitlist for earlier flight returned, got %+v", waiting)
+			}
+
+			if rowsAffected, err := tt.store.SetWaitlistStatus(ids[0], bookings.WaitlistPromoted, "booking-1"); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result setting status, got %d, %v", rowsAffected, err)
+			}
+
+			got, err := tt.store.GetWaitlistEntry(ids[0])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != bookings.WaitlistPromoted || got.BookingId != "booking-1" {
+				t.Errorf("entry not promoted, got %+v", got)
+			}
+
+			if waiting, _ := tt.store.GetWaitlist(launchDate); len(waiting) != 1 || waiting[0].Id != ids[1] {
+				t.Errorf("promoted entry still waiting, got %+v", waiting)
+			}
+
+			if _, err := tt.store.GetWaitlistEntry("missing"); !errors.Is(err, bookings.ErrNotFound) {
+				t.Errorf("wrong error, got %v want %v", err, bookings.ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestExternalLaunches(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launchedAt, _ := time.Parse(time.RFC3339, "2010-12-08T15:43:00Z")
+			day := bookings.Today(launchedAt)
+
+			stale := []bookings.ExternalLaunch{{Id: "stale", Name: "Stale", LaunchPadId: "pad", DateUTC: launchedAt}}
+			if _, err := bookings.ImportLaunchSnapshot(tt.store, stale); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			launches := []bookings.ExternalLaunch{
+				{Id: "one", Name: "COTS 1", LaunchPadId: "pad", DateUTC: launchedAt},
+				{Id: "two", Name: "Other Pad", LaunchPadId: "other-pad", DateUTC: launchedAt},
+			}
+			imported, err := bookings.ImportLaunchSnapshot(tt.store, launches)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if imported != 2 {
+				t.Errorf("wrong number imported, got %d want %d", imported, 2)
+			}
+
+			counts := []struct {
+				from, to time.Time
+				want     int
+			}{
+				{from: day, to: day.AddDate(0, 0, 1), want: 1},
+				{from: launchedAt, to: launchedAt.Add(time.Minute), want: 1},
+				{from: day, to: launchedAt, want: 0},
+				{from: day.AddDate(0, 0, 1), to: day.AddDate(0, 0, 2), want: 0},
+			}
+			for _, c := range counts {
+				got, err := tt.store.CountExternalLaunches("pad", c.from, c.to)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != c.want {
+					t.Errorf("wrong count from %s to %s, got %d want %d", c.from, c.to, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEligibility(t *testing.T) {
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{name: "1. Memory", store: memory},
+		{name: "2. SQLite", store: newTestSQLite(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seeded, err := tt.store.GetEligibilityRules()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(seeded) != 3 {
+				t.Fatalf("wrong number of seeded rules, got %d want %d", len(seeded), 3)
+			}
+
+			created, err := tt.store.CreateEligibilityRule(bookings.EligibilityRule{
+				Kind:          bookings.EligibilityMaxTripsPerYear,
+				DestinationId: testDestinationId,
+				Value:         2,
+				Reason:        "seats are limited",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(created.Id) == 0 || created.DestinationId != testDestinationId || created.Value != 2 {
+				t.Errorf("rule not created, got %+v", created)
+			}
+
+			if rowsAffected, err := tt.store.DeleteEligibilityRule(created.Id); err != nil || rowsAffected != 1 {
+				t.Fatalf("wrong result deleting, got %d, %v", rowsAffected, err)
+			}
+			if rowsAffected, _ := tt.store.DeleteEligibilityRule(created.Id); rowsAffected != 0 {
+				t.Errorf("wrong rows affected deleting twice, got %d want %d", rowsAffected, 0)
+			}
+
+			birthday, _ := time.Parse(time.DateOnly, "1980-04-12")
+			customer := bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: birthday}
+			create := func(customer bookings.Customer, launchDate, direction string) *bookings.Booking {
+				date, _ := time.Parse(time.DateOnly, launchDate)
+				booking, err := tt.store.Create(bookings.Booking{
+					Customer:      customer,
+					LaunchPadId:   testLaunchPadId,
+					DestinationId: testDestinationId,
+					LaunchDate:    date,
+					Direction:     direction,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return booking
+			}
+
+			create(customer, "2030-01-07", bookings.DirectionOutbound)
+			create(customer, "2030-01-21", bookings.DirectionReturn)
+			create(customer, "2031-01-06", bookings.DirectionOutbound)
+			cancelled := create(customer, "2030-03-04", bookings.DirectionOutbound)
+			if _, err := tt.store.Cancel(cancelled.Id, bookings.CancelledByCustomer, nil, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			namesake := customer
+			namesake.Birthday = birthday.AddDate(1, 0, 0)
+			create(namesake, "2030-02-04", bookings.DirectionOutbound)
+
+			// Names are matched ignoring case.
+			customer.FirstName, customer.LastName = "IAN", "thomson"
+			from, _ := time.Parse(time.DateOnly, "2030-01-01")
+			trips, err := tt.store.CountTrips(customer, from, from.AddDate(1, 0, 0))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if trips != 1 {
+				t.Errorf("wrong number of trips, got %d want %d", trips, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const travelTimeColumns = `launchpad_id, destination_id, travel_days, created_at, updated_at`
+
+// GetTravelTimes returns the launchpad's travel time overrides, or every launchpad's if launchPadId is empty.
+func (s *sqlStore) GetTravelTimes(launchPadId string) ([]bookings.TravelTime, error) {
+	query := `SELECT ` + travelTimeColumns + ` FROM travel_times`
+	var args []any
+	if len(launchPadId) > 0 {
+		query += ` WHERE launchpad_id = $1`
+		args = append(args, launchPadId)
+	}
+
+	rows, err := s.conn.Query(query+` ORDER BY launchpad_id, destination_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying travel_times: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.TravelTime{}
+
+	for rows.Next() {
+		result, err := scanTravelTime(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over travel_times rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetTravelDays returns how long flights between the launchpad and destination take, using the launchpad's travel time
+// override if there is one and the destination's travel time otherwise.
+func (s *sqlStore) GetTravelDays(launchPadId, destinationId string) (int, error) {
+	destination, err := s.GetDestination(destinationId)
+	if err != nil {
+		return 0, err
+	}
+
+	var travelDays int
+
+	err = s.conn.QueryRow(`SELECT travel_days FROM travel_times WHERE launchpad_id = $1 AND destination_id = $2`,
+		launchPadId, destinationId).Scan(&travelDays)
+	if errors.Is(err, sql.ErrNoRows) {
+		return destination.TravelDays, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error scanning travel_times: %w", err)
+	}
+
+	return travelDays, nil
+}
+
+// SetTravelTime sets the travel time between a launchpad and a destination, replacing any it already has.
+func (s *sqlStore) SetTravelTime(travelTime bookings.TravelTime) (*bookings.TravelTime, error) {
+	_, err := s.conn.Exec(`INSERT INTO travel_times (`+travelTimeColumns+`) VALUES ($1, $2, $3, $4, $4)
+	 ON CONFLICT (launchpad_id, destination_id) DO UPDATE SET travel_days = excluded.travel_days, updated_at = excluded.updated_at`,
+		travelTime.LaunchPadId, travelTime.DestinationId, travelTime.TravelDays, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error setting travel time: %w", err)
+	}
+
+	row := s.conn.QueryRow(`SELECT `+travelTimeColumns+` FROM travel_times WHERE launchpad_id = $1 AND destination_id = $2`,
+		travelTime.LaunchPadId, travelTime.DestinationId)
+
+	result, err := scanTravelTime(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("travel time from launchpad %s: %w", travelTime.LaunchPadId, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// DeleteTravelTime removes the travel time between a launchpad and a destination, so the destination's applies again.
+func (s *sqlStore) DeleteTravelTime(launchPadId, destinationId string) (int64, error) {
+	result, err := s.conn.Exec(`DELETE FROM travel_times WHERE launchpad_id = $1 AND destination_id = $2`, launchPadId, destinationId)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete travel time: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanTravelTime(row scanner) (*bookings.TravelTime, error) {
+	var result bookings.TravelTime
+
+	if err := row.Scan(
+		&result.LaunchPadId,
+		&result.DestinationId,
+		&result.TravelDays,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning travel_times: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const waitlistColumns = `id, first_name, last_name, gender, birthday, email, phone, launchpad_id, destination_id, launch_date, direction, promo_code, reason, status, booking_id, created_at, updated_at`
+
+// GetWaitlist returns the entries still waiting for flights launching on or after from, oldest first.
+func (s *sqlStore) GetWaitlist(from time.Time) ([]bookings.WaitlistEntry, error) {
+	rows, err := s.conn.Query(`SELECT `+waitlistColumns+` FROM waitlist WHERE status = $1 AND launch_date >= $2 ORDER BY created_at, id`,
+		bookings.WaitlistWaiting, from)
+	if err != nil {
+		return nil, fmt.Errorf("error querying waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	results := []bookings.WaitlistEntry{}
+
+	for rows.Next() {
+		result, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over waitlist rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetWaitlistEntry gets a waitlist entry by id, whatever its status.
+func (s *sqlStore) GetWaitlistEntry(id string) (*bookings.WaitlistEntry, error) {
+	result, err := scanWaitlistEntry(s.conn.QueryRow(`SELECT `+waitlistColumns+` FROM waitlist WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("waitlist entry %s: %w", id, bookings.ErrNotFound)
+	}
+
+	return result, err
+}
+
+// CreateWaitlistEntry adds a new entry to the end of its flight's waitlist. An entry without a direction is waiting
+// for an outbound flight, and one without a status is waiting.
+func (s *sqlStore) CreateWaitlistEntry(entry bookings.WaitlistEntry) (*bookings.WaitlistEntry, error) {
+	id := uuid.NewString()
+
+	if len(entry.Direction) == 0 {
+		entry.Direction = bookings.DirectionOutbound
+	}
+
+	if len(entry.Status) == 0 {
+		entry.Status = bookings.WaitlistWaiting
+	}
+
+	_, err := s.conn.Exec(`INSERT INTO waitlist (`+waitlistColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $16)`,
+		id, entry.FirstName, entry.LastName, entry.Gender, entry.Birthday, entry.Email, entry.Phone, entry.LaunchPadId, entry.DestinationId, entry.LaunchDate, entry.Direction,
+		nullString(entry.PromoCode), entry.Reason, entry.Status, nullString(entry.BookingId), time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error creating waitlist entry: %w", err)
+	}
+
+	return s.GetWaitlistEntry(id)
+}
+
+// SetWaitlistStatus moves a waitlist entry to the status, recording the booking it was promoted to if bookingId isn't
+// empty.
+func (s *sqlStore) SetWaitlistStatus(id, status, bookingId string) (int64, error) {
+	result, err := s.conn.Exec(`UPDATE waitlist SET status = $2, booking_id = COALESCE($3, booking_id), updated_at = $4 WHERE id = $1`,
+		id, status, nullString(bookingId), time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not set waitlist entry status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanWaitlistEntry(row scanner) (*bookings.WaitlistEntry, error) {
+	var result bookings.WaitlistEntry
+	var promoCode, bookingId sql.NullString
+
+	if err := row.Scan(
+		&result.Id,
+		&result.FirstName,
+		&result.LastName,
+		&result.Gender,
+		&result.Birthday,
+		&result.Email,
+		&result.Phone,
+		&result.LaunchPadId,
+		&result.DestinationId,
+		&result.LaunchDate,
+		&result.Direction,
+		&promoCode,
+		&result.Reason,
+		&result.Status,
+		&bookingId,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("error scanning waitlist: %w", err)
+	}
+
+	result.PromoCode = promoCode.String
+	result.BookingId = bookingId.String
+
+	return &result, nil
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+)
+
+// NewClient returns the client used to call the SpaceX API and payment providers, with the timeouts and connection
+// settings from config.
+func NewClient(cfg config.Config) *http.Client {
+	return &http.Client{
+		Timeout: time.Duration(cfg.HTTPTimeout) * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:      cfg.MaxIdleConns,
+			MaxConnsPerHost:   cfg.MaxConnsPerHost,
+			IdleConnTimeout:   time.Duration(cfg.IdleConnTimeoutSecs) * time.Second,
+			DisableKeepAlives: cfg.DisableKeepAlives,
+			DialContext: (&net.Dialer{
+				Timeout:   time.Duration(cfg.DialerTimeoutSecs) * time.Second,
+				KeepAlive: time.Duration(cfg.DialerKeepAliveSecs) * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: time.Duration(cfg.TLSHandshakeTimeoutSecs) * time.Second,
+		},
+	}
+}
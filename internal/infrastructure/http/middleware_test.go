@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServer_RequireAdmin(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminAPIKey    string
+		authorization  string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Correct bearer token, request allowed",
+			adminAPIKey:    "secret",
+			authorization:  "Bearer secret",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "2. Wrong bearer token, returns 401",
+			adminAPIKey:    "secret",
+			authorization:  "Bearer guess",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "3. No bearer token, returns 401",
+			adminAPIKey:    "secret",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "4. Admin API disabled, returns 401 even with an empty bearer token",
+			adminAPIKey:    "",
+			authorization:  "Bearer ",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Server{adminAPIKey: tt.adminAPIKey}
+			handler := s.RequireAdmin(func(w http.ResponseWriter, r *http.Request) {})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/launchpads", nil)
+			if len(tt.authorization) > 0 {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
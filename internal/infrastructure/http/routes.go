@@ -7,13 +7,63 @@ import (
 )
 
 // NewMux sets up routes for the API.
-func (s *Server) NewMux(handlers api.BookingHandlers) *http.ServeMux {
+func (s *Server) NewMux(handlers api.BookingHandlers, admin api.AdminHandlers) *http.ServeMux {
 	const baseURL = "/api/v1"
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET "+baseURL+"/bookings", handlers.Get)
+	mux.HandleFunc("GET "+baseURL+"/quote", handlers.GetQuote)
 	mux.HandleFunc("POST "+baseURL+"/booking", handlers.Post)
+	mux.HandleFunc("POST "+baseURL+"/bookings/group", handlers.PostGroup)
 	mux.HandleFunc("DELETE "+baseURL+"/booking/{id}", handlers.Delete)
+	mux.HandleFunc("POST "+baseURL+"/booking/{id}/return", handlers.PostReturn)
+	mux.HandleFunc("POST "+baseURL+"/holds", handlers.PostHold)
+	mux.HandleFunc("POST "+baseURL+"/holds/{id}/confirm", handlers.PostHoldConfirm)
+	mux.HandleFunc("GET "+baseURL+"/waitlist/{id}", handlers.GetWaitlistEntry)
+	mux.HandleFunc("POST "+baseURL+"/booking/{id}/check-in", handlers.PostCheckIn)
+	mux.HandleFunc("GET "+baseURL+"/booking/{id}/boarding-pass", handlers.GetBoardingPass)
+	mux.HandleFunc("GET "+baseURL+"/boarding/key", handlers.GetBoardingPassKey)
+	// Boarding passengers is for gate staff, so it needs the admin API key.
+	mux.Handle("POST "+baseURL+"/boarding/verify", s.RequireAdmin(handlers.PostBoardingVerify))
+	// The manifest lists passengers' personal details, so only admins can download it.
+	mux.Handle("GET "+baseURL+"/flights/{launchpad_id}/{date}/manifest", s.RequireAdmin(admin.GetManifest))
+
+	const adminURL = baseURL + "/admin"
+
+	mux.Handle("GET "+adminURL+"/launchpads", s.RequireAdmin(admin.GetLaunchPads))
+	mux.Handle("POST "+adminURL+"/launchpads", s.RequireAdmin(admin.PostLaunchPad))
+	mux.Handle("PUT "+adminURL+"/launchpads/{id}", s.RequireAdmin(admin.PutLaunchPad))
+	mux.Handle("DELETE "+adminURL+"/launchpads/{id}", s.RequireAdmin(admin.DeleteLaunchPad))
+	mux.Handle("GET "+adminURL+"/destinations", s.RequireAdmin(admin.GetDestinations))
+	mux.Handle("POST "+adminURL+"/destinations", s.RequireAdmin(admin.PostDestination))
+	mux.Handle("PUT "+adminURL+"/destinations/{id}", s.RequireAdmin(admin.PutDestination))
+	mux.Handle("DELETE "+adminURL+"/destinations/{id}", s.RequireAdmin(admin.DeleteDestination))
+	mux.Handle("GET "+adminURL+"/schedule", s.RequireAdmin(admin.GetSchedule))
+	mux.Handle("POST "+adminURL+"/schedule", s.RequireAdmin(admin.PostScheduleEntry))
+	mux.Handle("PUT "+adminURL+"/schedule/{id}", s.RequireAdmin(admin.PutScheduleEntry))
+	mux.Handle("DELETE "+adminURL+"/schedule/{id}", s.RequireAdmin(admin.DeleteScheduleEntry))
+	mux.Handle("GET "+adminURL+"/schedule-exceptions", s.RequireAdmin(admin.GetScheduleExceptions))
+	mux.Handle("POST "+adminURL+"/schedule-exceptions", s.RequireAdmin(admin.PostScheduleException))
+	mux.Handle("PUT "+adminURL+"/schedule-exceptions/{id}", s.RequireAdmin(admin.PutScheduleException))
+	mux.Handle("DELETE "+adminURL+"/schedule-exceptions/{id}", s.RequireAdmin(admin.DeleteScheduleException))
+	mux.Handle("GET "+adminURL+"/travel-times", s.RequireAdmin(admin.GetTravelTimes))
+	mux.Handle("PUT "+adminURL+"/travel-times/{launch_pad_id}/{destination_id}", s.RequireAdmin(admin.PutTravelTime))
+	mux.Handle("DELETE "+adminURL+"/travel-times/{launch_pad_id}/{destination_id}", s.RequireAdmin(admin.DeleteTravelTime))
+	mux.Handle("GET "+adminURL+"/fares", s.RequireAdmin(admin.GetFares))
+	mux.Handle("PUT "+adminURL+"/fares/{launch_pad_id}/{destination_id}", s.RequireAdmin(admin.PutFare))
+	mux.Handle("DELETE "+adminURL+"/fares/{launch_pad_id}/{destination_id}", s.RequireAdmin(admin.DeleteFare))
+	mux.Handle("GET "+adminURL+"/fare-rules", s.RequireAdmin(admin.GetFareRules))
+	mux.Handle("POST "+adminURL+"/fare-rules", s.RequireAdmin(admin.PostFareRule))
+	mux.Handle("DELETE "+adminURL+"/fare-rules/{id}", s.RequireAdmin(admin.DeleteFareRule))
+	mux.Handle("GET "+adminURL+"/eligibility-rules", s.RequireAdmin(admin.GetEligibilityRules))
+	mux.Handle("POST "+adminURL+"/eligibility-rules", s.RequireAdmin(admin.PostEligibilityRule))
+	mux.Handle("DELETE "+adminURL+"/eligibility-rules/{id}", s.RequireAdmin(admin.DeleteEligibilityRule))
+	mux.Handle("GET "+adminURL+"/promo-codes", s.RequireAdmin(admin.GetPromoCodes))
+	mux.Handle("PUT "+adminURL+"/promo-codes/{code}", s.RequireAdmin(admin.PutPromoCode))
+	mux.Handle("DELETE "+adminURL+"/promo-codes/{code}", s.RequireAdmin(admin.DeletePromoCode))
+	mux.Handle("GET "+adminURL+"/bookings", s.RequireAdmin(admin.GetBookings))
+	mux.Handle("POST "+adminURL+"/bookings/{id}/cancel", s.RequireAdmin(admin.CancelBooking))
+	mux.Handle("POST "+adminURL+"/bookings/{id}/status", s.RequireAdmin(admin.PostBookingStatus))
 
 	return mux
 }
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/petherin/spacetickets/internal/interfaces/api"
+)
+
+func TestServer_NewMux_RequiresAdmin(t *testing.T) {
+	s := Server{adminAPIKey: "secret"}
+	mux := s.NewMux(api.BookingHandlers{}, api.AdminHandlers{})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{name: "1. Boarding verify", method: http.MethodPost, path: "/api/v1/boarding/verify"},
+		{name: "2. Manifest", method: http.MethodGet, path: "/api/v1/flights/uuid-1/2024-01-01/manifest"},
+		{name: "3. Bookings with contact details", method: http.MethodGet, path: "/api/v1/admin/bookings"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("wrong status code without the admin API key: got %v want %v", w.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
@@ -9,14 +9,16 @@ import (
 
 // Server encapsulates an HTTP server.
 type Server struct {
-	HTTPServer *http.Server
+	HTTPServer  *http.Server
+	adminAPIKey string
 }
 
 // New creates a new server, setting its address and handlers to those passed in.
-func New(addr string, handlers api.BookingHandlers) Server {
-	server := Server{}
+// Admin endpoints require adminAPIKey as a bearer token, and are disabled if it's empty.
+func New(addr string, handlers api.BookingHandlers, admin api.AdminHandlers, adminAPIKey string) Server {
+	server := Server{adminAPIKey: adminAPIKey}
 
-	mux := server.NewMux(handlers)
+	mux := server.NewMux(handlers, admin)
 	mw := server.RecoverPanic(server.LogRequest(server.CORS(mux)))
 	svr := http.Server{
 		Addr:         addr,
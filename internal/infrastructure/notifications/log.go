@@ -0,0 +1,23 @@
+package notifications
+
+import (
+	"log"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// Log writes notifications to the log instead of sending them, so the API can run without a mail server.
+type Log struct{}
+
+// NewLog returns a Log notifier.
+func NewLog() Log {
+	return Log{}
+}
+
+// Notify logs the notification.
+func (Log) Notify(notification bookings.Notification) error {
+	log.Printf("Notification %s for booking %s to %s: %s\n%s", notification.Kind, notification.BookingId, notification.To,
+		notification.Subject, notification.Body)
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package notifications
+
+import (
+	"fmt"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+)
+
+// Open returns the bookings.Notifier selected by the Notifier config.
+func Open(cfg config.Config) (bookings.Notifier, error) {
+	switch cfg.Notifier {
+	case config.NotifierLog:
+		return NewLog(), nil
+	case config.NotifierSMTP:
+		return NewSMTP(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword), nil
+	default:
+		return nil, fmt.Errorf("unrecognised notifier %s", cfg.Notifier)
+	}
+}
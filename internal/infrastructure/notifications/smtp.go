@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// SMTP emails notifications through the mail server at Addr, e.g. a provider's relay or a local SMTP sink such as
+// Mailpit. Emails are plain text and come from From.
+type SMTP struct {
+	Addr string
+	From string
+	// Auth logs in to the mail server. It's nil when the server doesn't need a login.
+	Auth smtp.Auth
+}
+
+// NewSMTP returns a new SMTP notifier, assigning passed dependencies. It logs in with PLAIN auth if username is set,
+// which net/smtp only allows over TLS or to localhost.
+func NewSMTP(addr, from, username, password string) *SMTP {
+	s := &SMTP{Addr: addr, From: from}
+
+	if len(username) > 0 {
+		host, _, _ := net.SplitHostPort(addr)
+		s.Auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return s
+}
+
+// Notify emails the notification to its recipient.
+func (s *SMTP) Notify(notification bookings.Notification) error {
+	if err := smtp.SendMail(s.Addr, s.Auth, s.From, []string{notification.To}, s.message(notification, time.Now())); err != nil {
+		return fmt.Errorf("error emailing %s: %w", notification.To, err)
+	}
+
+	return nil
+}
+
+// message returns the notification as an email sent at now. The subject is encoded in case a launchpad or
+// destination's name isn't ASCII.
+func (s *SMTP) message(notification bookings.Notification, now time.Time) []byte {
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(notification.Subject)
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", s.From)
+	fmt.Fprintf(&message, "To: %s\r\n", notification.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+
+	return []byte(message.String())
+}
@@ -0,0 +1,126 @@
+package notifications
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// sinkMail is an email received by the SMTP sink.
+type sinkMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// newSMTPSink starts a local SMTP server that accepts one email and sends it on the channel. If rejectRecipients is
+// set it refuses every recipient instead.
+func newSMTPSink(t *testing.T, rejectRecipients bool) (string, <-chan sinkMail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan sinkMail, 1)
+
+	go func() {
+		c, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn := textproto.NewConn(c)
+		defer conn.Close()
+
+		var mail sinkMail
+		conn.PrintfLine("220 localhost ESMTP sink")
+		for {
+			line, err := conn.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case command == "EHLO" || command == "HELO":
+				conn.PrintfLine("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				mail.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				conn.PrintfLine("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				if rejectRecipients {
+					conn.PrintfLine("550 No such user")
+					continue
+				}
+				mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				conn.PrintfLine("250 OK")
+			case command == "DATA":
+				conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				lines, err := conn.ReadDotLines()
+				if err != nil {
+					return
+				}
+				mail.data = strings.Join(lines, "\n")
+				conn.PrintfLine("250 OK")
+				received <- mail
+			case command == "QUIT":
+				conn.PrintfLine("221 Bye")
+				return
+			default:
+				conn.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTP_Notify(t *testing.T) {
+	addr, received := newSMTPSink(t, false)
+
+	notification := bookings.Notification{
+		Kind:      bookings.NotifyConfirmed,
+		BookingId: "booking-1",
+		To:        "ian@example.com",
+		Subject:   "Your flight to Pluto on Monday 14 January 2030 is confirmed",
+		Body:      "Hi Ian,\n\nYour seat from Cape Canaveral to Pluto is confirmed.\n",
+	}
+
+	if err := NewSMTP(addr, "bookings@spacetickets.example", "", "").Notify(notification); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mail := <-received
+	if mail.from != "bookings@spacetickets.example" {
+		t.Errorf("wrong sender, got %s", mail.from)
+	}
+	if len(mail.to) != 1 || mail.to[0] != notification.To {
+		t.Errorf("wrong recipients, got %v want %s", mail.to, notification.To)
+	}
+
+	for _, want := range []string{
+		"From: bookings@spacetickets.example",
+		"To: ian@example.com",
+		"Subject: Your flight to Pluto on Monday 14 January 2030 is confirmed",
+		"Content-Type: text/plain; charset=utf-8",
+		"Your seat from Cape Canaveral to Pluto is confirmed.",
+	} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("email missing %q, got %s", want, mail.data)
+		}
+	}
+}
+
+func TestSMTP_Notify_Rejected(t *testing.T) {
+	addr, _ := newSMTPSink(t, true)
+
+	err := NewSMTP(addr, "bookings@spacetickets.example", "", "").Notify(bookings.Notification{To: "nobody@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "nobody@example.com") {
+		t.Errorf("wrong error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package payments
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// Fake takes payments in process without contacting a payment provider, so the API can run without one.
+type Fake struct {
+	// Decline makes every charge fail with bookings.ErrPaymentDeclined.
+	Decline bool
+	// Err, when set, makes every charge fail with it without taking the payment, as if the provider timed out.
+	Err error
+	// RefundErr, when set, makes every refund fail with it without making the refund.
+	RefundErr error
+
+	mu       sync.Mutex
+	payments []bookings.Payment
+	refunds  []bookings.Refund
+}
+
+// NewFake returns a Fake that accepts every charge.
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Charge records the payment and returns it, or bookings.ErrPaymentDeclined if Decline is set. Charging a reference
+// that's already been paid returns its payment again.
+func (f *Fake) Charge(charge bookings.Charge) (*bookings.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, payment := range f.payments {
+		if payment.Reference == charge.Reference {
+			return &payment, nil
+		}
+	}
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	if f.Decline {
+		return nil, bookings.ErrPaymentDeclined
+	}
+
+	payment := bookings.Payment{
+		Id:        uuid.NewString(),
+		Reference: charge.Reference,
+		Amount:    charge.Amount,
+		Currency:  charge.Currency,
+	}
+
+	f.payments = append(f.payments, payment)
+
+	return &payment, nil
+}
+
+// Payments returns the payments taken so far.
+func (f *Fake) Payments() []bookings.Payment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]bookings.Payment{}, f.payments...)
+}
+
+// Refund records the refund and returns it with an id, or RefundErr if it's set. Refunding a reference that's already
+// been refunded returns its refund again.
+func (f *Fake) Refund(refund bookings.Refund) (*bookings.Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, existing := range f.refunds {
+		if existing.Reference == refund.Reference {
+			return &existing, nil
+		}
+	}
+
+	if f.RefundErr != nil {
+		return nil, f.RefundErr
+	}
+
+	refund.Id = uuid.NewString()
+	f.refunds = append(f.refunds, refund)
+
+	return &refund, nil
+}
+
+// Refunds returns the refunds made so far.
+func (f *Fake) Refunds() []bookings.Refund {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]bookings.Refund{}, f.refunds...)
+}
@@ -0,0 +1,21 @@
+package payments
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+)
+
+// Open returns the bookings.PaymentGateway selected by the PaymentGateway config.
+func Open(cfg config.Config, client *http.Client) (bookings.PaymentGateway, error) {
+	switch cfg.PaymentGateway {
+	case config.PaymentGatewayFake:
+		return NewFake(), nil
+	case config.PaymentGatewayHTTP:
+		return NewHTTP(client, cfg.PaymentGatewayEndpoint), nil
+	default:
+		return nil, fmt.Errorf("unrecognised payment gateway %s", cfg.PaymentGateway)
+	}
+}
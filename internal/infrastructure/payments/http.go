@@ -0,0 +1,83 @@
+package payments
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// HTTP takes payments through a payment provider's API at Endpoint, or a local stand-in for it.
+//
+// Charges are POSTed as JSON to /v1/charges. The provider responds 200 or 201 with the payment, or 402 Payment
+// Required if the payment is declined. Refunds are POSTed to /v1/refunds, and the provider responds 200 or 201 with
+// the refund and its id.
+type HTTP struct {
+	Client   *http.Client
+	Endpoint string
+}
+
+// NewHTTP returns a new HTTP gateway, assigning passed dependencies.
+func NewHTTP(client *http.Client, endpoint string) *HTTP {
+	return &HTTP{Client: client, Endpoint: endpoint}
+}
+
+// Charge asks the provider to take the charge.
+func (h *HTTP) Charge(charge bookings.Charge) (*bookings.Payment, error) {
+	var payment bookings.Payment
+	if err := h.post("/v1/charges", charge, &payment); err != nil {
+		return nil, fmt.Errorf("error charging %s: %w", charge.Reference, err)
+	}
+
+	return &payment, nil
+}
+
+// Refund asks the provider to give back some or all of a payment.
+func (h *HTTP) Refund(refund bookings.Refund) (*bookings.Refund, error) {
+	var result bookings.Refund
+	if err := h.post("/v1/refunds", refund, &result); err != nil {
+		return nil, fmt.Errorf("error refunding %s: %w", refund.Reference, err)
+	}
+
+	return &result, nil
+}
+
+// post sends the request to the path as JSON and decodes the response into result. A 402 response returns
+// bookings.ErrPaymentDeclined.
+func (h *HTTP) post(path string, request, result any) error {
+	fullURL, err := url.JoinPath(h.Endpoint, path)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fullURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusPaymentRequired:
+		return bookings.ErrPaymentDeclined
+	default:
+		return fmt.Errorf("payment gateway returned %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
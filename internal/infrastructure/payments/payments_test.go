@@ -0,0 +1,119 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+func TestHTTP_Charge(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		want       *bookings.Payment
+		wantErr    error
+	}{
+		{
+			name:       "1. Charge accepted, payment returned",
+			statusCode: http.StatusCreated,
+			want:       &bookings.Payment{Id: "pay-1", Reference: "booking-1", Amount: 1500, Currency: "USD"},
+		},
+		{
+			name:       "2. Charge declined, ErrPaymentDeclined returned",
+			statusCode: http.StatusPaymentRequired,
+			wantErr:    bookings.ErrPaymentDeclined,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/charges" {
+					t.Errorf("wrong request, got %s %s", r.Method, r.URL.Path)
+				}
+
+				var charge bookings.Charge
+				if err := json.NewDecoder(r.Body).Decode(&charge); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				json.NewEncoder(w).Encode(bookings.Payment{Id: "pay-1", Reference: charge.Reference, Amount: charge.Amount, Currency: charge.Currency})
+			}))
+			defer server.Close()
+
+			got, err := NewHTTP(server.Client(), server.URL).Charge(bookings.Charge{Reference: "booking-1", Amount: 1500, Currency: "USD"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("wrong error, got %v want %v", err, tt.wantErr)
+			}
+
+			if tt.want != nil && (got == nil || *got != *tt.want) {
+				t.Errorf("wrong payment, got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTP_Charge_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTP(server.Client(), server.URL).Charge(bookings.Charge{Reference: "booking-1", Amount: 1500, Currency: "USD"})
+	if err == nil || errors.Is(err, bookings.ErrPaymentDeclined) {
+		t.Errorf("wrong error, got %v", err)
+	}
+}
+
+func TestHTTP_Refund(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/refunds" {
+			t.Errorf("wrong request, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var refund bookings.Refund
+		if err := json.NewDecoder(r.Body).Decode(&refund); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		refund.Id = "refund-1"
+		json.NewEncoder(w).Encode(refund)
+	}))
+	defer server.Close()
+
+	got, err := NewHTTP(server.Client(), server.URL).Refund(bookings.Refund{PaymentId: "pay-1", Reference: "booking-1", Amount: 750, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := bookings.Refund{Id: "refund-1", PaymentId: "pay-1", Reference: "booking-1", Amount: 750, Currency: "USD"}
+	if *got != want {
+		t.Errorf("wrong refund, got %+v want %+v", got, want)
+	}
+}
+
+func TestFake_Charge(t *testing.T) {
+	fake := NewFake()
+
+	payment, err := fake.Charge(bookings.Charge{Reference: "booking-1", Amount: 1500, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payment.Id) == 0 || payment.Amount != 1500 {
+		t.Errorf("wrong payment, got %+v", payment)
+	}
+
+	fake.Decline = true
+	if _, err := fake.Charge(bookings.Charge{Reference: "booking-2", Amount: 1500, Currency: "USD"}); !errors.Is(err, bookings.ErrPaymentDeclined) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrPaymentDeclined)
+	}
+
+	if got := len(fake.Payments()); got != 1 {
+		t.Errorf("wrong number of payments, got %d want %d", got, 1)
+	}
+}
@@ -0,0 +1,384 @@
+// Package spacexstub is a local stand-in for the SpaceX API, backed by a fixture of launches, so the API's SpaceX
+// checks can be exercised over real HTTP without SpaceX. Faults can be switched on to test how the API copes when
+// SpaceX are slow, down or broken.
+package spacexstub
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// The faults the stub can be switched to.
+const (
+	// FaultNone answers every request normally.
+	FaultNone = "none"
+	// FaultLatency answers every request normally, after waiting for the stub's latency.
+	FaultLatency = "latency"
+	// FaultError answers every request with a 500.
+	FaultError = "error"
+	// FaultMalformed answers every request with a 200 and a body that isn't valid JSON.
+	FaultMalformed = "malformed"
+	// FaultTimeout never answers, the request hangs until the client gives up or the stub is closed.
+	FaultTimeout = "timeout"
+)
+
+// defaultLimit is how many launches a page has when the query's options don't set a limit, as in the SpaceX API.
+const defaultLimit = 10
+
+//go:embed fixture.json
+var defaultFixture []byte
+
+// Stub serves launches from a fixture in the SpaceX API's formats.
+type Stub struct {
+	// launches never change once the stub is created, mu only guards the fault.
+	launches []bookings.ExternalLaunch
+	// closed releases requests held by FaultLatency and FaultTimeout when the stub is closed.
+	closed    chan struct{}
+	closeOnce sync.Once
+	mu        sync.RWMutex
+	fault     string
+	latency   time.Duration
+}
+
+// New returns a new Stub serving the launches, sorted by date, with no fault.
+func New(launches []bookings.ExternalLaunch) *Stub {
+	sorted := append([]bookings.ExternalLaunch(nil), launches...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DateUTC.Before(sorted[j].DateUTC) })
+
+	return &Stub{launches: sorted, closed: make(chan struct{}), fault: FaultNone}
+}
+
+// Close releases the requests the stub is holding because of a fault, so a server using it can shut down.
+func (s *Stub) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// DefaultFixture returns the launches in the fixture built into the stub. They include launches from Cape Canaveral,
+// Kennedy and Kwajalein in January 2030, after the real SpaceX data ends.
+func DefaultFixture() ([]bookings.ExternalLaunch, error) {
+	return bookings.ParseLaunchSnapshot(bytes.NewReader(defaultFixture))
+}
+
+// SetFault switches the stub to the fault. Latency is only used by FaultLatency.
+func (s *Stub) SetFault(fault string, latency time.Duration) error {
+	switch fault {
+	case FaultNone, FaultLatency, FaultError, FaultMalformed, FaultTimeout:
+	default:
+		return fmt.Errorf("unrecognised fault %s", fault)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fault = fault
+	s.latency = latency
+
+	return nil
+}
+
+// Handler returns the stub's routes. POST /v4/launches/query and GET /v4/launches answer like the SpaceX API, subject
+// to the current fault. PUT /stub/fault?mode=latency&latency=2s switches the fault while the stub is running.
+func (s *Stub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v4/launches/query", s.withFault(s.queryLaunches))
+	mux.HandleFunc("GET /v4/launches", s.withFault(s.getLaunches))
+	mux.HandleFunc("PUT /stub/fault", s.putFault)
+
+	return mux
+}
+
+// withFault applies the current fault before calling next.
+func (s *Stub) withFault(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		fault, latency := s.fault, s.latency
+		s.mu.RUnlock()
+
+		switch fault {
+		case FaultLatency:
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			case <-s.closed:
+				return
+			}
+		case FaultError:
+			http.Error(w, `{"error":"stubbed SpaceX API error"}`, http.StatusInternalServerError)
+			return
+		case FaultMalformed:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"docs": [{"id": `))
+			return
+		case FaultTimeout:
+			select {
+			case <-r.Context().Done():
+			case <-s.closed:
+			}
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// putFault switches the fault to the mode and latency query parameters.
+func (s *Stub) putFault(w http.ResponseWriter, r *http.Request) {
+	var latency time.Duration
+	if value := r.URL.Query().Get("latency"); len(value) > 0 {
+		var err error
+		latency, err = time.ParseDuration(value)
+		if err != nil {
+			http.Error(w, "latency must be a duration, e.g. 2s", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.SetFault(r.URL.Query().Get("mode"), latency); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("SpaceX stub fault set to %s\n", r.URL.Query().Get("mode"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getLaunches returns every launch.
+func (s *Stub) getLaunches(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.launches)
+}
+
+// queryRequest is the body of a query, as sent by bookings.SpaceXLaunchesRequest. Only the query and the pagination
+// options are used.
+type queryRequest struct {
+	Query   map[string]json.RawMessage `json:"query"`
+	Options struct {
+		Pagination *bool `json:"pagination"`
+		Limit      *int  `json:"limit"`
+		Page       int   `json:"page"`
+	} `json:"options"`
+}
+
+// queryResponse is a page of launches, with the pagination fields the SpaceX API returns.
+type queryResponse struct {
+	Docs          []bookings.ExternalLaunch `json:"docs"`
+	TotalDocs     int                       `json:"totalDocs"`
+	Offset        int                       `json:"offset"`
+	Limit         int                       `json:"limit"`
+	TotalPages    int                       `json:"totalPages"`
+	Page          int                       `json:"page"`
+	PagingCounter int                       `json:"pagingCounter"`
+	HasPrevPage   bool                      `json:"hasPrevPage"`
+	HasNextPage   bool                      `json:"hasNextPage"`
+	PrevPage      *int                      `json:"prevPage"`
+	NextPage      *int                      `json:"nextPage"`
+}
+
+// queryLaunches returns the page of launches matching the query. As in the SpaceX API, a limit of 0 only returns the
+// count, and turning pagination off returns every match.
+func (s *Stub) queryLaunches(w http.ResponseWriter, r *http.Request) {
+	var req queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("could not parse query: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	matched := []bookings.ExternalLaunch{}
+	for _, launch := range s.launches {
+		ok, err := matches(launch, req.Query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ok {
+			matched = append(matched, launch)
+		}
+	}
+
+	writeJSON(w, paginate(matched, req))
+}
+
+// paginate returns the page of launches the request's options ask for.
+func paginate(launches []bookings.ExternalLaunch, req queryRequest) queryResponse {
+	total := len(launches)
+
+	if req.Options.Pagination != nil && !*req.Options.Pagination {
+		return queryResponse{Docs: launches, TotalDocs: total, Limit: total, TotalPages: 1, Page: 1, PagingCounter: 1}
+	}
+
+	limit := defaultLimit
+	if req.Options.Limit != nil {
+		limit = *req.Options.Limit
+	}
+
+	page := max(req.Options.Page, 1)
+
+	if limit <= 0 {
+		return queryResponse{Docs: []bookings.ExternalLaunch{}, TotalDocs: total, TotalPages: 1, Page: 1, PagingCounter: 1}
+	}
+
+	totalPages := max((total+limit-1)/limit, 1)
+	offset := (page - 1) * limit
+	end := min(offset+limit, total)
+
+	docs := []bookings.ExternalLaunch{}
+	if offset < total {
+		docs = launches[offset:end]
+	}
+
+	resp := queryResponse{
+		Docs:          docs,
+		TotalDocs:     total,
+		Offset:        offset,
+		Limit:         limit,
+		TotalPages:    totalPages,
+		Page:          page,
+		PagingCounter: offset + 1,
+		HasPrevPage:   page > 1,
+		HasNextPage:   page < totalPages,
+	}
+	if resp.HasPrevPage {
+		prev := page - 1
+		resp.PrevPage = &prev
+	}
+	if resp.HasNextPage {
+		next := page + 1
+		resp.NextPage = &next
+	}
+
+	return resp
+}
+
+// matches returns true if the launch meets every condition in the query. A condition is either a value the field must
+// equal, or an object of $eq, $ne, $gt, $gte, $lt, $lte and $in operators, as in MongoDB. Only the id, name,
+// launchpad and date_utc fields can be queried, so a query the stub doesn't understand fails instead of matching
+// everything.
+func matches(launch bookings.ExternalLaunch, query map[string]json.RawMessage) (bool, error) {
+	for field, condition := range query {
+		var value any
+		switch field {
+		case "id", "_id":
+			value = launch.Id
+		case "name":
+			value = launch.Name
+		case "launchpad":
+			value = launch.LaunchPadId
+		case "date_utc":
+			value = launch.DateUTC
+		default:
+			return false, fmt.Errorf("unsupported query field %s", field)
+		}
+
+		ok, err := meets(value, condition)
+		if err != nil {
+			return false, fmt.Errorf("invalid condition on %s: %w", field, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// meets returns true if the value meets the condition.
+func meets(value any, condition json.RawMessage) (bool, error) {
+	if !strings.HasPrefix(strings.TrimSpace(string(condition)), "{") {
+		c, err := compare(value, condition)
+		return c == 0, err
+	}
+
+	var operators map[string]json.RawMessage
+	if err := json.Unmarshal(condition, &operators); err != nil {
+		return false, err
+	}
+
+	for operator, operand := range operators {
+		if operator == "$in" {
+			var options []json.RawMessage
+			if err := json.Unmarshal(operand, &options); err != nil {
+				return false, err
+			}
+
+			found := false
+			for _, option := range options {
+				c, err := compare(value, option)
+				if err != nil {
+					return false, err
+				}
+				found = found || c == 0
+			}
+			if !found {
+				return false, nil
+			}
+			continue
+		}
+
+		c, err := compare(value, operand)
+		if err != nil {
+			return false, err
+		}
+
+		var ok bool
+		switch operator {
+		case "$eq":
+			ok = c == 0
+		case "$ne":
+			ok = c != 0
+		case "$gt":
+			ok = c > 0
+		case "$gte":
+			ok = c >= 0
+		case "$lt":
+			ok = c < 0
+		case "$lte":
+			ok = c <= 0
+		default:
+			return false, fmt.Errorf("unsupported operator %s", operator)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// compare returns -1, 0 or 1 as the value is less than, equal to or greater than the operand, which is decoded as the
+// value's type.
+func compare(value any, operand json.RawMessage) (int, error) {
+	switch v := value.(type) {
+	case time.Time:
+		var t time.Time
+		if err := json.Unmarshal(operand, &t); err != nil {
+			return 0, err
+		}
+		return v.Compare(t), nil
+	case string:
+		var s string
+		if err := json.Unmarshal(operand, &s); err != nil {
+			return 0, err
+		}
+		return strings.Compare(v, s), nil
+	default:
+		return 0, errors.New("unsupported value")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err)
+	}
+}
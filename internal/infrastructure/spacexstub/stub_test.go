@@ -0,0 +1,146 @@
+package spacexstub
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+	"github.com/petherin/spacetickets/internal/infrastructure/http"
+	"github.com/petherin/spacetickets/internal/interfaces/api"
+)
+
+const capeCanaveralSpaceXId = "5e9e4501f509094ba4566f84"
+
+// newTestHandlers returns handlers that check SpaceX through the stub, with a client built from config like the API's.
+func newTestHandlers(t *testing.T, stub *Stub) api.BookingHandlers {
+	t.Helper()
+
+	server := httptest.NewServer(stub.Handler())
+	t.Cleanup(server.Close)
+	// Cleanups run last first, so held requests are released before the server waits for them.
+	t.Cleanup(stub.Close)
+
+	client := http.NewClient(config.Config{
+		HTTPTimeout:             1,
+		MaxIdleConns:            1,
+		MaxConnsPerHost:         1,
+		IdleConnTimeoutSecs:     10,
+		DialerTimeoutSecs:       1,
+		TLSHandshakeTimeoutSecs: 1,
+		DisableKeepAlives:       true,
+	})
+
+	return api.NewBookingHandlers(nil, client, server.URL, nil, bookings.RefundPolicy{})
+}
+
+func TestStub_Query(t *testing.T) {
+	launches, err := DefaultFixture()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handlers := newTestHandlers(t, New(launches))
+
+	cots1Day, _ := time.Parse(time.DateOnly, "2010-12-08")
+	quietDay, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+	tests := []struct {
+		name      string
+		launchPad string
+		window    bookings.LaunchWindow
+		want      bool
+	}{
+		{
+			name:      "1. Launch during the window",
+			launchPad: capeCanaveralSpaceXId,
+			window:    bookings.LaunchWindow{Opens: cots1Day, Closes: cots1Day.AddDate(0, 0, 1)},
+			want:      true,
+		},
+		{
+			name:      "2. No launch during the window",
+			launchPad: capeCanaveralSpaceXId,
+			window:    bookings.LaunchWindow{Opens: quietDay, Closes: quietDay.AddDate(0, 0, 1)},
+			want:      false,
+		},
+		{
+			name:      "3. Launch from another launchpad",
+			launchPad: "5e9e4502f509094188566f88",
+			window:    bookings.LaunchWindow{Opens: cots1Day, Closes: cots1Day.AddDate(0, 0, 1)},
+			want:      false,
+		},
+		{
+			name:      "4. Window closes as the launch happens",
+			launchPad: capeCanaveralSpaceXId,
+			window:    bookings.LaunchWindow{Opens: cots1Day, Closes: cots1Day.Add(15*time.Hour + 43*time.Minute)},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := handlers.HasLaunch(tt.launchPad, tt.window)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("wrong result, got %t want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStub_Faults(t *testing.T) {
+	launches, err := DefaultFixture()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	day, _ := time.Parse(time.DateOnly, "2010-12-08")
+	window := bookings.LaunchWindow{Opens: day, Closes: day.AddDate(0, 0, 1)}
+
+	tests := []struct {
+		name    string
+		fault   string
+		latency time.Duration
+		wantErr bool
+	}{
+		{name: "1. Latency within the client timeout", fault: FaultLatency, latency: 100 * time.Millisecond},
+		{name: "2. Latency beyond the client timeout", fault: FaultLatency, latency: 5 * time.Second, wantErr: true},
+		{name: "3. Server error", fault: FaultError, wantErr: true},
+		{name: "4. Malformed JSON", fault: FaultMalformed, wantErr: true},
+		{name: "5. Timeout", fault: FaultTimeout, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := New(launches)
+			if err := stub.SetFault(tt.fault, tt.latency); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			handlers := newTestHandlers(t, stub)
+
+			start := time.Now()
+			clash, err := handlers.HasLaunch(capeCanaveralSpaceXId, window)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got %v, want error %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !clash {
+				t.Errorf("expected a clash")
+			}
+
+			// The client gives up after HTTP_TIMEOUT_SECS rather than waiting for the stub.
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("request took %s, client timeout not applied", elapsed)
+			}
+		})
+	}
+}
+
+func TestStub_SetFault(t *testing.T) {
+	if err := New(nil).SetFault("meteor-strike", 0); err == nil {
+		t.Errorf("expected an error for an unrecognised fault")
+	}
+}
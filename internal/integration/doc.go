@@ -0,0 +1,4 @@
+// Package integration holds end-to-end tests that run the API's HTTP server against a real Postgres database and a
+// stand-in for the SpaceX API. They're behind the integration build tag, run them with
+// `go test -tags integration ./internal/integration/...`.
+package integration
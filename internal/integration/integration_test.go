@@ -0,0 +1,203 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+	"github.com/petherin/spacetickets/internal/infrastructure/http"
+	"github.com/petherin/spacetickets/internal/infrastructure/payments"
+	"github.com/petherin/spacetickets/internal/infrastructure/spacexstub"
+	"github.com/petherin/spacetickets/internal/interfaces/api"
+)
+
+const (
+	capeCanaveralId = "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975"
+	moonId          = "466fc378-14eb-4ed9-8bec-d29abe54c5a9"
+)
+
+// newServer starts the API's HTTP server on a random port, storing bookings in repo and checking SpaceX against the
+// stub's built-in fixture, and returns its base URL.
+func newServer(t *testing.T, repo bookings.Booker) string {
+	t.Helper()
+
+	launches, err := spacexstub.DefaultFixture()
+	if err != nil {
+		t.Fatalf("could not load SpaceX fixture: %v", err)
+	}
+
+	stub := spacexstub.New(launches)
+	spaceX := httptest.NewServer(stub.Handler())
+	t.Cleanup(spaceX.Close)
+	t.Cleanup(stub.Close)
+
+	client := http.NewClient(config.Config{
+		HTTPTimeout:             5,
+		MaxIdleConns:            1,
+		MaxConnsPerHost:         1,
+		IdleConnTimeoutSecs:     10,
+		DialerTimeoutSecs:       3,
+		TLSHandshakeTimeoutSecs: 2,
+		DisableKeepAlives:       true,
+	})
+
+	gateway := payments.NewFake()
+	handlers := api.NewBookingHandlers(repo, client, spaceX.URL, gateway, bookings.DefaultRefundPolicy)
+	admin := api.NewAdminHandlers(repo, gateway, bookings.DefaultRefundPolicy)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+
+	svr := http.New(listener.Addr().String(), handlers, admin, "secret")
+	go func() {
+		if err := svr.HTTPServer.Serve(listener); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			t.Errorf("server error: %v", err)
+		}
+	}()
+	t.Cleanup(func() { svr.HTTPServer.Shutdown(context.Background()) })
+
+	return "http://" + listener.Addr().String() + "/api/v1"
+}
+
+// do sends the request and decodes the JSON response into result, failing the test unless the response is a 200.
+func do(t *testing.T, method, url, body string, result any) {
+	t.Helper()
+
+	req, err := nethttp.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("could not create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("wrong status code, got %d want %d", resp.StatusCode, nethttp.StatusOK)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+}
+
+// bookingRequest returns a booking for an adult from Cape Canaveral to the Moon on the launch date.
+func bookingRequest(launchDate string) string {
+	return `{"first_name": "Ian", "last_name": "Thomson", "gender": "Male", "birthday": "1980-04-12",
+		"launch_pad_id": "` + capeCanaveralId + `", "destination_id": "` + moonId + `", "launch_date": "` + launchDate + `"}`
+}
+
+func TestBookings(t *testing.T) {
+	repo := newPostgres(t)
+	baseURL := newServer(t, repo)
+
+	// Cape Canaveral flies to the Moon on Mondays.
+	var created struct {
+		Id string `json:"id"`
+	}
+
+	t.Run("1. Create a booking", func(t *testing.T) {
+		do(t, nethttp.MethodPost, baseURL+"/booking", bookingRequest("2030-01-14"), &created)
+		if len(created.Id) == 0 {
+			t.Fatalf("booking not created")
+		}
+
+		booking, err := repo.Get(created.Id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if booking.Status != bookings.StatusConfirmed || booking.Price == nil || len(booking.PaymentId) == 0 {
+			t.Errorf("booking not paid for, got %+v", booking)
+		}
+
+		wantDeparture := time.Date(2030, 1, 14, 5, 0, 0, 0, time.UTC)
+		if booking.DepartureAt == nil || !booking.DepartureAt.Equal(wantDeparture) {
+			t.Errorf("wrong departure, got %v want %s", booking.DepartureAt, wantDeparture)
+		}
+	})
+
+	t.Run("2. List bookings", func(t *testing.T) {
+		var all []struct {
+			Id        string `json:"id"`
+			FirstName string `json:"first_name"`
+		}
+		do(t, nethttp.MethodGet, baseURL+"/bookings", "", &all)
+
+		found, seeded := false, false
+		for _, booking := range all {
+			found = found || booking.Id == created.Id
+			seeded = seeded || booking.FirstName == "Brian"
+		}
+		if !found || !seeded {
+			t.Errorf("created and seeded bookings not listed, got %+v", all)
+		}
+	})
+
+	t.Run("3. Schedule rejection", func(t *testing.T) {
+		var rejection struct {
+			Status string `json:"Status"`
+		}
+		do(t, nethttp.MethodPost, baseURL+"/booking", bookingRequest("2030-01-15"), &rejection)
+
+		want := "Flight cancelled, this launchpad does not fly to the destination on the requested day"
+		if rejection.Status != want {
+			t.Errorf("wrong status, got %q want %q", rejection.Status, want)
+		}
+	})
+
+	t.Run("4. SpaceX rejection", func(t *testing.T) {
+		var rejection struct {
+			Status string `json:"Status"`
+		}
+		do(t, nethttp.MethodPost, baseURL+"/booking", bookingRequest("2030-01-07"), &rejection)
+
+		want := "Flight cancelled, overlaps with SpaceX launch"
+		if rejection.Status != want {
+			t.Errorf("wrong status, got %q want %q", rejection.Status, want)
+		}
+	})
+
+	t.Run("5. Delete a booking", func(t *testing.T) {
+		var deleted struct {
+			Status string `json:"Status"`
+		}
+		do(t, nethttp.MethodDelete, baseURL+"/booking/"+created.Id, "", &deleted)
+
+		if deleted.Status != "Record deleted" {
+			t.Errorf("wrong status, got %q want %q", deleted.Status, "Record deleted")
+		}
+
+		booking, err := repo.Get(created.Id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !booking.Deleted || booking.Status != bookings.StatusCancelled {
+			t.Errorf("booking not cancelled, got %+v", booking)
+		}
+
+		all, err := repo.GetAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, booking := range all {
+			if booking.Id == created.Id {
+				t.Errorf("deleted booking still listed")
+			}
+		}
+	})
+}
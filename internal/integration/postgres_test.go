@@ -0,0 +1,149 @@
+//go:build integration
+
+package integration
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/petherin/spacetickets/internal/infrastructure/config"
+	"github.com/petherin/spacetickets/internal/infrastructure/database"
+)
+
+// The Postgres server the tests create their databases on. When INTEGRATION_DB_HOST isn't set, a throwaway server is
+// started with initdb and pg_ctl if they're on the PATH, otherwise the tests are skipped.
+const (
+	dbHostEnvVar     = "INTEGRATION_DB_HOST"
+	dbUsernameEnvVar = "INTEGRATION_DB_USERNAME"
+	dbPasswordEnvVar = "INTEGRATION_DB_PASSWORD"
+)
+
+// structurePath is database_structure.sql, relative to this package.
+const structurePath = "../infrastructure/database/database_structure.sql"
+
+// newPostgres returns a PostGres connected to a new database, created from database_structure.sql and dropped when
+// the test finishes.
+func newPostgres(t *testing.T) *database.PostGres {
+	t.Helper()
+
+	host, username, password := os.Getenv(dbHostEnvVar), getEnv(dbUsernameEnvVar, "postgres"), getEnv(dbPasswordEnvVar, "password")
+	if len(host) == 0 {
+		host = startPostgres(t, username)
+	}
+
+	admin, err := sql.Open("postgres", connectionString(username, password, "postgres", host))
+	if err != nil {
+		t.Fatalf("could not connect to Postgres: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	name := "spacetickets_" + randomSuffix(t)
+	if _, err := admin.Exec(`CREATE DATABASE ` + name); err != nil {
+		t.Fatalf("could not create database: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP DATABASE IF EXISTS ` + name + ` WITH (FORCE)`); err != nil {
+			t.Logf("could not drop database %s: %v", name, err)
+		}
+	})
+
+	structure, err := os.ReadFile(structurePath)
+	if err != nil {
+		t.Fatalf("could not read database structure: %v", err)
+	}
+
+	db, err := sql.Open("postgres", connectionString(username, password, name, host))
+	if err != nil {
+		t.Fatalf("could not connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(string(structure)); err != nil {
+		t.Fatalf("could not create database structure: %v", err)
+	}
+
+	repo, err := database.New(config.Config{
+		DBUsername:              username,
+		DBPassword:              password,
+		DBName:                  name,
+		DBHost:                  host,
+		DBMaxOpenConns:          5,
+		DBMaxIdleConns:          5,
+		DBConnMaxLifetimeSecs:   60,
+		DBConnRetries:           3,
+		DBConnRetryIntervalSecs: 1,
+	})
+	if err != nil {
+		t.Fatalf("could not open repo: %v", err)
+	}
+	// Registered after the drop, so the repo's connections are closed first.
+	t.Cleanup(repo.Close)
+
+	return repo
+}
+
+// startPostgres starts a throwaway Postgres server that only listens on a Unix socket in a temporary directory, and
+// returns the directory to use as the host. The server is stopped when the test finishes.
+func startPostgres(t *testing.T, username string) string {
+	t.Helper()
+
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		t.Skipf("%s not set and initdb not on PATH, skipping integration tests", dbHostEnvVar)
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		t.Skipf("%s not set and pg_ctl not on PATH, skipping integration tests", dbHostEnvVar)
+	}
+
+	dir := t.TempDir()
+	data, socket := filepath.Join(dir, "data"), filepath.Join(dir, "socket")
+	if err := os.Mkdir(socket, 0o700); err != nil {
+		t.Fatalf("could not create socket directory: %v", err)
+	}
+
+	if out, err := exec.Command(initdb, "-D", data, "-U", username, "-A", "trust").CombinedOutput(); err != nil {
+		t.Fatalf("initdb failed: %v\n%s", err, out)
+	}
+
+	options := fmt.Sprintf("-k %s -c listen_addresses=''", socket)
+	if out, err := exec.Command(pgCtl, "-D", data, "-o", options, "-l", filepath.Join(dir, "postgres.log"), "-w", "start").CombinedOutput(); err != nil {
+		t.Fatalf("pg_ctl start failed: %v\n%s", err, out)
+	}
+	t.Cleanup(func() {
+		if out, err := exec.Command(pgCtl, "-D", data, "-m", "immediate", "stop").CombinedOutput(); err != nil {
+			t.Logf("pg_ctl stop failed: %v\n%s", err, out)
+		}
+	})
+
+	return socket
+}
+
+func connectionString(username, password, name, host string) string {
+	return fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable host=%s", username, password, name, host)
+}
+
+func getEnv(name, fallback string) string {
+	if value := os.Getenv(name); len(value) > 0 {
+		return value
+	}
+
+	return fallback
+}
+
+func randomSuffix(t *testing.T) string {
+	t.Helper()
+
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("could not generate database name: %v", err)
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,932 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// AdminHandlers provides methods and dependencies needed to handle requests to the admin API, which manages the
+// launchpads, destinations and schedule that bookings are validated against.
+type AdminHandlers struct {
+	Booker       bookings.Booker
+	Payments     bookings.PaymentGateway
+	RefundPolicy bookings.RefundPolicy
+	// CheckInOpens is how long before departure passengers can check in and board.
+	CheckInOpens time.Duration
+	// Notifier, when set, tells customers when their booking is cancelled or a timetable change affects their flight.
+	Notifier bookings.Notifier
+}
+
+// NewAdminHandlers returns a new AdminHandlers object, assigning passed dependencies. Check-in opens
+// bookings.DefaultCheckInOpens before departure.
+func NewAdminHandlers(booker bookings.Booker, payments bookings.PaymentGateway, refundPolicy bookings.RefundPolicy) AdminHandlers {
+	return AdminHandlers{Booker: booker, Payments: payments, RefundPolicy: refundPolicy, CheckInOpens: bookings.DefaultCheckInOpens}
+}
+
+// GetLaunchPads returns all launchpads, including retired ones.
+func (a *AdminHandlers) GetLaunchPads(w http.ResponseWriter, r *http.Request) {
+	launchPads, err := a.Booker.GetLaunchPads()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, launchPads)
+}
+
+// PostLaunchPad creates a launchpad.
+func (a *AdminHandlers) PostLaunchPad(w http.ResponseWriter, r *http.Request) {
+	var launchPad bookings.LaunchPad
+	if err := json.NewDecoder(r.Body).Decode(&launchPad); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+
+	if err := launchPad.Validate(); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	newLaunchPad, err := a.Booker.CreateLaunchPad(launchPad)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newLaunchPad)
+}
+
+// PutLaunchPad updates the fields of a launchpad that are present in the request.
+func (a *AdminHandlers) PutLaunchPad(w http.ResponseWriter, r *http.Request) {
+	var updated *bookings.LaunchPad
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		launchPad, err := tx.GetLaunchPad(r.PathValue("id"))
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(launchPad); err != nil {
+			return bookings.ValidationError{Reason: err.Error()}
+		}
+		launchPad.Id = r.PathValue("id")
+
+		if err := launchPad.Validate(); err != nil {
+			return err
+		}
+
+		updated, err = tx.UpdateLaunchPad(*launchPad)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteLaunchPad retires a launchpad so no more flights can be booked from it.
+func (a *AdminHandlers) DeleteLaunchPad(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.RetireLaunchPad(r.PathValue("id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Launchpad retired")
+}
+
+// GetDestinations returns all destinations, including retired ones.
+func (a *AdminHandlers) GetDestinations(w http.ResponseWriter, r *http.Request) {
+	destinations, err := a.Booker.GetDestinations()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, destinations)
+}
+
+// PostDestination creates a destination.
+func (a *AdminHandlers) PostDestination(w http.ResponseWriter, r *http.Request) {
+	var destination bookings.Destination
+	if err := json.NewDecoder(r.Body).Decode(&destination); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+
+	if err := destination.Validate(); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	newDestination, err := a.Booker.CreateDestination(destination)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newDestination)
+}
+
+// PutDestination updates the fields of a destination that are present in the request.
+func (a *AdminHandlers) PutDestination(w http.ResponseWriter, r *http.Request) {
+	var updated *bookings.Destination
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		destination, err := tx.GetDestination(r.PathValue("id"))
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(destination); err != nil {
+			return bookings.ValidationError{Reason: err.Error()}
+		}
+		destination.Id = r.PathValue("id")
+
+		if err := destination.Validate(); err != nil {
+			return err
+		}
+
+		updated, err = tx.UpdateDestination(*destination)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteDestination retires a destination so no more flights can be booked to it.
+func (a *AdminHandlers) DeleteDestination(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.RetireDestination(r.PathValue("id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Destination retired")
+}
+
+// GetSchedule returns the weekly schedule, optionally filtered by the launch_pad_id query parameter.
+func (a *AdminHandlers) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := a.Booker.GetSchedule(r.URL.Query().Get("launch_pad_id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// PostScheduleEntry adds a weekly flight to the schedule. Flights are outbound unless the request says otherwise.
+func (a *AdminHandlers) PostScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	entry := bookings.ScheduleEntry{Direction: bookings.DirectionOutbound}
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	entry.Id = ""
+
+	var created *bookings.ScheduleEntry
+	var flagged []bookings.Booking
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		if err := validateScheduleEntry(tx, entry); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = tx.CreateScheduleEntry(entry)
+		if err != nil {
+			return err
+		}
+
+		flagged, err = flagAffectedBookings(tx, entry.LaunchPadId)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyRescheduled, flagged...)
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// PutScheduleEntry updates the fields of a weekly flight that are present in the request.
+func (a *AdminHandlers) PutScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	var updated *bookings.ScheduleEntry
+	var flagged []bookings.Booking
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		entry, err := findScheduleEntry(tx, r.PathValue("id"))
+		if err != nil {
+			return err
+		}
+
+		// Lock the launchpad the flight is moving from, as well as the one it's moving to.
+		previousLaunchPadId := entry.LaunchPadId
+		if _, err := tx.GetLaunchPad(previousLaunchPadId); err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(entry); err != nil {
+			return bookings.ValidationError{Reason: err.Error()}
+		}
+		entry.Id = r.PathValue("id")
+
+		if err := validateScheduleEntry(tx, *entry); err != nil {
+			return err
+		}
+
+		updated, err = tx.UpdateScheduleEntry(*entry)
+		if err != nil {
+			return err
+		}
+
+		flagged, err = flagAffectedBookings(tx, previousLaunchPadId, entry.LaunchPadId)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyRescheduled, flagged...)
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteScheduleEntry removes a weekly flight from the schedule.
+func (a *AdminHandlers) DeleteScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	var rowsAffected int64
+	var flagged []bookings.Booking
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		entry, err := findScheduleEntry(tx, r.PathValue("id"))
+		if errors.Is(err, bookings.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.GetLaunchPad(entry.LaunchPadId); err != nil {
+			return err
+		}
+
+		rowsAffected, err = tx.DeleteScheduleEntry(entry.Id)
+		if err != nil {
+			return err
+		}
+
+		flagged, err = flagAffectedBookings(tx, entry.LaunchPadId)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyRescheduled, flagged...)
+
+	writeRetired(w, rowsAffected, "Schedule entry deleted")
+}
+
+// GetScheduleExceptions returns every schedule exception, or only those covering the date query parameter.
+func (a *AdminHandlers) GetScheduleExceptions(w http.ResponseWriter, r *http.Request) {
+	var date time.Time
+	if value := r.URL.Query().Get("date"); len(value) > 0 {
+		var err error
+		date, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			writeAdminError(w, bookings.ValidationError{Reason: "invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	exceptions, err := a.Booker.GetScheduleExceptions(date)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, exceptions)
+}
+
+// PostScheduleException adds an extra flight, cancellation, launchpad closure or unavailable destination. Exceptions
+// apply to outbound flights unless the request says otherwise.
+func (a *AdminHandlers) PostScheduleException(w http.ResponseWriter, r *http.Request) {
+	exception := bookings.ScheduleException{Direction: bookings.DirectionOutbound}
+	if err := json.NewDecoder(r.Body).Decode(&exception); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	exception.Id = ""
+
+	var created *bookings.ScheduleException
+	var flagged []bookings.Booking
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		if err := validateScheduleException(tx, exception); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = tx.CreateScheduleException(exception)
+		if err != nil {
+			return err
+		}
+
+		launchPadIds, err := exceptionLaunchPads(tx, exception)
+		if err != nil {
+			return err
+		}
+
+		flagged, err = flagAffectedBookings(tx, launchPadIds...)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyRescheduled, flagged...)
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// PutScheduleException updates the fields of a schedule exception that are present in the request.
+func (a *AdminHandlers) PutScheduleException(w http.ResponseWriter, r *http.Request) {
+	var updated *bookings.ScheduleException
+	var flagged []bookings.Booking
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		exception, err := tx.GetScheduleException(r.PathValue("id"))
+		if err != nil {
+			return err
+		}
+
+		// Lock the launchpad the exception is moving from, as well as the one it's moving to.
+		previous := *exception
+		if len(previous.LaunchPadId) > 0 {
+			if _, err := tx.GetLaunchPad(previous.LaunchPadId); err != nil {
+				return err
+			}
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(exception); err != nil {
+			return bookings.ValidationError{Reason: err.Error()}
+		}
+		exception.Id = r.PathValue("id")
+
+		if err := validateScheduleException(tx, *exception); err != nil {
+			return err
+		}
+
+		updated, err = tx.UpdateScheduleException(*exception)
+		if err != nil {
+			return err
+		}
+
+		launchPadIds, err := exceptionLaunchPads(tx, previous, *exception)
+		if err != nil {
+			return err
+		}
+
+		flagged, err = flagAffectedBookings(tx, launchPadIds...)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyRescheduled, flagged...)
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteScheduleException removes a schedule exception, so the weekly schedule applies again.
+func (a *AdminHandlers) DeleteScheduleException(w http.ResponseWriter, r *http.Request) {
+	var rowsAffected int64
+	var flagged []bookings.Booking
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		exception, err := tx.GetScheduleException(r.PathValue("id"))
+		if errors.Is(err, bookings.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(exception.LaunchPadId) > 0 {
+			if _, err := tx.GetLaunchPad(exception.LaunchPadId); err != nil {
+				return err
+			}
+		}
+
+		rowsAffected, err = tx.DeleteScheduleException(exception.Id)
+		if err != nil {
+			return err
+		}
+
+		launchPadIds, err := exceptionLaunchPads(tx, *exception)
+		if err != nil {
+			return err
+		}
+
+		flagged, err = flagAffectedBookings(tx, launchPadIds...)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyRescheduled, flagged...)
+
+	writeRetired(w, rowsAffected, "Schedule exception deleted")
+}
+
+// GetTravelTimes returns the travel time overrides, optionally filtered by the launch_pad_id query parameter.
+func (a *AdminHandlers) GetTravelTimes(w http.ResponseWriter, r *http.Request) {
+	travelTimes, err := a.Booker.GetTravelTimes(r.URL.Query().Get("launch_pad_id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, travelTimes)
+}
+
+// PutTravelTime sets how long flights between a launchpad and a destination take, overriding the destination's travel
+// time. Bookings that have already been made keep the arrival date they were given.
+func (a *AdminHandlers) PutTravelTime(w http.ResponseWriter, r *http.Request) {
+	travelTime := bookings.TravelTime{TravelDays: -1}
+	if err := json.NewDecoder(r.Body).Decode(&travelTime); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	travelTime.LaunchPadId = r.PathValue("launch_pad_id")
+	travelTime.DestinationId = r.PathValue("destination_id")
+
+	if travelTime.TravelDays < 0 {
+		writeAdminError(w, bookings.ValidationError{Reason: "travel_days is required and must not be negative"})
+		return
+	}
+
+	var updated *bookings.TravelTime
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		if _, err := tx.GetLaunchPad(travelTime.LaunchPadId); err != nil {
+			return err
+		}
+
+		if _, err := tx.GetDestination(travelTime.DestinationId); err != nil {
+			return err
+		}
+
+		if err := travelTime.Validate(); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = tx.SetTravelTime(travelTime)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteTravelTime removes a travel time override, so the destination's travel time applies again.
+func (a *AdminHandlers) DeleteTravelTime(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.DeleteTravelTime(r.PathValue("launch_pad_id"), r.PathValue("destination_id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Travel time deleted")
+}
+
+// GetFares returns every fare.
+func (a *AdminHandlers) GetFares(w http.ResponseWriter, r *http.Request) {
+	fares, err := a.Booker.GetFares()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, fares)
+}
+
+// PutFare sets the base price of seats on flights between a launchpad and a destination. Bookings that have already
+// been made keep the price they were given.
+func (a *AdminHandlers) PutFare(w http.ResponseWriter, r *http.Request) {
+	fare := bookings.Fare{BasePrice: -1}
+	if err := json.NewDecoder(r.Body).Decode(&fare); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	fare.LaunchPadId = r.PathValue("launch_pad_id")
+	fare.DestinationId = r.PathValue("destination_id")
+
+	if fare.BasePrice < 0 {
+		writeAdminError(w, bookings.ValidationError{Reason: "base_price is required and must not be negative"})
+		return
+	}
+
+	var updated *bookings.Fare
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		if _, err := tx.GetLaunchPad(fare.LaunchPadId); err != nil {
+			return err
+		}
+
+		if _, err := tx.GetDestination(fare.DestinationId); err != nil {
+			return err
+		}
+
+		if err := fare.Validate(); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = tx.SetFare(fare)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteFare removes the fare for flights between a launchpad and a destination, so they're booked without a price.
+func (a *AdminHandlers) DeleteFare(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.DeleteFare(r.PathValue("launch_pad_id"), r.PathValue("destination_id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Fare deleted")
+}
+
+// GetFareRules returns every fare rule.
+func (a *AdminHandlers) GetFareRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := a.Booker.GetFareRules()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// PostFareRule adds a day of the week or seasonal multiplier to fares.
+func (a *AdminHandlers) PostFareRule(w http.ResponseWriter, r *http.Request) {
+	var rule bookings.FareRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	rule.Id = ""
+
+	var created *bookings.FareRule
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		if len(rule.LaunchPadId) > 0 {
+			if _, err := tx.GetLaunchPad(rule.LaunchPadId); err != nil {
+				return err
+			}
+		}
+
+		if len(rule.DestinationId) > 0 {
+			if _, err := tx.GetDestination(rule.DestinationId); err != nil {
+				return err
+			}
+		}
+
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = tx.CreateFareRule(rule)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// DeleteFareRule removes a fare rule.
+func (a *AdminHandlers) DeleteFareRule(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.DeleteFareRule(r.PathValue("id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Fare rule deleted")
+}
+
+// GetPromoCodes returns every promo code, including ones that can't be used yet or have run out.
+func (a *AdminHandlers) GetPromoCodes(w http.ResponseWriter, r *http.Request) {
+	promoCodes, err := a.Booker.GetPromoCodes()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, promoCodes)
+}
+
+// PutPromoCode adds or replaces a promo code. Codes are stored in upper case, so customers can type them in any case.
+func (a *AdminHandlers) PutPromoCode(w http.ResponseWriter, r *http.Request) {
+	var promo bookings.PromoCode
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	promo.Code = bookings.NormalisePromoCode(r.PathValue("code"))
+
+	if err := promo.Validate(); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	updated, err := a.Booker.SetPromoCode(promo)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeletePromoCode removes a promo code. Bookings already made with it keep their price.
+func (a *AdminHandlers) DeletePromoCode(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.DeletePromoCode(bookings.NormalisePromoCode(r.PathValue("code")))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Promo code deleted")
+}
+
+// validateScheduleEntry locks the entry's launchpad, so bookings for it wait until the schedule change is committed,
+// then checks the entry against the launchpad's existing schedule, including entries that aren't in force yet.
+func validateScheduleEntry(tx bookings.Booker, entry bookings.ScheduleEntry) error {
+	if len(entry.LaunchPadId) == 0 || len(entry.DestinationId) == 0 {
+		return entry.Validate(nil)
+	}
+
+	if _, err := tx.GetLaunchPad(entry.LaunchPadId); errors.Is(err, bookings.ErrNotFound) {
+		return bookings.ValidationError{Reason: fmt.Sprintf("launch_pad_id %s not recognised", entry.LaunchPadId)}
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := tx.GetDestination(entry.DestinationId); errors.Is(err, bookings.ErrNotFound) {
+		return bookings.ValidationError{Reason: fmt.Sprintf("destination_id %s not recognised", entry.DestinationId)}
+	} else if err != nil {
+		return err
+	}
+
+	existing, err := tx.GetSchedule(entry.LaunchPadId)
+	if err != nil {
+		return err
+	}
+
+	return entry.Validate(existing)
+}
+
+// flagAffectedBookings flags the upcoming bookings from the launchpads whose flights can't go ahead any more, because
+// they've gone from the weekly schedule or an exception stops them, so they can be reviewed after a timetable change,
+// and returns them. It should be called in the transaction that changed the schedule.
+func flagAffectedBookings(tx bookings.Booker, launchPadIds ...string) ([]bookings.Booking, error) {
+	today := bookings.Today(time.Now().UTC())
+
+	var flagged []bookings.Booking
+	for i, launchPadId := range launchPadIds {
+		if slices.Contains(launchPadIds[:i], launchPadId) {
+			continue
+		}
+
+		upcoming, err := tx.GetUpcoming(launchPadId, today)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, booking := range upcoming {
+			if booking.ReviewRequired {
+				continue
+			}
+
+			_, err := checkScheduledFlight(tx, booking)
+			if err == nil {
+				continue
+			}
+
+			reason, ok := reviewReason(booking, err)
+			if !ok {
+				return nil, err
+			}
+
+			if _, err := tx.FlagForReview(booking.Id, reason); err != nil {
+				return nil, err
+			}
+			booking.ReviewRequired, booking.ReviewReason = true, reason
+			flagged = append(flagged, booking)
+		}
+	}
+
+	if len(flagged) > 0 {
+		log.Printf("Number of bookings flagged for review: %d\n", len(flagged))
+	}
+
+	return flagged, nil
+}
+
+// reviewReason returns why the booking is flagged for review when checkScheduledFlight says its flight can't go ahead
+// with err, or false if err isn't a reason to flag it, e.g. because something went wrong.
+func reviewReason(booking bookings.Booking, err error) (string, bool) {
+	date := booking.LaunchDate.Format(time.DateOnly)
+
+	switch {
+	case errors.Is(err, bookings.ErrLaunchScheduleInvalid):
+		return fmt.Sprintf("timetable changed, the launchpad no longer flies to the destination on %s", date), true
+	case errors.Is(err, bookings.ErrReturnScheduleInvalid):
+		return fmt.Sprintf("timetable changed, no return flight from the destination lands at the launchpad on %s", date), true
+	case errors.Is(err, bookings.ErrFlightCancelled):
+		return fmt.Sprintf("timetable changed, the flight has been cancelled on %s", date), true
+	case errors.Is(err, bookings.ErrLaunchPadClosed):
+		return fmt.Sprintf("timetable changed, the launchpad is closed on %s", date), true
+	case errors.Is(err, bookings.ErrDestinationUnavailable):
+		return fmt.Sprintf("timetable changed, the destination is unavailable on %s", date), true
+	default:
+		return "", false
+	}
+}
+
+// exceptionLaunchPads returns the launchpads whose bookings the exceptions can affect, which is every launchpad if one
+// of them only names a destination.
+func exceptionLaunchPads(tx bookings.Booker, exceptions ...bookings.ScheduleException) ([]string, error) {
+	var launchPadIds []string
+	for _, exception := range exceptions {
+		if len(exception.LaunchPadId) > 0 {
+			launchPadIds = append(launchPadIds, exception.LaunchPadId)
+			continue
+		}
+
+		launchPads, err := tx.GetLaunchPads()
+		if err != nil {
+			return nil, err
+		}
+
+		launchPadIds = launchPadIds[:0]
+		for _, launchPad := range launchPads {
+			launchPadIds = append(launchPadIds, launchPad.Id)
+		}
+
+		return launchPadIds, nil
+	}
+
+	return launchPadIds, nil
+}
+
+// validateScheduleException locks the exception's launchpad, if it has one, so bookings for it wait until the
+// exception is committed, then checks the launchpad and destination it names exist.
+func validateScheduleException(tx bookings.Booker, exception bookings.ScheduleException) error {
+	if err := exception.Validate(); err != nil {
+		return err
+	}
+
+	if len(exception.LaunchPadId) > 0 {
+		if _, err := tx.GetLaunchPad(exception.LaunchPadId); errors.Is(err, bookings.ErrNotFound) {
+			return bookings.ValidationError{Reason: fmt.Sprintf("launch_pad_id %s not recognised", exception.LaunchPadId)}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(exception.DestinationId) > 0 {
+		if _, err := tx.GetDestination(exception.DestinationId); errors.Is(err, bookings.ErrNotFound) {
+			return bookings.ValidationError{Reason: fmt.Sprintf("destination_id %s not recognised", exception.DestinationId)}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func findScheduleEntry(tx bookings.Booker, id string) (*bookings.ScheduleEntry, error) {
+	schedule, err := tx.GetSchedule("")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range schedule {
+		if entry.Id == id {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("schedule entry %s: %w", id, bookings.ErrNotFound)
+}
+
+// GetBookings returns all bookings with the customers' contact details, or only those with the status in the status
+// query parameter.
+func (a *AdminHandlers) GetBookings(w http.ResponseWriter, r *http.Request) {
+	all, err := a.Booker.GetAll()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bookingsWithStatus(all, r.URL.Query().Get("status")))
+}
+
+// CancelBooking cancels a booking for the reason in the request, refunding it according to the refund policy.
+// Bookings cancelled because of a SpaceX conflict are refunded in full.
+func (a *AdminHandlers) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+
+	if err := bookings.ValidateCancellationReason(request.Reason); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	cancelled, err := bookings.CancelBooking(a.Booker, a.Payments, a.RefundPolicy, r.PathValue("id"), request.Reason, time.Now().UTC())
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	notify(a.Booker, a.Notifier, bookings.NotifyCancelled, *cancelled)
+
+	writeJSON(w, http.StatusOK, cancelled)
+}
+
+// writeAdminError responds with a status code that matches the error.
+func writeAdminError(w http.ResponseWriter, err error) {
+	var validationErr bookings.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeStatus(w, http.StatusBadRequest, validationErr.Reason)
+	case errors.Is(err, bookings.ErrScheduleConflict), errors.Is(err, bookings.ErrInvalidTransition),
+		errors.Is(err, bookings.ErrCheckInNotOpen), errors.Is(err, bookings.ErrCheckInClosed),
+		errors.Is(err, bookings.ErrNotDeparted), errors.Is(err, bookings.ErrCancellationPending),
+		errors.Is(err, bookings.ErrPaymentPending):
+		writeStatus(w, http.StatusConflict, err.Error())
+	case errors.Is(err, bookings.ErrNotFound):
+		writeStatus(w, http.StatusNotFound, "ID not recognised")
+	default:
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+	}
+}
+
+func writeRetired(w http.ResponseWriter, rowsAffected int64, message string) {
+	log.Printf("Number of rows updated: %d\n", rowsAffected)
+
+	if rowsAffected == 0 {
+		writeStatus(w, http.StatusOK, "ID not recognised")
+		return
+	}
+
+	writeStatus(w, http.StatusOK, message)
+}
+
+func writeStatus(w http.ResponseWriter, statusCode int, status string) {
+	writeJSON(w, statusCode, map[string]string{"Status": status})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err)
+	}
+}
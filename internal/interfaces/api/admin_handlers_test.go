@@ -0,0 +1,1178 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/database"
+	"github.com/petherin/spacetickets/internal/infrastructure/payments"
+)
+
+const (
+	capeCanaveralId = "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975"
+	moonId          = "466fc378-14eb-4ed9-8bec-d29abe54c5a9"
+)
+
+// passenger is an adult every eligibility rule lets fly.
+var passenger = bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male",
+	Birthday: time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)}
+
+func newAdminMux(t *testing.T) (*http.ServeMux, *database.Memory) {
+	t.Helper()
+
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	admin := NewAdminHandlers(repo, payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/admin/launchpads", admin.PostLaunchPad)
+	mux.HandleFunc("PUT /api/v1/admin/launchpads/{id}", admin.PutLaunchPad)
+	mux.HandleFunc("DELETE /api/v1/admin/launchpads/{id}", admin.DeleteLaunchPad)
+	mux.HandleFunc("POST /api/v1/admin/destinations", admin.PostDestination)
+	mux.HandleFunc("GET /api/v1/admin/schedule", admin.GetSchedule)
+	mux.HandleFunc("POST /api/v1/admin/schedule", admin.PostScheduleEntry)
+	mux.HandleFunc("PUT /api/v1/admin/schedule/{id}", admin.PutScheduleEntry)
+	mux.HandleFunc("DELETE /api/v1/admin/schedule/{id}", admin.DeleteScheduleEntry)
+	mux.HandleFunc("GET /api/v1/admin/schedule-exceptions", admin.GetScheduleExceptions)
+	mux.HandleFunc("POST /api/v1/admin/schedule-exceptions", admin.PostScheduleException)
+	mux.HandleFunc("GET /api/v1/admin/travel-times", admin.GetTravelTimes)
+	mux.HandleFunc("PUT /api/v1/admin/travel-times/{launch_pad_id}/{destination_id}", admin.PutTravelTime)
+	mux.HandleFunc("DELETE /api/v1/admin/travel-times/{launch_pad_id}/{destination_id}", admin.DeleteTravelTime)
+	mux.HandleFunc("GET /api/v1/admin/fares", admin.GetFares)
+	mux.HandleFunc("PUT /api/v1/admin/fares/{launch_pad_id}/{destination_id}", admin.PutFare)
+	mux.HandleFunc("DELETE /api/v1/admin/fares/{launch_pad_id}/{destination_id}", admin.DeleteFare)
+	mux.HandleFunc("POST /api/v1/admin/fare-rules", admin.PostFareRule)
+	mux.HandleFunc("PUT /api/v1/admin/promo-codes/{code}", admin.PutPromoCode)
+	mux.HandleFunc("GET /api/v1/admin/eligibility-rules", admin.GetEligibilityRules)
+	mux.HandleFunc("POST /api/v1/admin/eligibility-rules", admin.PostEligibilityRule)
+	mux.HandleFunc("DELETE /api/v1/admin/eligibility-rules/{id}", admin.DeleteEligibilityRule)
+	mux.HandleFunc("POST /api/v1/admin/bookings/{id}/cancel", admin.CancelBooking)
+	mux.HandleFunc("POST /api/v1/admin/bookings/{id}/status", admin.PostBookingStatus)
+	mux.HandleFunc("GET /api/v1/flights/{launchpad_id}/{date}/manifest", admin.GetManifest)
+
+	return mux, repo
+}
+
+func TestAdmin_LaunchPads(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            *http.Request
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Successfully creates a launchpad",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/launchpads", strings.NewReader(`{"full_name": "Starbase Pad B", "spacex_launchpad_id": "5e9e4502f5090927f8566f99", "seat_capacity": 120, "timezone": "America/Chicago"}`)),
+			want:           `"full_name":"Starbase Pad B","spacex_launchpad_id":"5e9e4502f5090927f8566f99","seat_capacity":120,"timezone":"America/Chicago","active":true`,
+			wantStatusCode: 201,
+		},
+		{
+			name:           "2. Invalid SpaceX launchpad id, returns 400",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/launchpads", strings.NewReader(`{"full_name": "Starbase Pad B", "spacex_launchpad_id": "nope", "seat_capacity": 120}`)),
+			want:           `{"Status":"spacex_launchpad_id must be 24 lowercase hexadecimal characters"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "3. Updates only the fields sent",
+			req:            httptest.NewRequest(http.MethodPut, "/api/v1/admin/launchpads/"+capeCanaveralId, strings.NewReader(`{"full_name": "Cape Canaveral SLC-40"}`)),
+			want:           `"full_name":"Cape Canaveral SLC-40","spacex_launchpad_id":"5e9e4501f509094ba4566f84","seat_capacity":100,"timezone":"America/New_York","active":true`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "4. Updating unknown launchpad, returns 404",
+			req:            httptest.NewRequest(http.MethodPut, "/api/v1/admin/launchpads/unknown", strings.NewReader(`{"full_name": "Nowhere"}`)),
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 404,
+		},
+		{
+			name:           "5. Unrecognised timezone, returns 400",
+			req:            httptest.NewRequest(http.MethodPut, "/api/v1/admin/launchpads/"+capeCanaveralId, strings.NewReader(`{"timezone": "Florida"}`)),
+			want:           `{"Status":"unrecognised timezone \"Florida\""}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "6. Retires a launchpad",
+			req:            httptest.NewRequest(http.MethodDelete, "/api/v1/admin/launchpads/"+capeCanaveralId, nil),
+			want:           `{"Status":"Launchpad retired"}`,
+			wantStatusCode: 200,
+		},
+	}
+
+	mux, _ := newAdminMux(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, tt.req)
+
+			res := w.Result()
+			if status := res.StatusCode; status != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatusCode)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdmin_Schedule(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/destinations", strings.NewReader(`{"name": "Callisto"}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("destination not created: %d %s", w.Code, w.Body.String())
+	}
+	var callisto bookings.Destination
+	if err := json.Unmarshal(w.Body.Bytes(), &callisto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A booking on the Moon flight next Monday, which moving Monday's flight to Callisto should flag for review.
+	nextMonday := bookings.Today(time.Now().UTC())
+	for nextMonday.Weekday() != time.Monday {
+		nextMonday = nextMonday.AddDate(0, 0, 1)
+	}
+	booking, err := repo.Create(bookings.Booking{LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: nextMonday})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           func() string
+		body           string
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Adding a second destination on the same weekday is rejected",
+			method:         http.MethodPost,
+			path:           func() string { return "/api/v1/admin/schedule" },
+			body:           `{"launch_pad_id": "` + capeCanaveralId + `", "day_of_week": "Monday", "destination_id": "` + callisto.Id + `"}`,
+			want:           `{"Status":"launchpad already flies to a destination on that day of the week while this entry is in effect"}`,
+			wantStatusCode: 409,
+		},
+		{
+			name:           "2. Unknown day of the week is rejected",
+			method:         http.MethodPost,
+			path:           func() string { return "/api/v1/admin/schedule" },
+			body:           `{"launch_pad_id": "` + capeCanaveralId + `", "day_of_week": "Someday", "destination_id": "` + callisto.Id + `"}`,
+			want:           `{"Status":"unrecognised day_of_week \"Someday\""}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:   "3. Moving Monday's flight to Callisto",
+			method: http.MethodPut,
+			path: func() string {
+				schedule, _ := repo.GetSchedule(capeCanaveralId)
+				for _, entry := range schedule {
+					if entry.DayOfWeek == "Monday" {
+						return "/api/v1/admin/schedule/" + entry.Id
+					}
+				}
+				return ""
+			},
+			body:           `{"destination_id": "` + callisto.Id + `"}`,
+			want:           `"day_of_week":"Monday","destination_id":"` + callisto.Id + `"`,
+			wantStatusCode: 200,
+		},
+		{
+			name:   "4. Moving Tuesday's flight to Monday is rejected",
+			method: http.MethodPut,
+			path: func() string {
+				schedule, _ := repo.GetSchedule(capeCanaveralId)
+				for _, entry := range schedule {
+					if entry.DayOfWeek == "Tuesday" {
+						return "/api/v1/admin/schedule/" + entry.Id
+					}
+				}
+				return ""
+			},
+			body:           `{"day_of_week": "Monday"}`,
+			want:           `{"Status":"launchpad already flies to a destination on that day of the week while this entry is in effect"}`,
+			wantStatusCode: 409,
+		},
+		{
+			name:   "5. Ending Monday's flight to Callisto in 2030",
+			method: http.MethodPut,
+			path: func() string {
+				schedule, _ := repo.GetSchedule(capeCanaveralId)
+				for _, entry := range schedule {
+					if entry.DayOfWeek == "Monday" {
+						return "/api/v1/admin/schedule/" + entry.Id
+					}
+				}
+				return ""
+			},
+			body:           `{"effective_to": "2030-12-31"}`,
+			want:           `"day_of_week":"Monday","destination_id":"` + callisto.Id + `","direction":"outbound","effective_from":null,"effective_to":"2030-12-31T00:00:00Z"`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "6. Monday's flight goes back to the Moon from 2031",
+			method:         http.MethodPost,
+			path:           func() string { return "/api/v1/admin/schedule" },
+			body:           `{"launch_pad_id": "` + capeCanaveralId + `", "day_of_week": "Monday", "destination_id": "` + moonId + `", "effective_from": "2031-01-01"}`,
+			want:           `"effective_from":"2031-01-01T00:00:00Z","effective_to":null,"window_opens":"00:00","window_closes":"24:00"`,
+			wantStatusCode: 201,
+		},
+		{
+			name:           "7. Launch window that closes before it opens is rejected",
+			method:         http.MethodPost,
+			path:           func() string { return "/api/v1/admin/schedule" },
+			body:           `{"launch_pad_id": "` + capeCanaveralId + `", "day_of_week": "Monday", "destination_id": "` + moonId + `", "effective_from": "2040-01-01", "window_opens": "18:00", "window_closes": "09:00"}`,
+			want:           `{"Status":"window_closes must be after window_opens"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "8. Effective dates in the wrong order are rejected",
+			method:         http.MethodPost,
+			path:           func() string { return "/api/v1/admin/schedule" },
+			body:           `{"launch_pad_id": "` + capeCanaveralId + `", "day_of_week": "Monday", "destination_id": "` + moonId + `", "effective_from": "2040-01-01", "effective_to": "2039-01-01"}`,
+			want:           `{"Status":"effective_to must not be before effective_from"}`,
+			wantStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path(), strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+		})
+	}
+
+	valid, err := repo.IsLaunchScheduleValid(bookings.DirectionOutbound, capeCanaveralId, callisto.Id, nextMonday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !valid {
+		t.Errorf("Cape Canaveral should fly to Callisto on Mondays")
+	}
+
+	valid, _ = repo.IsLaunchScheduleValid(bookings.DirectionOutbound, capeCanaveralId, moonId, nextMonday)
+	if valid {
+		t.Errorf("Cape Canaveral should no longer fly to the Moon on Mondays")
+	}
+
+	monday2031, _ := time.Parse(time.DateOnly, "2031-01-06")
+	valid, _ = repo.IsLaunchScheduleValid(bookings.DirectionOutbound, capeCanaveralId, moonId, monday2031)
+	if !valid {
+		t.Errorf("Cape Canaveral should fly to the Moon on Mondays again from 2031")
+	}
+
+	flagged, err := repo.Get(booking.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !flagged.ReviewRequired || !strings.Contains(flagged.ReviewReason, "timetable changed") {
+		t.Errorf("booking on the old schedule should be flagged for review, got %+v", flagged)
+	}
+}
+func TestAdmin_ScheduleKeepsExtraFlights(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	// A booking on an extra flight to the Moon next Tuesday, which isn't in the weekly schedule.
+	nextTuesday := bookings.Today(time.Now().UTC())
+	for nextTuesday.Weekday() != time.Tuesday {
+		nextTuesday = nextTuesday.AddDate(0, 0, 1)
+	}
+	_, err := repo.CreateScheduleException(bookings.ScheduleException{Kind: bookings.ExceptionExtraFlight, LaunchPadId: capeCanaveralId,
+		DestinationId: moonId, Direction: bookings.DirectionOutbound, StartDate: nextTuesday, EndDate: nextTuesday})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	booking, err := repo.Create(bookings.Booking{LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: nextTuesday})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	schedule, _ := repo.GetSchedule(capeCanaveralId)
+	for _, entry := range schedule {
+		if entry.DayOfWeek != "Friday" || entry.Direction != bookings.DirectionOutbound {
+			continue
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/v1/admin/schedule/"+entry.Id, strings.NewReader(`{"window_opens": "09:00"}`)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("schedule entry not updated: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	got, err := repo.Get(booking.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ReviewRequired {
+		t.Errorf("booking on an extra flight shouldn't be flagged for review, got %+v", got)
+	}
+}
+
+func TestAdmin_ScheduleExceptions(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Closes Cape Canaveral for a week",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/schedule-exceptions",
+			body:           `{"kind": "launchpad_closed", "launch_pad_id": "` + capeCanaveralId + `", "start_date": "2024-01-01", "end_date": "2024-01-07", "reason": "Maintenance"}`,
+			want:           `"kind":"launchpad_closed","launch_pad_id":"` + capeCanaveralId + `","destination_id":"","direction":"outbound","start_date":"2024-01-01T00:00:00Z","end_date":"2024-01-07T00:00:00Z","reason":"Maintenance"`,
+			wantStatusCode: 201,
+		},
+		{
+			name:           "2. Adds an extra flight to the Moon, end date defaults to the start date",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/schedule-exceptions",
+			body:           `{"kind": "extra_flight", "launch_pad_id": "` + capeCanaveralId + `", "destination_id": "` + moonId + `", "start_date": "2024-01-09"}`,
+			want:           `"start_date":"2024-01-09T00:00:00Z","end_date":"2024-01-09T00:00:00Z"`,
+			wantStatusCode: 201,
+		},
+		{
+			name:           "3. Unknown kind is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/schedule-exceptions",
+			body:           `{"kind": "holiday", "launch_pad_id": "` + capeCanaveralId + `", "start_date": "2024-01-09"}`,
+			want:           `{"Status":"unrecognised kind \"holiday\""}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "4. End date before start date is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/schedule-exceptions",
+			body:           `{"kind": "destination_unavailable", "destination_id": "` + moonId + `", "start_date": "2024-01-09", "end_date": "2024-01-08"}`,
+			want:           `{"Status":"end_date must not be before start_date"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "5. Unknown direction is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/schedule-exceptions",
+			body:           `{"kind": "launchpad_closed", "launch_pad_id": "` + capeCanaveralId + `", "direction": "sideways", "start_date": "2024-01-09"}`,
+			want:           `{"Status":"direction must be outbound or return"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "6. Lists only the exceptions covering a date",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/schedule-exceptions?date=2024-01-09",
+			want:           `"kind":"extra_flight"`,
+			wantStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+		})
+	}
+
+	monday, _ := time.Parse(time.DateOnly, "2024-01-01")
+	tuesday, _ := time.Parse(time.DateOnly, "2024-01-09")
+
+	outbound := bookings.Booking{Customer: passenger, LaunchPadId: capeCanaveralId, DestinationId: moonId, Direction: bookings.DirectionOutbound}
+	returning := outbound
+	returning.Direction = bookings.DirectionReturn
+
+	outbound.LaunchDate, returning.LaunchDate = monday, monday
+	if _, err := createBooking(repo, outbound); !errors.Is(err, bookings.ErrLaunchPadClosed) {
+		t.Errorf("wrong error booking during the closure, got %v want %v", err, bookings.ErrLaunchPadClosed)
+	}
+
+	// Cape Canaveral has a weekly return flight from the Moon on Mondays, and only launches were stopped.
+	if _, err := createBooking(repo, returning); err != nil {
+		t.Errorf("return flight landing during the closure should be bookable, got %v", err)
+	}
+
+	outbound.LaunchDate, returning.LaunchDate = tuesday, tuesday
+	if _, err := createBooking(repo, outbound); err != nil {
+		t.Errorf("extra flight to the Moon should be bookable, got %v", err)
+	}
+
+	if _, err := createBooking(repo, returning); !errors.Is(err, bookings.ErrReturnScheduleInvalid) {
+		t.Errorf("wrong error booking a return flight on the extra flight's day, got %v want %v", err, bookings.ErrReturnScheduleInvalid)
+	}
+}
+
+func TestAdmin_ScheduleExceptions_FlagBookings(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notifier := &recordingNotifier{}
+	admin := NewAdminHandlers(repo, payments.NewFake(), bookings.DefaultRefundPolicy)
+	admin.Notifier = notifier
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/admin/schedule-exceptions", admin.PostScheduleException)
+	mux.HandleFunc("DELETE /api/v1/admin/schedule-exceptions/{id}", admin.DeleteScheduleException)
+
+	nextMonday := bookings.Today(time.Now().UTC())
+	for nextMonday.Weekday() != time.Monday {
+		nextMonday = nextMonday.AddDate(0, 0, 1)
+	}
+	nextTuesday := nextMonday.AddDate(0, 0, 1)
+
+	customer := passenger
+	customer.Email = "ian@example.com"
+
+	// Cape Canaveral flies to the Moon on Mondays, and to Mars on Tuesdays unless an extra flight goes to the Moon.
+	monday, err := repo.Create(bookings.Booking{Customer: customer, LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: nextMonday})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	extraFlight, err := repo.CreateScheduleException(bookings.ScheduleException{Kind: bookings.ExceptionExtraFlight, LaunchPadId: capeCanaveralId,
+		DestinationId: moonId, Direction: bookings.DirectionOutbound, StartDate: nextTuesday, EndDate: nextTuesday})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tuesday, err := repo.Create(bookings.Booking{Customer: customer, LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: nextTuesday})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		want       string
+		bookingId  string
+		wantReason string
+	}{
+		{
+			name:       "1. Cancelling Monday's flight flags its booking",
+			method:     http.MethodPost,
+			path:       "/api/v1/admin/schedule-exceptions",
+			body:       `{"kind": "cancelled_flight", "launch_pad_id": "` + capeCanaveralId + `", "start_date": "` + nextMonday.Format(time.DateOnly) + `"}`,
+			want:       `"kind":"cancelled_flight"`,
+			bookingId:  monday.Id,
+			wantReason: "timetable changed, the flight has been cancelled on " + nextMonday.Format(time.DateOnly),
+		},
+		{
+			name:       "2. Deleting Tuesday's extra flight flags its booking",
+			method:     http.MethodDelete,
+			path:       "/api/v1/admin/schedule-exceptions/" + extraFlight.Id,
+			want:       `{"Status":"Schedule exception deleted"}`,
+			bookingId:  tuesday.Id,
+			wantReason: "timetable changed, the launchpad no longer flies to the destination on " + nextTuesday.Format(time.DateOnly),
+		},
+		{
+			name:   "3. Deleting an unknown exception",
+			method: http.MethodDelete,
+			path:   "/api/v1/admin/schedule-exceptions/unknown",
+			want:   `{"Status":"ID not recognised"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier.sent = nil
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+
+			if len(tt.bookingId) == 0 {
+				if len(notifier.sent) != 0 {
+					t.Errorf("wrong number of notifications, got %d want 0", len(notifier.sent))
+				}
+				return
+			}
+
+			flagged, err := repo.Get(tt.bookingId)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !flagged.ReviewRequired || flagged.ReviewReason != tt.wantReason {
+				t.Errorf("booking should be flagged for review with reason %q, got %+v", tt.wantReason, flagged)
+			}
+
+			if len(notifier.sent) != 1 || notifier.sent[0].Kind != bookings.NotifyRescheduled || notifier.sent[0].BookingId != tt.bookingId {
+				t.Errorf("wrong notifications, got %+v", notifier.sent)
+			}
+		})
+	}
+}
+
+func TestAdmin_TravelTimes(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Overrides the travel time from Cape Canaveral to the Moon",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/travel-times/" + capeCanaveralId + "/" + moonId,
+			body:           `{"travel_days": 5}`,
+			want:           `"launch_pad_id":"` + capeCanaveralId + `","destination_id":"` + moonId + `","travel_days":5`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "2. Lists the launchpad's travel times",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/travel-times?launch_pad_id=" + capeCanaveralId,
+			want:           `"travel_days":5`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "3. Missing travel days is rejected",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/travel-times/" + capeCanaveralId + "/" + moonId,
+			body:           `{}`,
+			want:           `{"Status":"travel_days is required and must not be negative"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "4. Unknown launchpad returns 404",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/travel-times/unknown/" + moonId,
+			body:           `{"travel_days": 5}`,
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+		})
+	}
+
+	monday, _ := time.Parse(time.DateOnly, "2024-01-08")
+
+	booking, err := createBooking(repo, bookings.Booking{Customer: passenger, LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: monday, Direction: bookings.DirectionOutbound})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := monday.AddDate(0, 0, 5); booking.EstimatedArrival == nil || !booking.EstimatedArrival.Equal(want) {
+		t.Errorf("wrong estimated arrival, got %v want %v", booking.EstimatedArrival, want)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/travel-times/"+capeCanaveralId+"/"+moonId, nil))
+	if !strings.Contains(w.Body.String(), `{"Status":"Travel time deleted"}`) {
+		t.Errorf("handler returned unexpected body: got %v", w.Body.String())
+	}
+
+	travelDays, _ := repo.GetTravelDays(capeCanaveralId, moonId)
+	if travelDays != 3 {
+		t.Errorf("destination travel days not used after deleting override, got %d want %d", travelDays, 3)
+	}
+}
+
+func TestAdmin_Pricing(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Sets the fare from Cape Canaveral to the Moon",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/fares/" + capeCanaveralId + "/" + moonId,
+			body:           `{"base_price": 10000000, "currency": "USD"}`,
+			want:           `"launch_pad_id":"` + capeCanaveralId + `","destination_id":"` + moonId + `","base_price":10000000,"currency":"USD"`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "2. Lists the fares",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/fares",
+			want:           `"base_price":10000000`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "3. Unrecognised currency is rejected",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/fares/" + capeCanaveralId + "/" + moonId,
+			body:           `{"base_price": 10000000, "currency": "dollars"}`,
+			want:           `{"Status":"currency must be a three letter ISO 4217 code, e.g. USD"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "4. Adds a season",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/fare-rules",
+			body:           `{"kind": "season", "start_date": "2024-01-01", "end_date": "2024-01-31", "multiplier": 1.5, "reason": "new year"}`,
+			want:           `"kind":"season"`,
+			wantStatusCode: 201,
+		},
+		{
+			name:           "5. Season without dates is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/fare-rules",
+			body:           `{"kind": "season", "multiplier": 1.5}`,
+			want:           `{"Status":"start_date and end_date are required for a season"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "6. Adds a promo code, stored in upper case",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/promo-codes/summer20",
+			body:           `{"percent_off": 20, "valid_from": "2024-01-01", "valid_to": "2099-12-31"}`,
+			want:           `"code":"SUMMER20","percent_off":20`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "7. Promo code taking off more than everything is rejected",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/promo-codes/summer20",
+			body:           `{"percent_off": 120, "valid_from": "2024-01-01", "valid_to": "2099-12-31"}`,
+			want:           `{"Status":"percent_off must be between 1 and 100"}`,
+			wantStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+		})
+	}
+
+	monday, _ := time.Parse(time.DateOnly, "2024-01-08")
+	birthday, _ := time.Parse(time.DateOnly, "2020-01-01")
+
+	// The new year season, the child discount and the promo code all apply.
+	booking, err := createBooking(repo, bookings.Booking{
+		Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: birthday},
+		LaunchPadId:   capeCanaveralId,
+		DestinationId: moonId,
+		LaunchDate:    monday,
+		Direction:     bookings.DirectionOutbound,
+		PromoCode:     "summer20",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.Price == nil || *booking.Price != 6000000 || booking.Currency != "USD" || booking.PromoCode != "SUMMER20" {
+		t.Errorf("wrong price, got %v %s with promo code %s", booking.Price, booking.Currency, booking.PromoCode)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/fares/"+capeCanaveralId+"/"+moonId, nil))
+	if !strings.Contains(w.Body.String(), `{"Status":"Fare deleted"}`) {
+		t.Errorf("handler returned unexpected body: got %v", w.Body.String())
+	}
+
+	booking, err = createBooking(repo, bookings.Booking{Customer: passenger, LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: monday, Direction: bookings.DirectionOutbound})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.Price != nil || len(booking.Currency) > 0 {
+		t.Errorf("flight without a fare was priced, got %v %s", booking.Price, booking.Currency)
+	}
+
+	if _, err := createBooking(repo, bookings.Booking{Customer: passenger, LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: monday, Direction: bookings.DirectionOutbound, PromoCode: "unknown"}); !errors.Is(err, bookings.ErrPromoCodeInvalid) {
+		t.Errorf("wrong error, got %v want %v", err, bookings.ErrPromoCodeInvalid)
+	}
+}
+
+func TestAdmin_Eligibility(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Lists the seeded rules",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/eligibility-rules",
+			want:           `"kind":"min_age","destination_id":"","value":2`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "2. Adds a limit of one trip a year",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/eligibility-rules",
+			body:           `{"kind": "max_trips_per_year", "value": 1, "reason": "seats are limited"}`,
+			want:           `"kind":"max_trips_per_year","destination_id":"","value":1,"reason":"seats are limited"`,
+			wantStatusCode: 201,
+		},
+		{
+			name:           "3. Unrecognised kind is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/eligibility-rules",
+			body:           `{"kind": "height", "value": 150}`,
+			want:           `{"Status":"unrecognised kind \"height\", use min_age, max_age or max_trips_per_year"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "4. Zero value is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/eligibility-rules",
+			body:           `{"kind": "min_age", "value": 0}`,
+			want:           `{"Status":"value must be greater than 0"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "5. Unknown destination is rejected",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/eligibility-rules",
+			body:           `{"kind": "min_age", "destination_id": "nope", "value": 18}`,
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 404,
+		},
+		{
+			name:           "6. Unknown rule isn't deleted",
+			method:         http.MethodDelete,
+			path:           "/api/v1/admin/eligibility-rules/nope",
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+		})
+	}
+
+	monday, _ := time.Parse(time.DateOnly, "2024-01-08")
+	newborn, _ := time.Parse(time.DateOnly, "2023-12-25")
+	flight := bookings.Booking{LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: monday, Direction: bookings.DirectionOutbound}
+
+	baby := flight
+	baby.Customer = bookings.Customer{FirstName: "Ada", LastName: "Thomson", Gender: "Female", Birthday: newborn}
+	_, err := createBooking(repo, baby)
+
+	var ineligible bookings.EligibilityError
+	if !errors.As(err, &ineligible) || ineligible.Code != bookings.IneligibleTooYoung {
+		t.Fatalf("wrong error booking a newborn, got %v want %s", err, bookings.IneligibleTooYoung)
+	}
+
+	w := httptest.NewRecorder()
+	writeBookingResult(w, nil, err)
+	if want := `{"Status":"Passenger not eligible, passengers must be at least 2 on the launch date (infants are too young for launch)","reason_code":"too_young"}`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), want)
+	}
+
+	adult := flight
+	adult.Customer = passenger
+	if _, err := createBooking(repo, adult); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The passenger's name is matched ignoring case.
+	adult.Customer.FirstName = "IAN"
+	adult.LaunchDate = monday.AddDate(0, 0, 7)
+	if _, err := createBooking(repo, adult); !errors.As(err, &ineligible) || ineligible.Code != bookings.IneligibleTooManyTrips {
+		t.Fatalf("wrong error booking a second trip, got %v want %s", err, bookings.IneligibleTooManyTrips)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/eligibility-rules/"+ineligible.Rule.Id, nil))
+	if !strings.Contains(w.Body.String(), `{"Status":"Eligibility rule deleted"}`) {
+		t.Errorf("handler returned unexpected body: got %v", w.Body.String())
+	}
+
+	if _, err := createBooking(repo, adult); err != nil {
+		t.Errorf("second trip should be bookable once the rule is deleted, got %v", err)
+	}
+}
+
+func TestAdmin_CancelBooking(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	// Paid for a flight leaving in an hour, too late for a refund unless SpaceX caused the cancellation.
+	departure := time.Now().UTC().Add(time.Hour)
+	price := int64(25000000)
+	booking, err := repo.Create(bookings.Booking{
+		Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+		LaunchPadId:   capeCanaveralId,
+		DestinationId: moonId,
+		LaunchDate:    bookings.Today(departure),
+		DepartureAt:   &departure,
+		Price:         &price,
+		Currency:      "USD",
+		Status:        bookings.StatusConfirmed,
+		PaymentId:     "pay-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Unrecognised reason, returns 400",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/bookings/"+booking.Id+"/cancel", strings.NewReader(`{"reason": "weather"}`)),
+			want:           `{"Status":"unrecognised reason \"weather\", use customer or spacex_conflict"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "2. SpaceX conflict, refunded in full",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/bookings/"+booking.Id+"/cancel", strings.NewReader(`{"reason": "spacex_conflict"}`)),
+			want:           `"status":"cancelled","payment_id":"pay-1","cancellation_reason":"spacex_conflict","refund_amount":25000000`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "3. Already cancelled, returns 404",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/bookings/"+booking.Id+"/cancel", strings.NewReader(`{"reason": "spacex_conflict"}`)),
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 404,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, tt.req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAdmin_BookingStatus(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	create := func(departure time.Time, status string) *bookings.Booking {
+		booking, err := repo.Create(bookings.Booking{
+			Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+			LaunchPadId:   capeCanaveralId,
+			DestinationId: moonId,
+			LaunchDate:    bookings.Today(departure),
+			DepartureAt:   &departure,
+			Status:        status,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return booking
+	}
+
+	// One passenger checked in for a flight that left an hour ago but didn't board, the other's flight is tomorrow.
+	departed := create(time.Now().UTC().Add(-time.Hour), bookings.StatusCheckedIn)
+	tomorrow := create(time.Now().UTC().Add(24*time.Hour), bookings.StatusBoarded)
+
+	statusURL := func(booking *bookings.Booking) string {
+		return "/api/v1/admin/bookings/" + booking.Id + "/status"
+	}
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Unrecognised status, returns 400",
+			req:            httptest.NewRequest(http.MethodPost, statusURL(departed), strings.NewReader(`{"status": "lost"}`)),
+			want:           `{"Status":"unrecognised status \"lost\", use checked_in, boarded, flown or no_show"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "2. Flown without boarding, returns 409",
+			req:            httptest.NewRequest(http.MethodPost, statusURL(departed), strings.NewReader(`{"status": "flown"}`)),
+			want:           `it can't be flown: booking cannot move to the requested status"}`,
+			wantStatusCode: 409,
+		},
+		{
+			name:           "3. Boarded after departure, returns 409",
+			req:            httptest.NewRequest(http.MethodPost, statusURL(departed), strings.NewReader(`{"status": "boarded"}`)),
+			want:           `check-in has closed, the flight has departed"}`,
+			wantStatusCode: 409,
+		},
+		{
+			name:           "4. No-show",
+			req:            httptest.NewRequest(http.MethodPost, statusURL(departed), strings.NewReader(`{"status": "no_show"}`)),
+			want:           `"status":"no_show"`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "5. No-shows can't be cancelled, returns 409",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/bookings/"+departed.Id+"/cancel", strings.NewReader(`{"reason": "customer"}`)),
+			want:           `it can't be cancelled: booking cannot move to the requested status"}`,
+			wantStatusCode: 409,
+		},
+		{
+			name:           "6. Flown before departure, returns 409",
+			req:            httptest.NewRequest(http.MethodPost, statusURL(tomorrow), strings.NewReader(`{"status": "flown"}`)),
+			want:           `flight has not departed yet"}`,
+			wantStatusCode: 409,
+		},
+		{
+			name:           "7. Unknown booking, returns 404",
+			req:            httptest.NewRequest(http.MethodPost, "/api/v1/admin/bookings/nope/status", strings.NewReader(`{"status": "flown"}`)),
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 404,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, tt.req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAdmin_Manifest(t *testing.T) {
+	mux, repo := newAdminMux(t)
+
+	// Cape Canaveral flies to the Moon on Mondays.
+	launchDate := time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC)
+	for _, customer := range []bookings.Customer{
+		{FirstName: "Ian", LastName: "Thomson", Gender: "Male", Birthday: time.Date(1980, 4, 12, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Ada", LastName: "Byron", Gender: "Female", Birthday: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC)},
+	} {
+		_, err := repo.Create(bookings.Booking{
+			Customer:      customer,
+			LaunchPadId:   capeCanaveralId,
+			DestinationId: moonId,
+			LaunchDate:    launchDate,
+			Direction:     bookings.DirectionOutbound,
+			Status:        bookings.StatusConfirmed,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	manifestURL := "/api/v1/flights/" + capeCanaveralId + "/2030-01-14/manifest"
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		want           string
+		wantStatusCode int
+		wantHeader     string
+	}{
+		{
+			name:           "1. JSON by default, sorted by name",
+			req:            httptest.NewRequest(http.MethodGet, manifestURL, nil),
+			want:           `"first_name":"Ada","last_name":"Byron","gender":"Female","age_at_launch":39,"destination_id":"` + moonId + `","destination":"Moon","status":"confirmed"}`,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "2. CSV download",
+			req:            httptest.NewRequest(http.MethodGet, manifestURL+"?format=csv", nil),
+			want:           "booking_id,last_name,first_name,gender,age_at_launch,destination_id,destination,status\n",
+			wantStatusCode: 200,
+			wantHeader:     `attachment; filename="manifest-` + capeCanaveralId + `-2030-01-14.csv"`,
+		},
+		{
+			name:           "3. PDF download",
+			req:            httptest.NewRequest(http.MethodGet, manifestURL+"?format=pdf", nil),
+			want:           "%PDF-",
+			wantStatusCode: 200,
+			wantHeader:     `attachment; filename="manifest-` + capeCanaveralId + `-2030-01-14.pdf"`,
+		},
+		{
+			name:           "4. Unrecognised format, returns 400",
+			req:            httptest.NewRequest(http.MethodGet, manifestURL+"?format=xls", nil),
+			want:           `{"Status":"format must be json, csv or pdf"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "5. Invalid date, returns 400",
+			req:            httptest.NewRequest(http.MethodGet, "/api/v1/flights/"+capeCanaveralId+"/14-01-2030/manifest", nil),
+			want:           `{"Status":"invalid date format. Use YYYY-MM-DD"}`,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "6. Unknown launchpad, returns 404",
+			req:            httptest.NewRequest(http.MethodGet, "/api/v1/flights/nope/2030-01-14/manifest", nil),
+			want:           `{"Status":"ID not recognised"}`,
+			wantStatusCode: 404,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, tt.req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+
+			if got := w.Header().Get("Content-Disposition"); got != tt.wantHeader {
+				t.Errorf("handler returned wrong Content-Disposition: got %q want %q", got, tt.wantHeader)
+			}
+		})
+	}
+
+	// The CSV lists a row for each passenger, Byron before Thomson.
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, manifestURL+"?format=csv", nil))
+	rows := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(rows) != 3 || !strings.Contains(rows[1], ",Byron,Ada,Female,39,") || !strings.Contains(rows[2], ",Thomson,Ian,Male,49,") {
+		t.Errorf("wrong CSV rows, got %q", rows)
+	}
+}
+
+// recordingNotifier keeps the notifications it's asked to send.
+type recordingNotifier struct {
+	sent []bookings.Notification
+}
+
+func (n *recordingNotifier) Notify(notification bookings.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestAdmin_CancelBooking_Notifies(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notifier := &recordingNotifier{}
+	admin := NewAdminHandlers(repo, payments.NewFake(), bookings.DefaultRefundPolicy)
+	admin.Notifier = notifier
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/admin/bookings/{id}/cancel", admin.CancelBooking)
+
+	customer := passenger
+	customer.Email = "ian@example.com"
+	launchDate := bookings.Today(time.Now().UTC().AddDate(0, 1, 0))
+	booking, err := repo.Create(bookings.Booking{
+		Customer:      customer,
+		LaunchPadId:   capeCanaveralId,
+		DestinationId: moonId,
+		LaunchDate:    launchDate,
+		Status:        bookings.StatusConfirmed,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/bookings/"+booking.Id+"/cancel",
+		strings.NewReader(`{"reason": "spacex_conflict"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", w.Code, http.StatusOK)
+	}
+
+	if len(notifier.sent) != 1 {
+		t.Fatalf("wrong number of notifications, got %d want 1", len(notifier.sent))
+	}
+
+	got := notifier.sent[0]
+	if got.Kind != bookings.NotifyCancelled || got.To != "ian@example.com" || got.BookingId != booking.Id {
+		t.Errorf("wrong notification, got %+v", got)
+	}
+
+	for _, want := range []string{"Cape Canaveral", "Moon"} {
+		if !strings.Contains(got.Body, want) {
+			t.Errorf("body missing %q, got %s", want, got.Body)
+		}
+	}
+}
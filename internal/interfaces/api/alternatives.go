@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+const (
+	// maxAlternativeChecks is the most flights checked against SpaceX when suggesting alternatives to a booking.
+	maxAlternativeChecks = 10
+	// alternativesTimeout is how long SpaceX has to check the alternatives, so the response is written well within the
+	// server's write timeout.
+	alternativesTimeout = 2 * time.Second
+)
+
+// rejectionResponse is why a booking couldn't be made, and the flights the customer could book instead.
+type rejectionResponse struct {
+	Status       string                 `json:"Status"`
+	Alternatives []bookings.Alternative `json:"alternatives"`
+}
+
+// suggestsAlternatives returns true if the booking was rejected because its launchpad doesn't fly to the destination
+// that day, or because of a SpaceX launch, so other flights are suggested.
+func suggestsAlternatives(err error) bool {
+	return errors.Is(err, bookings.ErrLaunchScheduleInvalid) || errors.Is(err, bookings.ErrSpaceXConflict)
+}
+
+// writeAlternatives writes why the booking was rejected, with the flights the customer could book instead. If the
+// alternatives can't be found the error is logged and the rejection is written without them.
+func (b *BookingHandlers) writeAlternatives(ctx context.Context, w http.ResponseWriter, booking bookings.Booking, rejection error) {
+	status, _ := bookingStatus(rejection)
+
+	ctx, cancel := context.WithTimeout(ctx, alternativesTimeout)
+	defer cancel()
+
+	alternatives, err := b.findAlternatives(ctx, booking)
+	if err != nil {
+		log.Println(err)
+		alternatives = []bookings.Alternative{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(rejectionResponse{Status: status, Alternatives: alternatives})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+}
+
+// findAlternatives returns the flights that could be booked instead of the booking's: up to MaxAlternativeDates of the
+// nearest dates its launchpad flies to the destination, then the other launchpads flying there on the requested day.
+// Each one is checked against the schedule, its exceptions, seats and SpaceX the same way a booking is. Dates that have
+// already passed at the launchpad aren't suggested.
+//
+// The schedule and seats are checked first, then at most maxAlternativeChecks of the flights left are checked against
+// SpaceX at the same time. Flights SpaceX can't be checked for before the context is done aren't suggested.
+func (b *BookingHandlers) findAlternatives(ctx context.Context, booking bookings.Booking) ([]bookings.Alternative, error) {
+	now := time.Now()
+
+	launchPad, err := b.Booker.GetLaunchPad(booking.LaunchPadId)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := launchPad.LocalDate(now)
+	if err != nil {
+		return nil, err
+	}
+
+	// More dates than are suggested are checked against SpaceX, in case some of them clash.
+	var dates []alternativeCheck
+	for _, date := range bookings.NearbyDates(booking.LaunchDate, today, bookings.AlternativeSearchDays) {
+		if len(dates) == 2*bookings.MaxAlternativeDates {
+			break
+		}
+
+		candidate := booking
+		candidate.LaunchDate = date
+
+		check, err := prepareAlternative(b.Booker, *launchPad, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if check != nil {
+			dates = append(dates, *check)
+		}
+	}
+
+	launchPads, err := b.Booker.GetLaunchPads()
+	if err != nil {
+		return nil, err
+	}
+
+	var others []alternativeCheck
+	for _, other := range launchPads {
+		if len(dates)+len(others) == maxAlternativeChecks {
+			break
+		}
+		if other.Id == booking.LaunchPadId {
+			continue
+		}
+
+		today, err := other.LocalDate(now)
+		if err != nil {
+			return nil, err
+		}
+		if booking.LaunchDate.Before(today) {
+			continue
+		}
+
+		candidate := booking
+		candidate.LaunchPadId = other.Id
+
+		check, err := prepareAlternative(b.Booker, other, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if check != nil {
+			others = append(others, *check)
+		}
+	}
+
+	checks := append(dates, others...)
+	b.checkAlternatives(ctx, checks)
+
+	alternatives := []bookings.Alternative{}
+	for i, check := range checks {
+		if i < len(dates) && len(alternatives) == bookings.MaxAlternativeDates {
+			continue
+		}
+		if check.available {
+			alternatives = append(alternatives, check.alternative)
+		}
+	}
+
+	return alternatives, nil
+}
+
+// alternativeCheck is a flight that could be suggested instead of a rejected booking, once it's been checked against
+// SpaceX.
+type alternativeCheck struct {
+	booking     bookings.Booking
+	alternative bookings.Alternative
+	available   bool
+}
+
+// prepareAlternative returns the flight for the candidate booking on the launchpad to check against SpaceX, or nil if
+// the schedule, its exceptions or the seats left rule it out. It doesn't run in a transaction, as nothing is booked.
+func prepareAlternative(tx bookings.Booker, launchPad bookings.LaunchPad, candidate bookings.Booking) (*alternativeCheck, error) {
+	prepared, err := prepareFlight(tx, candidate, 1)
+	if _, rejected := bookingStatus(err); rejected {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &alternativeCheck{booking: prepared, alternative: bookings.NewAlternative(launchPad, prepared)}, nil
+}
+
+// checkAlternatives checks the flights against SpaceX at the same time, marking the ones without a clash as available.
+// It returns when they've all been checked or the context is done, and errors are logged so the other flights can
+// still be suggested.
+func (b *BookingHandlers) checkAlternatives(ctx context.Context, checks []alternativeCheck) {
+	type result struct {
+		index     int
+		available bool
+	}
+
+	// The channel has room for every result, so checks still running after the context is done don't block.
+	results := make(chan result, len(checks))
+	for i, check := range checks {
+		go func() {
+			err := b.checkLaunchPad(check.booking)
+			if _, rejected := bookingStatus(err); !rejected && err != nil {
+				log.Printf("checking alternative on %s: %v\n", check.booking.LaunchDate.Format(time.DateOnly), err)
+			}
+			results <- result{index: i, available: err == nil}
+		}()
+	}
+
+	for range checks {
+		select {
+		case r := <-results:
+			checks[r.index].available = r.available
+		case <-ctx.Done():
+			log.Println("not all alternatives were checked in time")
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// boardingPassScale is the width in pixels of each module of a boarding pass's QR code, and boardingPassQuietZone is the
+// width in modules of the light border a scanner needs around it.
+const (
+	boardingPassScale     = 8
+	boardingPassQuietZone = 4
+)
+
+// boardingResponse is a booking whose boarding pass has been scanned at the gate.
+type boardingResponse struct {
+	Status  string           `json:"Status"`
+	Booking bookings.Booking `json:"booking"`
+}
+
+// GetBoardingPass returns the boarding pass for a confirmed or checked in booking, a QR code holding its signed token.
+// It's a PNG of the QR code, or a printable PDF with the passenger and flight when the format query parameter is pdf.
+// Only the customer who made the booking can get it, with the booking's access token.
+func (b *BookingHandlers) GetBoardingPass(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		format = "png"
+	}
+	if format != "png" && format != "pdf" {
+		writeStatus(w, http.StatusBadRequest, "format must be png or pdf")
+		return
+	}
+
+	if len(b.BoardingPassKey) == 0 {
+		writeStatus(w, http.StatusServiceUnavailable, "Boarding passes are not available")
+		return
+	}
+
+	booking, err := b.getOwnBooking(r)
+	if errors.Is(err, bookings.ErrNotFound) {
+		writeStatus(w, http.StatusNotFound, "ID not recognised")
+		return
+	}
+	if errors.Is(err, bookings.ErrAccessTokenInvalid) {
+		writeAccessTokenInvalid(w)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	pass, err := bookings.NewBoardingPass(*booking)
+	if errors.Is(err, bookings.ErrNotBoardable) {
+		writeStatus(w, http.StatusConflict, "Boarding passes are only issued for confirmed and checked in bookings")
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	code, err := boardingPassImage(pass.Sign(b.BoardingPassKey))
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	if format == "png" {
+		w.Header().Set("Content-Type", "image/png")
+		if err := png.Encode(w, code); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	launchPad, err := b.Booker.GetLaunchPad(booking.LaunchPadId)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	destination, err := b.Booker.GetDestination(booking.DestinationId)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="boarding-pass-`+booking.Id+`.pdf"`)
+	if err := writeBoardingPassPDF(w, *booking, *launchPad, *destination, code); err != nil {
+		log.Println(err)
+	}
+}
+
+// boardingPassImage returns a QR code holding a boarding pass's signed token, at error correction level M so it still
+// scans with around 15% of it damaged, with its quiet zone.
+func boardingPassImage(token string) (image.Image, error) {
+	code, err := qr.Encode(token, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	size := code.Bounds().Dx() * boardingPassScale
+	scaled, err := barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, err
+	}
+
+	border := boardingPassQuietZone * boardingPassScale
+	img := image.NewGray(image.Rect(0, 0, size+2*border, size+2*border))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, scaled.Bounds().Add(image.Pt(border, border)), scaled, image.Point{}, draw.Src)
+
+	return img, nil
+}
+
+// GetBoardingPassKey returns the public key boarding passes are signed with, so gate devices can check passes while
+// they're offline.
+func (b *BookingHandlers) GetBoardingPassKey(w http.ResponseWriter, r *http.Request) {
+	if len(b.BoardingPassKey) == 0 {
+		writeStatus(w, http.StatusServiceUnavailable, "Boarding passes are not available")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"algorithm":  "Ed25519",
+		"public_key": base64.StdEncoding.EncodeToString(b.BoardingPassKey.Public().(ed25519.PublicKey)),
+	})
+}
+
+// PostBoardingVerify checks the boarding pass token scanned at the gate and records the passenger as boarded, if they've
+// checked in and the flight hasn't departed. Scanning a pass again succeeds with the time the passenger first boarded,
+// so gate devices can retry scans they queued while offline.
+func (b *BookingHandlers) PostBoardingVerify(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeStatus(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(b.BoardingPassKey) == 0 {
+		writeStatus(w, http.StatusServiceUnavailable, "Boarding passes are not available")
+		return
+	}
+
+	booking, boarded, err := bookings.Board(b.Booker, b.BoardingPassKey.Public().(ed25519.PublicKey), request.Token, b.CheckInOpens,
+		time.Now().UTC())
+	switch {
+	case errors.Is(err, bookings.ErrBoardingPassInvalid):
+		writeStatus(w, http.StatusBadRequest, "Boarding pass is not valid")
+	case errors.Is(err, bookings.ErrNotFound):
+		writeStatus(w, http.StatusNotFound, "ID not recognised")
+	case errors.Is(err, bookings.ErrBoardingPassOutdated):
+		writeStatus(w, http.StatusConflict, "Boarding pass is out of date, the booking has changed since it was issued")
+	case errors.Is(err, bookings.ErrNotCheckedIn):
+		writeStatus(w, http.StatusConflict, "Passenger has not checked in")
+	case errors.Is(err, bookings.ErrNotBoardable):
+		writeStatus(w, http.StatusConflict, "Booking is not checked in, the passenger cannot board")
+	case errors.Is(err, bookings.ErrCheckInNotOpen):
+		writeStatus(w, http.StatusConflict, "Boarding has not opened for this flight")
+	case errors.Is(err, bookings.ErrCheckInClosed):
+		writeStatus(w, http.StatusConflict, "Boarding has closed, the flight has departed")
+	case err != nil:
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+	case boarded:
+		writeJSON(w, http.StatusOK, boardingResponse{Status: "Boarded", Booking: *booking})
+	default:
+		writeJSON(w, http.StatusOK, boardingResponse{Status: "Already boarded", Booking: *booking})
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"time"
+
+	"codeberg.org/go-pdf/fpdf"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// The layout of a boarding pass PDF, in points. Pages are A5 portrait.
+const (
+	boardingPassWidth  = 420
+	boardingPassHeight = 595
+	boardingPassQRSize = 240
+)
+
+// writeBoardingPassPDF writes a printable boarding pass with the passenger, their flight and the QR code to scan at the
+// gate. Times are in the launchpad's timezone.
+func writeBoardingPassPDF(w io.Writer, booking bookings.Booking, launchPad bookings.LaunchPad, destination bookings.Destination,
+	code image.Image) error {
+	departs := "See launch window"
+	if booking.DepartureAt != nil {
+		departs = booking.DepartureAt.UTC().Format("15:04 MST")
+		if location, err := time.LoadLocation(launchPad.Timezone); err == nil {
+			departs = booking.DepartureAt.In(location).Format("15:04 MST")
+		}
+	}
+
+	var qrPNG bytes.Buffer
+	if err := png.Encode(&qrPNG, code); err != nil {
+		return err
+	}
+
+	pdf := fpdf.New("P", "pt", "A5", "")
+	pdf.SetProducer("spacetickets", false)
+	// The built-in Helvetica fonts are in Windows-1252, so names are translated from UTF-8.
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", pdfTitleSize)
+	pdf.Text(pdfMargin, pdfMargin+pdfTitleSize, "Space Tickets Boarding Pass")
+
+	fields := [][2]string{
+		{"Passenger", booking.FirstName + " " + booking.LastName},
+		{"From", launchPad.FullName},
+		{"To", destination.Name},
+		{"Launch date", booking.LaunchDate.Format("Monday 2 January 2006")},
+		{"Departs", departs},
+		{"Booking", booking.Id},
+	}
+
+	y := float64(pdfMargin + pdfTitleSize + 2*pdfTitleSpacing)
+	for _, field := range fields {
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+		pdf.Text(pdfMargin, y, field[0])
+		pdf.SetFont("Helvetica", "B", pdfFontSize+2)
+		pdf.Text(pdfMargin+80, y, translate(field[1]))
+		y += pdfTitleSpacing
+	}
+
+	// The QR code already has its quiet zone, so it's drawn at the bottom of the page inside the margin.
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("qr", options, &qrPNG)
+	pdf.ImageOptions("qr", (boardingPassWidth-boardingPassQRSize)/2, boardingPassHeight-pdfMargin-boardingPassQRSize,
+		boardingPassQRSize, boardingPassQRSize, false, options, 0, "")
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetEligibilityRules returns every eligibility rule.
+func (a *AdminHandlers) GetEligibilityRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := a.Booker.GetEligibilityRules()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// PostEligibilityRule adds an age or trips a year limit on who can fly, for every destination or just one.
+func (a *AdminHandlers) PostEligibilityRule(w http.ResponseWriter, r *http.Request) {
+	var rule bookings.EligibilityRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+	rule.Id = ""
+
+	var created *bookings.EligibilityRule
+	err := a.Booker.InTransaction(func(tx bookings.Booker) error {
+		if len(rule.DestinationId) > 0 {
+			if _, err := tx.GetDestination(rule.DestinationId); err != nil {
+				return err
+			}
+		}
+
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = tx.CreateEligibilityRule(rule)
+		return err
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// DeleteEligibilityRule removes an eligibility rule.
+func (a *AdminHandlers) DeleteEligibilityRule(w http.ResponseWriter, r *http.Request) {
+	rowsAffected, err := a.Booker.DeleteEligibilityRule(r.PathValue("id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeRetired(w, rowsAffected, "Eligibility rule deleted")
+}
@@ -2,12 +2,16 @@ package api
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/petherin/spacetickets/internal/domains/bookings"
 )
 
@@ -16,30 +20,83 @@ type BookingHandlers struct {
 	Booker            bookings.Booker
 	HTTPClient        *http.Client
 	SpaceXAPIEndpoint string
+	Payments          bookings.PaymentGateway
+	RefundPolicy      bookings.RefundPolicy
+	// Launches, when set, is checked for SpaceX launches instead of the SpaceX API, e.g. a snapshot of SpaceX's launches.
+	Launches bookings.LaunchConflicts
+	// BoardingPassKey signs boarding passes. Boarding passes aren't available when it's not set.
+	BoardingPassKey ed25519.PrivateKey
+	// CheckInOpens is how long before departure passengers can check in and board.
+	CheckInOpens time.Duration
+	// Notifier, when set, tells customers when their booking is confirmed or cancelled.
+	Notifier bookings.Notifier
 }
 
-// NewBookingHandlers returns a new BookingHandlers object, assigning passed dependencies.
-func NewBookingHandlers(booker bookings.Booker, client *http.Client, spaceXAPIEndpoint string) BookingHandlers {
-	return BookingHandlers{Booker: booker, HTTPClient: client, SpaceXAPIEndpoint: spaceXAPIEndpoint}
+// NewBookingHandlers returns a new BookingHandlers object, assigning passed dependencies. Check-in opens
+// bookings.DefaultCheckInOpens before departure.
+func NewBookingHandlers(booker bookings.Booker, client *http.Client, spaceXAPIEndpoint string, payments bookings.PaymentGateway,
+	refundPolicy bookings.RefundPolicy) BookingHandlers {
+	return BookingHandlers{Booker: booker, HTTPClient: client, SpaceXAPIEndpoint: spaceXAPIEndpoint, Payments: payments,
+		RefundPolicy: refundPolicy, CheckInOpens: bookings.DefaultCheckInOpens}
 }
 
-// Get returns all bookings.
+// Get returns all bookings, or only those with the status in the status query parameter, e.g. disrupted. Anyone can
+// list bookings, so the customers' contact details are left out. Admins see them with AdminHandlers.GetBookings.
 func (b *BookingHandlers) Get(w http.ResponseWriter, r *http.Request) {
-	bookings, err := b.Booker.GetAll()
+	all, err := b.Booker.GetAll()
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	results := bookingsWithStatus(all, r.URL.Query().Get("status"))
+	for i := range results {
+		results[i] = publicBooking(results[i])
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(bookings)
+	err = json.NewEncoder(w).Encode(results)
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
 	}
 }
 
-// Post validates the requested booking and creates it if so.
+// bookingsWithStatus returns the bookings with the status, or all of them if status is empty.
+func bookingsWithStatus(all []bookings.Booking, status string) []bookings.Booking {
+	if len(status) == 0 {
+		return all
+	}
+
+	results := []bookings.Booking{}
+	for _, booking := range all {
+		if booking.Status == status {
+			results = append(results, booking)
+		}
+	}
+
+	return results
+}
+
+// publicBooking returns the booking without the customer's contact details or its access token, for responses to
+// callers who haven't authenticated. Booking ids are listed publicly, so knowing one doesn't prove the caller is the
+// customer.
+func publicBooking(booking bookings.Booking) bookings.Booking {
+	booking.Customer = publicCustomer(booking.Customer)
+	booking.AccessToken = ""
+	return booking
+}
+
+// publicCustomer returns the customer without their email address and phone number.
+func publicCustomer(customer bookings.Customer) bookings.Customer {
+	customer.Email, customer.Phone = "", ""
+	return customer
+}
+
+// Post validates the requested booking and creates it if so, then takes payment for it and confirms it. If the flight
+// is full or overlaps with a SpaceX launch and the waitlist query parameter is true, the customer joins the flight's
+// waitlist instead. Bookings rejected because of a SpaceX launch or the day of the week are sent alternative flights.
 func (b *BookingHandlers) Post(w http.ResponseWriter, r *http.Request) {
 	var booking bookings.Booking
 
@@ -50,49 +107,199 @@ func (b *BookingHandlers) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	launchPad, err := b.Booker.GetLaunchPad(booking.LaunchPadId)
-	if err != nil {
-		log.Println(err)
-		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+	// Return flights are booked with PostReturn, so they're linked to their outbound booking, and groups with PostGroup.
+	booking.Direction = bookings.DirectionOutbound
+	booking.OutboundBookingId = ""
+	booking.GroupId = ""
+	booking.AccessToken = ""
+
+	var newBooking *bookings.Booking
+	err = b.checkLaunchPad(booking)
+	if err == nil {
+		err = b.Booker.InTransaction(func(tx bookings.Booker) error {
+			var err error
+			newBooking, err = createBooking(tx, booking)
+			return err
+		})
+	}
+
+	if reason, ok := bookings.WaitlistReason(err); ok && r.URL.Query().Get("waitlist") == "true" {
+		b.joinWaitlist(w, bookings.NewWaitlistEntry(booking, reason))
 		return
 	}
 
-	spaceXLaunches, err := b.getSpaceXLaunch(launchPad.SpaceXLaunchPadId, booking)
+	if suggestsAlternatives(err) {
+		b.writeAlternatives(r.Context(), w, booking, err)
+		return
+	}
+
+	if err == nil {
+		newBooking, err = b.payFor(newBooking)
+	}
+
+	writeBookingResult(w, newBooking, err)
+}
+
+// groupBookingResponse lists the bookings made by a group booking, and the group id they share.
+type groupBookingResponse struct {
+	GroupId  string             `json:"group_id"`
+	Bookings []bookings.Booking `json:"bookings"`
+}
+
+// PostGroup books every passenger in the group on the same flight, or none of them if the flight can't take them all.
+// The flight is only checked once, however many passengers there are, and the group is paid for with one payment.
+func (b *BookingHandlers) PostGroup(w http.ResponseWriter, r *http.Request) {
+	var group bookings.GroupBooking
+
+	err := json.NewDecoder(r.Body).Decode(&group)
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
 		return
 	}
 
-	if spaceXLaunches.TotalDocs > 0 {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"Status": "Flight cancelled, overlaps with SpaceX launch"}`))
+	if len(group.Passengers) == 0 {
+		writeBookingResult(w, nil, bookings.ErrNoPassengers)
 		return
 	}
 
-	proposedWeekDay := booking.LaunchDate.Weekday().String()
-	validLaunch, err := b.Booker.IsLaunchScheduleValid(booking.LaunchPadId, proposedWeekDay, booking.DestinationId)
+	flight := group.Flight()
+
+	if err := b.checkLaunchPad(flight); err != nil {
+		writeBookingResult(w, nil, err)
+		return
+	}
+
+	flight.GroupId = uuid.NewString()
+
+	var newBookings []bookings.Booking
+	err = b.Booker.InTransaction(func(tx bookings.Booker) error {
+		var err error
+		newBookings, err = createBookings(tx, flight, group.Passengers)
+		return err
+	})
+	if err == nil {
+		newBookings, err = b.pay(flight.GroupId, newBookings)
+	}
+
+	writeBookingResult(w, groupBookingResponse{GroupId: flight.GroupId, Bookings: newBookings}, err)
+}
+
+// PostReturn books the return flight for the specified outbound booking, for the same customer. The request gives the
+// launch_date the flight leaves the destination and, optionally, the launch_pad_id it lands at, which defaults to the
+// launchpad the outbound flight left from. The return booking is returned without the customer's contact details.
+func (b *BookingHandlers) PostReturn(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		LaunchPadId string `json:"launch_pad_id"`
+		LaunchDate  string `json:"launch_date"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
 		return
 	}
 
-	if !validLaunch {
+	launchDate, err := time.Parse(time.DateOnly, request.LaunchDate)
+	if err != nil {
+		log.Println(fmt.Errorf("invalid date format. Use YYYY-MM-DD: %w", err))
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	var newBooking *bookings.Booking
+	err = b.Booker.InTransaction(func(tx bookings.Booker) error {
+		outbound, err := tx.Get(r.PathValue("id"))
+		if err != nil {
+			return err
+		}
+
+		if outbound.Deleted {
+			return fmt.Errorf("booking %s has been deleted: %w", outbound.Id, bookings.ErrNotFound)
+		}
+
+		if _, err := tx.GetReturn(outbound.Id); err == nil {
+			return bookings.ErrReturnAlreadyBooked
+		} else if !errors.Is(err, bookings.ErrNotFound) {
+			return err
+		}
+
+		booking, err := bookings.NewReturnBooking(*outbound, request.LaunchPadId, launchDate)
+		if err != nil {
+			return err
+		}
+
+		newBooking, err = createBooking(tx, booking)
+		return err
+	})
+	if err == nil {
+		newBooking, err = b.payFor(newBooking)
+	}
+	if newBooking != nil {
+		*newBooking = publicBooking(*newBooking)
+	}
+	if errors.Is(err, bookings.ErrNotFound) {
+		log.Println(err)
 		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"Status": "Flight cancelled, this launchpad does not fly to the destination on the requested day"}`))
+		w.Write([]byte(`{"Status": "ID not recognised"}`))
+		return
+	}
+
+	writeBookingResult(w, newBooking, err)
+}
+
+// cancellationResponse is the booking that's been cancelled, with the refund it was given.
+type cancellationResponse struct {
+	Status  string           `json:"Status"`
+	Booking bookings.Booking `json:"booking"`
+}
+
+// Delete cancels the specified booking for the customer, refunding it according to the refund policy, and offers its
+// seat to the flight's waitlist. The cancelled booking is returned without the customer's contact details.
+func (b *BookingHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if len(id) == 0 {
+		http.NotFound(w, r)
 		return
 	}
 
-	newBooking, err := b.Booker.Create(booking)
+	cancelled, err := bookings.CancelBooking(b.Booker, b.Payments, b.RefundPolicy, id, bookings.CancelledByCustomer, time.Now().UTC())
+	if errors.Is(err, bookings.ErrNotFound) {
+		log.Println(err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "ID not recognised"}`))
+		return
+	}
+	if errors.Is(err, bookings.ErrInvalidTransition) {
+		log.Println(err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "Booking cannot be cancelled once the passenger has boarded or the flight has departed"}`))
+		return
+	}
+	if errors.Is(err, bookings.ErrCancellationPending) {
+		log.Println(err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "Cancellation in progress, the booking will be cancelled once it has been refunded"}`))
+		return
+	}
+	if errors.Is(err, bookings.ErrPaymentPending) {
+		log.Println(err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "Booking cannot be cancelled until its payment is settled"}`))
+		return
+	}
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
 		return
 	}
 
+	notify(b.Booker, b.Notifier, bookings.NotifyCancelled, *cancelled)
+	b.promoteWaitlist(*cancelled)
+
 	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(newBooking)
+	err = json.NewEncoder(w).Encode(cancellationResponse{Status: "Record deleted", Booking: publicBooking(*cancelled)})
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
@@ -100,15 +307,29 @@ func (b *BookingHandlers) Post(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Delete marks the specified booking as deleted.
-func (b *BookingHandlers) Delete(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	if len(id) == 0 {
-		http.NotFound(w, r)
+// ineligibleResponse is why a passenger isn't eligible to fly, with a reason code such as bookings.IneligibleTooYoung.
+type ineligibleResponse struct {
+	Status     string `json:"Status"`
+	ReasonCode string `json:"reason_code"`
+}
+
+// writeBookingResult writes the new booking or bookings, or why they couldn't be made.
+func writeBookingResult(w http.ResponseWriter, result any, err error) {
+	var ineligible bookings.EligibilityError
+	if errors.As(err, &ineligible) {
+		writeJSON(w, http.StatusOK, ineligibleResponse{
+			Status:     "Passenger not eligible, " + ineligible.Reason,
+			ReasonCode: ineligible.Code,
+		})
+		return
+	}
+
+	if status, ok := bookingStatus(err); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "` + status + `"}`))
 		return
 	}
 
-	rowsAffected, err := b.Booker.Delete(id)
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
@@ -116,19 +337,352 @@ func (b *BookingHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+}
+
+// bookingStatus returns the status to reject a booking with because of err, or false if err isn't a reason to reject
+// it, e.g. because it's nil or something went wrong.
+func bookingStatus(err error) (string, bool) {
+	switch {
+	case errors.Is(err, bookings.ErrLaunchScheduleInvalid):
+		return "Flight cancelled, this launchpad does not fly to the destination on the requested day", true
+	case errors.Is(err, bookings.ErrReturnScheduleInvalid):
+		return "Flight cancelled, no return flight from the destination lands at this launchpad on the requested day", true
+	case errors.Is(err, bookings.ErrFlightCancelled):
+		return "Flight cancelled, this flight is not running on the requested day", true
+	case errors.Is(err, bookings.ErrLaunchPadClosed):
+		return "Flight cancelled, this launchpad is closed on the requested day", true
+	case errors.Is(err, bookings.ErrDestinationUnavailable):
+		return "Flight cancelled, this destination is unavailable on the requested day", true
+	case errors.Is(err, bookings.ErrLaunchPadRetired):
+		return "Flight cancelled, this launchpad has been retired", true
+	case errors.Is(err, bookings.ErrSpaceXConflict):
+		return "Flight cancelled, overlaps with SpaceX launch", true
+	case errors.Is(err, bookings.ErrDestinationRetired):
+		return "Flight cancelled, this destination has been retired", true
+	case errors.Is(err, bookings.ErrFlightFull):
+		return "Flight cancelled, no seats remaining on this flight", true
+	case errors.Is(err, bookings.ErrNotEnoughSeats):
+		return "Flight cancelled, not enough seats remaining on this flight for the group", true
+	case errors.Is(err, bookings.ErrNoPassengers):
+		return "Flight cancelled, the group has no passengers", true
+	case errors.Is(err, bookings.ErrReturnBeforeArrival):
+		return "Flight cancelled, the return flight departs before the outbound flight arrives", true
+	case errors.Is(err, bookings.ErrReturnAlreadyBooked):
+		return "Flight cancelled, a return flight has already been booked for this booking", true
+	case errors.Is(err, bookings.ErrHoldExpired):
+		return "Hold expired, the seat has been released", true
+	case errors.Is(err, bookings.ErrPromoCodeInvalid):
+		return "Flight cancelled, the promo code is not valid", true
+	case errors.Is(err, bookings.ErrInvalidEmail):
+		return "Flight cancelled, the email address is not valid", true
+	case errors.Is(err, bookings.ErrInvalidPhone):
+		return "Flight cancelled, the phone number is not valid", true
+	case errors.Is(err, bookings.ErrPaymentDeclined):
+		return "Payment declined, the booking has been released", true
+	case errors.Is(err, bookings.ErrPaymentPending):
+		return "Payment not confirmed, the booking is pending until the payment is settled", true
+	case errors.Is(err, bookings.ErrNotOutbound):
+		return "Flight cancelled, return flights can only be booked for outbound flights", true
+	default:
+		return "", false
+	}
+}
 
-	log.Printf("Number of rows updated: %d\n", rowsAffected)
+// checkLaunchPad checks the booking's launchpad hasn't been retired and that SpaceX aren't launching from it during the
+// flight's launch window, returning bookings.ErrLaunchPadRetired or bookings.ErrSpaceXConflict if they are.
+// The SpaceX API is checked before the transaction starts so the launchpad isn't locked while we wait for it.
+func (b *BookingHandlers) checkLaunchPad(booking bookings.Booking) error {
+	launchPad, err := b.Booker.GetLaunchPad(booking.LaunchPadId)
+	if err != nil {
+		return err
+	}
 
-	if rowsAffected == 0 {
-		w.Write([]byte(`{"Status": "ID not recognised"}`))
-		return
+	if !launchPad.Active {
+		return bookings.ErrLaunchPadRetired
+	}
+
+	entry, err := findScheduledFlight(b.Booker, booking)
+	if err != nil {
+		return err
+	}
+
+	window, err := bookings.NewLaunchWindow(*launchPad, entry, booking.LaunchDate)
+	if err != nil {
+		return err
+	}
+
+	clash, err := b.HasLaunch(launchPad.SpaceXLaunchPadId, window)
+	if err != nil {
+		return err
+	}
+
+	if clash {
+		return bookings.ErrSpaceXConflict
+	}
+
+	return nil
+}
+
+// createBooking creates a single booking with createBookings.
+func createBooking(tx bookings.Booker, booking bookings.Booking) (*bookings.Booking, error) {
+	created, err := createBookings(tx, booking, []bookings.Customer{booking.Customer})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created[0], nil
+}
+
+// createBookings books each passenger on the flight once their contact details and eligibility have been checked and
+// prepareFlight has checked it has seats for them all, pricing each seat from the flight's fare and the passenger's
+// age. Flights without a fare are booked without a price. The bookings are pending until they've been paid for. Each
+// booking is given its own access token, unless the booking already has one, e.g. from its outbound flight. It should
+// be called inside a transaction so nothing can change between the checks and the inserts, and so either every
+// passenger is booked or none are.
+func createBookings(tx bookings.Booker, booking bookings.Booking, passengers []bookings.Customer) ([]bookings.Booking, error) {
+	if len(passengers) == 0 {
+		return nil, bookings.ErrNoPassengers
+	}
+
+	for _, passenger := range passengers {
+		if err := passenger.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkEligibility(tx, booking, passengers); err != nil {
+		return nil, err
+	}
+
+	booking, err := prepareFlight(tx, booking, len(passengers))
+	if err != nil {
+		return nil, err
+	}
+
+	fare, rules, promo, err := findPricing(tx, booking.LaunchPadId, booking.DestinationId, booking.PromoCode, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	booking.Price, booking.Currency, booking.PromoCode = nil, "", ""
+	booking.Status, booking.PaymentId = bookings.StatusPending, ""
+	if promo != nil {
+		booking.PromoCode = promo.Code
+	}
+
+	accessToken := booking.AccessToken
+	created := make([]bookings.Booking, 0, len(passengers))
+	for _, passenger := range passengers {
+		booking.Customer = passenger
+
+		booking.AccessToken = accessToken
+		if len(booking.AccessToken) == 0 {
+			booking.AccessToken, err = bookings.NewAccessToken()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if fare != nil {
+			quote := bookings.NewQuote(*fare, rules, booking.LaunchDate, &passenger.Birthday, promo)
+			booking.Price = &quote.Price
+			booking.Currency = quote.Currency
+		}
+
+		newBooking, err := tx.Create(booking)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, *newBooking)
+	}
+
+	return created, nil
+}
+
+// checkEligibility checks every passenger can fly under the eligibility rules, returning a bookings.EligibilityError
+// for the first passenger who can't. Only outbound flights are checked, as passengers were checked when they booked the
+// flight out and a return flight isn't another trip.
+func checkEligibility(tx bookings.Booker, booking bookings.Booking, passengers []bookings.Customer) error {
+	if booking.Direction == bookings.DirectionReturn {
+		return nil
+	}
+
+	rules, err := tx.GetEligibilityRules()
+	if err != nil {
+		return err
+	}
+
+	for _, passenger := range passengers {
+		booking.Customer = passenger
+		if err := bookings.CheckEligibility(tx, rules, booking); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareFlight locks the launchpad, checks the schedule, its exceptions and that the flight has seats left for the
+// passengers, counting the seats other customers are holding. It returns the booking with the departure time the
+// schedule gives it and the arrival date its travel time gives it. A return flight is checked against the return
+// schedule and the seats on return flights landing at the launchpad.
+func prepareFlight(tx bookings.Booker, booking bookings.Booking, seats int) (bookings.Booking, error) {
+	launchPad, err := tx.GetLaunchPad(booking.LaunchPadId)
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	if !launchPad.Active {
+		return bookings.Booking{}, bookings.ErrLaunchPadRetired
+	}
+
+	destination, err := tx.GetDestination(booking.DestinationId)
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	if !destination.Active {
+		return bookings.Booking{}, bookings.ErrDestinationRetired
+	}
+
+	entry, err := checkScheduledFlight(tx, booking)
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	window, err := bookings.NewLaunchWindow(*launchPad, entry, booking.LaunchDate)
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	departureAt := window.Opens.UTC()
+	booking.DepartureAt = &departureAt
+
+	travelDays, err := tx.GetTravelDays(booking.LaunchPadId, booking.DestinationId)
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	arrival := bookings.EstimateArrival(booking.LaunchDate, travelDays)
+	booking.EstimatedArrival = &arrival
+
+	booked, err := tx.CountBookings(booking.Direction, booking.LaunchPadId, booking.LaunchDate)
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	held, err := tx.CountHolds(booking.Direction, booking.LaunchPadId, booking.LaunchDate, time.Now().UTC())
+	if err != nil {
+		return bookings.Booking{}, err
+	}
+
+	if booked+held >= launchPad.SeatCapacity {
+		return bookings.Booking{}, bookings.ErrFlightFull
+	}
+
+	if booked+held+seats > launchPad.SeatCapacity {
+		return bookings.Booking{}, bookings.ErrNotEnoughSeats
+	}
+
+	return booking, nil
+}
+
+// checkScheduledFlight checks the weekly schedule and its exceptions let the booking's flight go ahead on its launch
+// date, and returns the weekly schedule entry in force for it, or nil if it's an extra flight. A return flight that
+// isn't in the schedule returns bookings.ErrReturnScheduleInvalid.
+func checkScheduledFlight(tx bookings.Booker, booking bookings.Booking) (*bookings.ScheduleEntry, error) {
+	entry, err := findScheduledFlight(tx, booking)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := tx.GetScheduleExceptions(booking.LaunchDate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bookings.CheckFlight(entry != nil, exceptions, booking.Direction, booking.LaunchPadId, booking.DestinationId, booking.LaunchDate)
+	if errors.Is(err, bookings.ErrLaunchScheduleInvalid) && booking.Direction == bookings.DirectionReturn {
+		return nil, bookings.ErrReturnScheduleInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// findPricing returns the fare, fare rules and promo code that price seats on the flight between the launchpad and
+// destination. The fare is nil if the flight doesn't have one. A promo code that doesn't exist or can't be used today
+// returns bookings.ErrPromoCodeInvalid.
+func findPricing(booker bookings.Booker, launchPadId, destinationId, promoCode string, now time.Time) (*bookings.Fare, []bookings.FareRule, *bookings.PromoCode, error) {
+	var promo *bookings.PromoCode
+	if len(promoCode) > 0 {
+		var err error
+		promo, err = booker.GetPromoCode(bookings.NormalisePromoCode(promoCode))
+		if errors.Is(err, bookings.ErrNotFound) {
+			return nil, nil, nil, bookings.ErrPromoCodeInvalid
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if !promo.ValidOn(now) {
+			return nil, nil, nil, bookings.ErrPromoCodeInvalid
+		}
+	}
+
+	fare, err := booker.GetFare(launchPadId, destinationId)
+	if errors.Is(err, bookings.ErrNotFound) {
+		return nil, nil, promo, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rules, err := booker.GetFareRules()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return fare, rules, promo, nil
+}
+
+// findScheduledFlight returns the weekly schedule entry in force for the booking's flight, or nil if there isn't one,
+// e.g. because it's an extra flight.
+func findScheduledFlight(booker bookings.Booker, booking bookings.Booking) (*bookings.ScheduleEntry, error) {
+	entry, err := booker.FindScheduledFlight(booking.Direction, booking.LaunchPadId, booking.DestinationId, booking.LaunchDate)
+	if errors.Is(err, bookings.ErrNotFound) {
+		return nil, nil
+	}
+
+	return entry, err
+}
+
+// HasLaunch returns true if SpaceX are launching from the SpaceX launchpad during the launch window, so the handlers
+// can be used to reconcile bookings in the background.
+func (b *BookingHandlers) HasLaunch(spaceXLaunchPadId string, window bookings.LaunchWindow) (bool, error) {
+	if b.Launches != nil {
+		return b.Launches.HasLaunch(spaceXLaunchPadId, window)
+	}
+
+	spaceXLaunches, err := b.getSpaceXLaunch(spaceXLaunchPadId, window)
+	if err != nil {
+		return false, err
 	}
 
-	w.Write([]byte(`{"Status": "Record deleted"}`))
+	return spaceXLaunches.TotalDocs > 0, nil
 }
 
-// getSpaceXLaunch contacts the SpaceX API to check if there is a SpaceX launch from the requested launchpad and date.
-func (b *BookingHandlers) getSpaceXLaunch(spaceXLaunchId string, booking bookings.Booking) (*bookings.SpaceXLaunches, error) {
+// getSpaceXLaunch contacts the SpaceX API to check if there is a SpaceX launch from the requested launchpad during
+// the launch window.
+func (b *BookingHandlers) getSpaceXLaunch(spaceXLaunchId string, window bookings.LaunchWindow) (*bookings.SpaceXLaunches, error) {
 	fullURL, err := url.JoinPath(b.SpaceXAPIEndpoint, "/v4/launches/query")
 	if err != nil {
 		return nil, err
@@ -138,8 +692,8 @@ func (b *BookingHandlers) getSpaceXLaunch(spaceXLaunchId string, booking booking
 		Query: bookings.Query{
 			LaunchPad: spaceXLaunchId,
 			DateUtc: bookings.DateUtc{
-				Gte: booking.LaunchDate,
-				Lt:  booking.LaunchDate.Add(24 * time.Hour),
+				Gte: window.Opens.UTC(),
+				Lt:  window.Closes.UTC(),
 			},
 		},
 		Options: bookings.Options{
@@ -168,6 +722,10 @@ func (b *BookingHandlers) getSpaceXLaunch(spaceXLaunchId string, booking booking
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from SpaceX API: %s", resp.Status)
+	}
+
 	var spaceXLaunches bookings.SpaceXLaunches
 	err = json.NewDecoder(resp.Body).Decode(&spaceXLaunches)
 	if err != nil {
@@ -176,3 +734,30 @@ func (b *BookingHandlers) getSpaceXLaunch(spaceXLaunchId string, booking booking
 
 	return &spaceXLaunches, nil
 }
+
+// GetSpaceXLaunchPads contacts the SpaceX API to list every launchpad SpaceX know about, so the handlers can be used to
+// sync the launchpad catalogue in the background.
+func (b *BookingHandlers) GetSpaceXLaunchPads() ([]bookings.SpaceXLaunchPad, error) {
+	fullURL, err := url.JoinPath(b.SpaceXAPIEndpoint, "/v4/launchpads")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.HTTPClient.Get(fullURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from SpaceX API: %s", resp.Status)
+	}
+
+	var launchPads []bookings.SpaceXLaunchPad
+	err = json.NewDecoder(resp.Body).Decode(&launchPads)
+	if err != nil {
+		return nil, err
+	}
+
+	return launchPads, nil
+}
@@ -2,15 +2,24 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/database"
+	"github.com/petherin/spacetickets/internal/infrastructure/payments"
 )
 
 func TestServer_Get(t *testing.T) {
@@ -24,12 +33,19 @@ func TestServer_Get(t *testing.T) {
 		{
 			name:           "1. Successfully returns all bookings",
 			req:            httptest.NewRequest(http.MethodGet, "/api/v1/bookings", nil),
-			want:           `[{"id":"uuid-1","first_name":"Ian","last_name":"Thomson","gender":"Male","birthday":"2000-01-02T00:00:00Z","launch_pad_id":"4079f070-3e58-4e61-8af7-05c8de8e1fbf","destination_id":"fbd40165-03c7-47a5-be72-c79f81ebbf67","launch_date":"2011-01-02T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]`,
+			want:           `[{"id":"uuid-1","first_name":"Ian","last_name":"Thomson","gender":"Male","birthday":"2000-01-02T00:00:00Z","launch_pad_id":"4079f070-3e58-4e61-8af7-05c8de8e1fbf","destination_id":"fbd40165-03c7-47a5-be72-c79f81ebbf67","launch_date":"2011-01-02T00:00:00Z","departure_at":null,"estimated_arrival":null,"direction":"","outbound_booking_id":"","group_id":"","price":null,"currency":"","promo_code":"","status":"","payment_id":"","cancellation_reason":"","refund_amount":null,"refund_id":"","review_required":false,"review_reason":"","checked_in_at":null,"boarded_at":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]`,
 			wantErr:        nil,
 			wantStatusCode: 200,
 		},
 		{
-			name:           "2. Errors when getting all bookings, returns 500 and error message",
+			name:           "2. Only returns bookings with the requested status",
+			req:            httptest.NewRequest(http.MethodGet, "/api/v1/bookings?status=disrupted", nil),
+			want:           "[]\n",
+			wantErr:        nil,
+			wantStatusCode: 200,
+		},
+		{
+			name:           "3. Errors when getting all bookings, returns 500 and error message",
 			req:            httptest.NewRequest(http.MethodGet, "/api/v1/bookings", nil),
 			want:           ``,
 			wantErr:        fmt.Errorf("oops"),
@@ -39,7 +55,7 @@ func TestServer_Get(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handlers := NewBookingHandlers(bookerMock{ForceError: tt.wantErr}, nil, "")
+			handlers := NewBookingHandlers(bookerMock{ForceError: tt.wantErr}, nil, "", payments.NewFake(), bookings.DefaultRefundPolicy)
 			mux := &http.ServeMux{}
 			mux.HandleFunc("GET /api/v1/bookings", handlers.Get)
 
@@ -60,6 +76,153 @@ func TestServer_Get(t *testing.T) {
 	}
 }
 
+func TestServer_Get_ContactDetails(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	customer := passenger
+	customer.Email = "ian@example.com"
+	customer.Phone = "+44 20 7946 0958"
+	if _, err := repo.Create(bookings.Booking{Customer: customer, LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: time.Now().UTC()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handlers := NewBookingHandlers(repo, nil, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	admin := NewAdminHandlers(repo, payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("GET /api/v1/bookings", handlers.Get)
+	mux.HandleFunc("GET /api/v1/admin/bookings", admin.GetBookings)
+
+	tests := []struct {
+		name        string
+		path        string
+		wantContact bool
+	}{
+		{name: "1. Public list leaves out contact details", path: "/api/v1/bookings"},
+		{name: "2. Admin list includes contact details", path: "/api/v1/admin/bookings", wantContact: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			body := w.Body.String()
+			if !strings.Contains(body, `"first_name":"Ian"`) {
+				t.Fatalf("handler returned unexpected body: got %v", body)
+			}
+			if got := strings.Contains(body, `"email":"ian@example.com","phone":"+44 20 7946 0958"`); got != tt.wantContact {
+				t.Errorf("wrong contact details in body, got %v want contact details %v", body, tt.wantContact)
+			}
+		})
+	}
+}
+
+func TestServer_ContactDetails_Hidden(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("DELETE /api/v1/booking/{id}", handlers.Delete)
+	mux.HandleFunc("POST /api/v1/booking/{id}/return", handlers.PostReturn)
+	mux.HandleFunc("POST /api/v1/booking/{id}/check-in", handlers.PostCheckIn)
+	mux.HandleFunc("GET /api/v1/waitlist/{id}", handlers.GetWaitlistEntry)
+
+	customer := passenger
+	customer.Email = "ian@example.com"
+	customer.Phone = "+44 20 7946 0958"
+
+	create := func(launchDate time.Time, departureAt *time.Time) *bookings.Booking {
+		booking, err := repo.Create(bookings.Booking{Customer: customer, LaunchPadId: capeCanaveralId, DestinationId: moonId,
+			LaunchDate: launchDate, DepartureAt: departureAt, Status: bookings.StatusConfirmed, AccessToken: "secret"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return booking
+	}
+
+	now := time.Now().UTC()
+	today := bookings.Today(now)
+
+	// Cape Canaveral flies to the Moon on Mondays, and the seeded return flights from the Moon land there on Mondays.
+	monday := today.AddDate(0, 0, 7)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+
+	departureAt := now.Add(2 * time.Hour)
+	cancelled := create(monday, nil)
+	outbound := create(monday, nil)
+	checkingIn := create(today, &departureAt)
+
+	entry, err := repo.CreateWaitlistEntry(bookings.NewWaitlistEntry(bookings.Booking{Customer: customer, LaunchPadId: capeCanaveralId,
+		DestinationId: moonId, LaunchDate: monday, Direction: bookings.DirectionOutbound}, bookings.WaitlistFlightFull))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checkIn := httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+checkingIn.Id+"/check-in", nil)
+	checkIn.Header.Set("Authorization", "Bearer secret")
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want string
+	}{
+		{
+			name: "1. Cancelled booking",
+			req:  httptest.NewRequest(http.MethodDelete, "/api/v1/booking/"+cancelled.Id, nil),
+			want: `{"Status":"Record deleted"`,
+		},
+		{
+			name: "2. Return flight",
+			req: httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+outbound.Id+"/return",
+				strings.NewReader(`{"launch_date": "`+monday.AddDate(0, 0, 7).Format(time.DateOnly)+`"}`)),
+			want: `"direction":"return"`,
+		},
+		{
+			name: "3. Checked in booking",
+			req:  checkIn,
+			want: `"status":"checked_in"`,
+		},
+		{
+			name: "4. Waitlist entry",
+			req:  httptest.NewRequest(http.MethodGet, "/api/v1/waitlist/"+entry.Id, nil),
+			want: `"reason":"flight_full"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, tt.req)
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) || !strings.Contains(body, `"first_name":"Ian"`) {
+				t.Fatalf("handler returned unexpected body: got %v want %v", body, tt.want)
+			}
+			if strings.Contains(body, `"email"`) || strings.Contains(body, `"phone"`) || strings.Contains(body, `"access_token"`) {
+				t.Errorf("handler returned contact details or the access token: got %v", body)
+			}
+		})
+	}
+}
+
 func TestServer_Post(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -88,7 +251,7 @@ func TestServer_Post(t *testing.T) {
 					Header: make(http.Header),
 				}
 			}),
-			want:           `{"id":"uuid-1","first_name":"Ian","last_name":"Thomson","gender":"Male","birthday":"2000-01-02T00:00:00Z","launch_pad_id":"uuid-2","destination_id":"uuid-3","launch_date":"2011-01-02T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+			want:           `{"id":"uuid-1","first_name":"Ian","last_name":"Thomson","gender":"Male","birthday":"2000-01-02T00:00:00Z","launch_pad_id":"uuid-2","destination_id":"uuid-3","launch_date":"2011-01-02T00:00:00Z","departure_at":null,"estimated_arrival":null,"direction":"","outbound_booking_id":"","group_id":"","price":null,"currency":"","promo_code":"","status":"confirmed","payment_id":"","cancellation_reason":"","refund_amount":null,"refund_id":"","review_required":false,"review_reason":"","checked_in_at":null,"boarded_at":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
 			wantStatusCode: 200,
 		},
 		{
@@ -111,7 +274,7 @@ func TestServer_Post(t *testing.T) {
 					Header: make(http.Header),
 				}
 			}),
-			want:           `{"Status": "Flight cancelled, overlaps with SpaceX launch"}`,
+			want:           `{"Status":"Flight cancelled, overlaps with SpaceX launch","alternatives":[]}`,
 			wantStatusCode: 200,
 		},
 		{
@@ -123,6 +286,66 @@ func TestServer_Post(t *testing.T) {
   "birthday": "2000-04-12",
   "launch_pad_id": "false",
   "destination_id": "fbd40165-03c7-47a5-be72-c79f81ebbf67",
+  "launch_date": "2030-10-02"
+}`)),
+			client: NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(bytes.NewBufferString(`{
+					"totalDocs": 0
+}`)),
+					Header: make(http.Header),
+				}
+			}),
+			want:           `{"Status":"Flight cancelled, this launchpad does not fly to the destination on the requested day","alternatives":[{"launch_pad_id":"other","launch_pad_name":"Kennedy","destination_id":"fbd40165-03c7-47a5-be72-c79f81ebbf67","launch_date":"2030-10-02T00:00:00Z","departure_at":"2030-10-03T00:00:00Z"}]}`,
+			wantStatusCode: 200,
+		},
+		{
+			name: "4. Booking cancelled, every seat on the flight already booked",
+			req: httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(`{
+  "first_name": "Ian",
+  "last_name": "Thomson",
+  "gender": "Male",
+  "birthday": "2000-04-12",
+  "launch_pad_id": "full",
+  "destination_id": "fbd40165-03c7-47a5-be72-c79f81ebbf67",
+  "launch_date": "2022-10-05"
+}`)),
+			client: NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(bytes.NewBufferString(`{
+					"totalDocs": 0
+}`)),
+					Header: make(http.Header),
+				}
+			}),
+			want:           `{"Status": "Flight cancelled, no seats remaining on this flight"}`,
+			wantStatusCode: 200,
+		},
+		{
+			name: "5. Booking cancelled, launchpad has been retired",
+			req: httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(`{
+  "first_name": "Ian",
+  "last_name": "Thomson",
+  "gender": "Male",
+  "birthday": "2000-04-12",
+  "launch_pad_id": "retired",
+  "destination_id": "fbd40165-03c7-47a5-be72-c79f81ebbf67",
+  "launch_date": "2022-10-05"
+}`)),
+			want:           `{"Status": "Flight cancelled, this launchpad has been retired"}`,
+			wantStatusCode: 200,
+		},
+		{
+			name: "6. Booking cancelled, launchpad closed on the requested day",
+			req: httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(`{
+  "first_name": "Ian",
+  "last_name": "Thomson",
+  "gender": "Male",
+  "birthday": "2000-04-12",
+  "launch_pad_id": "closed",
+  "destination_id": "fbd40165-03c7-47a5-be72-c79f81ebbf67",
   "launch_date": "2022-10-05"
 }`)),
 			client: NewTestClient(func(req *http.Request) *http.Response {
@@ -134,14 +357,14 @@ func TestServer_Post(t *testing.T) {
 					Header: make(http.Header),
 				}
 			}),
-			want:           `{"Status": "Flight cancelled, this launchpad does not fly to the destination on the requested day"}`,
+			want:           `{"Status": "Flight cancelled, this launchpad is closed on the requested day"}`,
 			wantStatusCode: 200,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handlers := NewBookingHandlers(bookerMock{}, tt.client, "")
+			handlers := NewBookingHandlers(bookerMock{}, tt.client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
 			mux := &http.ServeMux{}
 			mux.HandleFunc("POST /api/v1/booking", handlers.Post)
 
@@ -162,6 +385,49 @@ func TestServer_Post(t *testing.T) {
 	}
 }
 
+func TestServer_PostLaunchWindow(t *testing.T) {
+	var gotQuery bookings.Query
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		var payload bookings.SpaceXLaunchesRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		gotQuery = payload.Query
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	handlers := NewBookingHandlers(bookerMock{}, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(`{
+  "first_name": "Ian",
+  "last_name": "Thomson",
+  "gender": "Male",
+  "birthday": "2000-04-12",
+  "launch_pad_id": "kwajalein",
+  "destination_id": "fbd40165-03c7-47a5-be72-c79f81ebbf67",
+  "launch_date": "2022-10-05"
+}`)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", w.Code, http.StatusOK)
+	}
+
+	// Kwajalein is UTC+12, so the 20:00 to 23:00 window on 5 October is 08:00 to 11:00 UTC.
+	wantGte := time.Date(2022, 10, 5, 8, 0, 0, 0, time.UTC)
+	wantLt := time.Date(2022, 10, 5, 11, 0, 0, 0, time.UTC)
+	if !gotQuery.DateUtc.Gte.Equal(wantGte) || !gotQuery.DateUtc.Lt.Equal(wantLt) {
+		t.Errorf("wrong SpaceX query window, got %v to %v want %v to %v", gotQuery.DateUtc.Gte, gotQuery.DateUtc.Lt, wantGte, wantLt)
+	}
+}
+
 func TestServer_Delete(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -172,7 +438,7 @@ func TestServer_Delete(t *testing.T) {
 		{
 			name:           "1. Successfully deletes a booking",
 			req:            httptest.NewRequest(http.MethodDelete, "/api/v1/booking/uuid-1", nil),
-			want:           `{"Status": "Record deleted"}`,
+			want:           `{"Status":"Record deleted","booking":{"id":"uuid-1"`,
 			wantStatusCode: 200,
 		},
 		{
@@ -185,7 +451,7 @@ func TestServer_Delete(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handlers := NewBookingHandlers(bookerMock{}, nil, "")
+			handlers := NewBookingHandlers(bookerMock{}, nil, "", payments.NewFake(), bookings.DefaultRefundPolicy)
 			mux := &http.ServeMux{}
 			mux.HandleFunc("DELETE /api/v1/booking/{id}", handlers.Delete)
 
@@ -206,80 +472,585 @@ func TestServer_Delete(t *testing.T) {
 	}
 }
 
-type bookerMock struct {
-	ForceError error
+func TestServer_MemoryBooker(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("GET /api/v1/bookings", handlers.Get)
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(`{
+  "first_name": "Ian",
+  "last_name": "Thomson",
+  "gender": "Male",
+  "birthday": "2000-04-12",
+  "launch_pad_id": "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+  "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+  "launch_date": "2010-12-06"
+}`)))
+	if !strings.Contains(w.Body.String(), `"first_name":"Ian"`) {
+		t.Fatalf("booking not created: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/bookings", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, `"first_name":"Brian"`) || !strings.Contains(body, `"first_name":"Ian"`) {
+		t.Errorf("handler returned unexpected body: got %v", body)
+	}
 }
 
-func (b bookerMock) GetAll() ([]bookings.Booking, error) {
-	if b.ForceError != nil {
-		return nil, b.ForceError
+func TestServer_PostReturn(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	birthday, _ := time.Parse(time.DateOnly, "2000-01-02")
-	launchDate, _ := time.Parse(time.DateOnly, "2011-01-02")
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
 
-	return []bookings.Booking{
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+	mux.HandleFunc("POST /api/v1/booking/{id}/return", handlers.PostReturn)
+
+	// Cape Canaveral flies to the Moon on Mondays, and the seeded return flights from the Moon land there on Mondays.
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(`{
+  "first_name": "Ian",
+  "last_name": "Thomson",
+  "gender": "Male",
+  "birthday": "2000-04-12",
+  "launch_pad_id": "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+  "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+  "launch_date": "2010-12-06"
+}`)))
+
+	var outbound struct {
+		Id          string `json:"id"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &outbound); err != nil || len(outbound.Id) == 0 || len(outbound.AccessToken) == 0 {
+		t.Fatalf("booking not created with an access token: %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"estimated_arrival":"2010-12-09T00:00:00Z","direction":"outbound"`) {
+		t.Errorf("wrong estimated arrival: %s", w.Body.String())
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want string
+	}{
 		{
-			Id: "uuid-1",
-			Customer: bookings.Customer{
-				FirstName: "Ian",
-				LastName:  "Thomson",
-				Birthday:  birthday,
-				Gender:    "Male",
-			},
-			LaunchPadId:   "4079f070-3e58-4e61-8af7-05c8de8e1fbf",
-			DestinationId: "fbd40165-03c7-47a5-be72-c79f81ebbf67",
-			LaunchDate:    launchDate,
+			name: "1. Return flight before the outbound flight arrives is rejected",
+			id:   outbound.Id,
+			body: `{"launch_date": "2010-12-06"}`,
+			want: `{"Status": "Flight cancelled, the return flight departs before the outbound flight arrives"}`,
 		},
-	}, nil
-}
+		{
+			name: "2. Day without a return flight from the destination is rejected",
+			id:   outbound.Id,
+			body: `{"launch_date": "2010-12-14"}`,
+			want: `{"Status": "Flight cancelled, no return flight from the destination lands at this launchpad on the requested day"}`,
+		},
+		{
+			name: "3. Successfully books the return flight",
+			id:   outbound.Id,
+			body: `{"launch_date": "2010-12-13"}`,
+			want: `"launch_date":"2010-12-13T00:00:00Z","departure_at":"2010-12-13T05:00:00Z","estimated_arrival":"2010-12-16T00:00:00Z","direction":"return","outbound_booking_id":"` + outbound.Id + `"`,
+		},
+		{
+			name: "4. Second return flight is rejected",
+			id:   outbound.Id,
+			body: `{"launch_date": "2010-12-20"}`,
+			want: `{"Status": "Flight cancelled, a return flight has already been booked for this booking"}`,
+		},
+		{
+			name: "5. Unrecognised booking",
+			id:   "nope",
+			body: `{"launch_date": "2010-12-20"}`,
+			want: `{"Status": "ID not recognised"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+tt.id+"/return", strings.NewReader(tt.body)))
 
-func (b bookerMock) Create(booking bookings.Booking) (*bookings.Booking, error) {
-	birthday, _ := time.Parse(time.DateOnly, "2000-01-02")
-	launchDate, _ := time.Parse(time.DateOnly, "2011-01-02")
+			if status := w.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
 
-	return &bookings.Booking{
-		Id: "uuid-1",
-		Customer: bookings.Customer{
-			FirstName: "Ian",
-			LastName:  "Thomson",
-			Birthday:  birthday,
-			Gender:    "Male",
-		},
-		LaunchPadId:   "uuid-2",
-		DestinationId: "uuid-3",
-		LaunchDate:    launchDate,
-	}, nil
-}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+		})
+	}
 
-func (b bookerMock) Delete(bookingId string) (int64, error) {
-	if bookingId == "zero" {
-		return 0, nil
+	// The return flight shares the outbound booking's access token, without giving it out to whoever booked it.
+	returnFlight, err := repo.GetReturn(outbound.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if returnFlight.AccessToken != outbound.AccessToken {
+		t.Errorf("wrong access token for the return flight, got %q want %q", returnFlight.AccessToken, outbound.AccessToken)
 	}
-	return 1, nil
 }
 
-func (b bookerMock) GetLaunchPad(id string) (*bookings.LaunchPad, error) {
-	return &bookings.LaunchPad{
-		Id:                "uuid-1",
-		FullName:          "Cape Canaveral",
-		SpaceXLaunchPadId: "123",
-	}, nil
-}
+func TestServer_PostGroup(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-func (b bookerMock) IsLaunchScheduleValid(launchPadId, dayOfWeek, destinationId string) (bool, error) {
-	if launchPadId == "false" {
-		return false, nil
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	// A small launchpad with only three seats, flying to the Moon on Mondays.
+	launchPad, err := repo.CreateLaunchPad(bookings.LaunchPad{FullName: "Small Pad", SpaceXLaunchPadId: "5e9e4502f5090927f8566f99", SeatCapacity: 3, Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.CreateScheduleEntry(bookings.ScheduleEntry{LaunchPadId: launchPad.Id, DayOfWeek: "Monday", DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	return true, nil
-}
 
-// RoundTripFunc defines a function for a RoundTrip
-type RoundTripFunc func(req *http.Request) *http.Response
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/bookings/group", handlers.PostGroup)
 
-// RoundTrip is a custom RoundTrip assigned to an http.client used in tests so we can control the response
-func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
-	return f(req), nil
+	passenger := `{"first_name": "Ian", "last_name": "Thomson", "gender": "Male", "birthday": "2000-04-12"}`
+	group := func(launchDate string, passengers ...string) string {
+		return `{"launch_pad_id": "` + launchPad.Id + `", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "` + launchDate + `", "passengers": [` + strings.Join(passengers, ", ") + `]}`
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		want      string
+		wantCount int
+	}{
+		{
+			name:      "1. Successfully books everyone in the group",
+			body:      group("2010-12-06", passenger, passenger),
+			want:      `"group_id":"`,
+			wantCount: 2,
+		},
+		{
+			name:      "2. Group bigger than the seats remaining books nobody",
+			body:      group("2010-12-06", passenger, passenger),
+			want:      `{"Status": "Flight cancelled, not enough seats remaining on this flight for the group"}`,
+			wantCount: 2,
+		},
+		{
+			name:      "3. Group on a day the launchpad doesn't fly to the destination books nobody",
+			body:      group("2010-12-07", passenger, passenger),
+			want:      `{"Status": "Flight cancelled, this launchpad does not fly to the destination on the requested day"}`,
+			wantCount: 2,
+		},
+		{
+			name:      "4. Group without passengers",
+			body:      group("2010-12-06"),
+			want:      `{"Status": "Flight cancelled, the group has no passengers"}`,
+			wantCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/bookings/group", strings.NewReader(tt.body)))
+
+			if status := w.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+
+			upcoming, _ := repo.GetUpcoming(launchPad.Id, time.Time{})
+			if len(upcoming) != tt.wantCount {
+				t.Errorf("wrong number of bookings, got %d want %d", len(upcoming), tt.wantCount)
+			}
+			if len(upcoming) > 1 && (len(upcoming[0].GroupId) == 0 || upcoming[0].GroupId != upcoming[1].GroupId) {
+				t.Errorf("bookings don't share a group id, got %+v", upcoming)
+			}
+		})
+	}
+}
+
+func TestServer_Holds(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	// A launchpad with a single seat, flying to the Moon on Mondays.
+	launchPad, err := repo.CreateLaunchPad(bookings.LaunchPad{FullName: "Tiny Pad", SpaceXLaunchPadId: "5e9e4502f5090927f8566f99", SeatCapacity: 1, Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.CreateScheduleEntry(bookings.ScheduleEntry{LaunchPadId: launchPad.Id, DayOfWeek: "Monday", DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	launchDate, _ := time.Parse(time.DateOnly, "2010-12-13")
+	expired, err := repo.CreateHold(bookings.Hold{LaunchPadId: launchPad.Id, DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9", LaunchDate: launchDate, ExpiresAt: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+	mux.HandleFunc("POST /api/v1/holds", handlers.PostHold)
+	mux.HandleFunc("POST /api/v1/holds/{id}/confirm", handlers.PostHoldConfirm)
+
+	post := func(url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(body)))
+		return w
+	}
+
+	hold := `{"launch_pad_id": "` + launchPad.Id + `", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "2010-12-06", "minutes": 10}`
+	customer := `{"first_name": "Ian", "last_name": "Thomson", "gender": "Male", "birthday": "2000-04-12"}`
+
+	w := post("/api/v1/holds", hold)
+	var held bookings.Hold
+	if err := json.NewDecoder(w.Body).Decode(&held); err != nil || len(held.Id) == 0 {
+		t.Fatalf("hold not created, got %v", err)
+	}
+	if minutes := time.Until(held.ExpiresAt).Minutes(); minutes < 9 || minutes > 10 {
+		t.Errorf("wrong hold expiry, got %v", held.ExpiresAt)
+	}
+
+	full := `{"Status": "Flight cancelled, no seats remaining on this flight"}`
+	if w := post("/api/v1/holds", hold); w.Body.String() != full {
+		t.Errorf("held seat was held again, got %v", w.Body.String())
+	}
+
+	booking := `{"first_name": "Jane", "last_name": "Thomson", "gender": "Female", "birthday": "2000-04-12", "launch_pad_id": "` + launchPad.Id + `", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "2010-12-06"}`
+	if w := post("/api/v1/booking", booking); w.Body.String() != full {
+		t.Errorf("held seat was booked, got %v", w.Body.String())
+	}
+
+	w = post("/api/v1/holds/"+held.Id+"/confirm", customer)
+	if !strings.Contains(w.Body.String(), `"first_name":"Ian"`) || !strings.Contains(w.Body.String(), `"launch_date":"2010-12-06T00:00:00Z"`) {
+		t.Errorf("hold not confirmed, got %v", w.Body.String())
+	}
+
+	if _, err := repo.GetHold(held.Id); !errors.Is(err, bookings.ErrNotFound) {
+		t.Errorf("confirmed hold not released, got %v", err)
+	}
+
+	if w := post("/api/v1/holds/"+held.Id+"/confirm", customer); w.Body.String() != `{"Status": "ID not recognised"}` {
+		t.Errorf("hold confirmed twice, got %v", w.Body.String())
+	}
+
+	if w := post("/api/v1/holds/"+expired.Id+"/confirm", customer); w.Body.String() != `{"Status": "Hold expired, the seat has been released"}` {
+		t.Errorf("expired hold confirmed, got %v", w.Body.String())
+	}
+
+	// The expired hold on the following Monday doesn't take the only seat.
+	nextWeek := strings.Replace(hold, "2010-12-06", "2010-12-13", 1)
+	if w := post("/api/v1/holds", nextWeek); !strings.Contains(w.Body.String(), `"expires_at"`) {
+		t.Errorf("expired hold still counted, got %v", w.Body.String())
+	}
+
+	tooLong := strings.Replace(hold, `"minutes": 10`, `"minutes": 120`, 1)
+	if w := post("/api/v1/holds", tooLong); w.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestServer_GetQuote(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handlers := NewBookingHandlers(repo, nil, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("GET /api/v1/quote", handlers.GetQuote)
+
+	// The seeded fare from Cape Canaveral to the Moon is $250,000, and flights on Saturdays cost 20% more.
+	const flight = "/api/v1/quote?launch_pad_id=b542c0cf-7fe3-4bb1-a63f-7cbdf8359975&destination_id=466fc378-14eb-4ed9-8bec-d29abe54c5a9"
+
+	tests := []struct {
+		name           string
+		query          string
+		want           string
+		wantStatusCode int
+	}{
+		{
+			name:           "1. Base price on a weekday",
+			query:          flight + "&launch_date=2010-12-06",
+			want:           `"base_price":25000000,"price":25000000,"currency":"USD","adjustments":[]`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "2. Weekend flight for a senior with a promo code",
+			query:          flight + "&launch_date=2010-12-11&birthday=1936-10-09&promo_code=welcome10",
+			want:           `"price":21600000,"currency":"USD","adjustments":[{"reason":"weekend","multiplier":1.2},{"reason":"senior discount","multiplier":0.8},{"reason":"promo code WELCOME10","multiplier":0.9}]`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "3. Unknown promo code",
+			query:          flight + "&launch_date=2010-12-06&promo_code=nope",
+			want:           `{"Status":"Promo code is not valid"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "4. Flight without a fare",
+			query:          "/api/v1/quote?launch_pad_id=unknown&destination_id=466fc378-14eb-4ed9-8bec-d29abe54c5a9&launch_date=2010-12-06",
+			want:           `{"Status":"No fare has been set for this flight"}`,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "5. Missing launch date",
+			query:          flight,
+			want:           `{"Status":"launch_pad_id, destination_id and launch_date are required"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.query, nil))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+type bookerMock struct {
+	// Catalogue, Holds, Pricing, Eligibility, Waitlist, ExternalLaunches and Lifecycle are embedded so the mock satisfies
+	// bookings.Booker, calling a method that isn't overridden panics.
+	bookings.Catalogue
+	bookings.Holds
+	bookings.Pricing
+	bookings.Eligibility
+	bookings.Waitlist
+	bookings.ExternalLaunches
+	bookings.Lifecycle
+	ForceError error
+}
+
+func (b bookerMock) GetAll() ([]bookings.Booking, error) {
+	if b.ForceError != nil {
+		return nil, b.ForceError
+	}
+
+	birthday, _ := time.Parse(time.DateOnly, "2000-01-02")
+	launchDate, _ := time.Parse(time.DateOnly, "2011-01-02")
+
+	return []bookings.Booking{
+		{
+			Id: "uuid-1",
+			Customer: bookings.Customer{
+				FirstName: "Ian",
+				LastName:  "Thomson",
+				Birthday:  birthday,
+				Gender:    "Male",
+			},
+			LaunchPadId:   "4079f070-3e58-4e61-8af7-05c8de8e1fbf",
+			DestinationId: "fbd40165-03c7-47a5-be72-c79f81ebbf67",
+			LaunchDate:    launchDate,
+		},
+	}, nil
+}
+
+func (b bookerMock) Create(booking bookings.Booking) (*bookings.Booking, error) {
+	birthday, _ := time.Parse(time.DateOnly, "2000-01-02")
+	launchDate, _ := time.Parse(time.DateOnly, "2011-01-02")
+
+	return &bookings.Booking{
+		Id: "uuid-1",
+		Customer: bookings.Customer{
+			FirstName: "Ian",
+			LastName:  "Thomson",
+			Birthday:  birthday,
+			Gender:    "Male",
+		},
+		LaunchPadId:   "uuid-2",
+		DestinationId: "uuid-3",
+		LaunchDate:    launchDate,
+	}, nil
+}
+
+func (b bookerMock) Get(bookingId string) (*bookings.Booking, error) {
+	if bookingId != "uuid-1" {
+		return nil, bookings.ErrNotFound
+	}
+
+	return b.Create(bookings.Booking{})
+}
+
+func (b bookerMock) GetReturn(outboundBookingId string) (*bookings.Booking, error) {
+	return nil, bookings.ErrNotFound
+}
+
+func (b bookerMock) Delete(bookingId string) (int64, error) {
+	if bookingId == "zero" {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (b bookerMock) GetLaunchPad(id string) (*bookings.LaunchPad, error) {
+	timezone := "America/New_York"
+	if id == "kwajalein" {
+		timezone = "Pacific/Kwajalein"
+	}
+
+	return &bookings.LaunchPad{
+		Id:                "uuid-1",
+		FullName:          "Cape Canaveral",
+		SpaceXLaunchPadId: "123",
+		SeatCapacity:      100,
+		Timezone:          timezone,
+		Active:            id != "retired",
+	}, nil
+}
+
+func (b bookerMock) GetLaunchPads() ([]bookings.LaunchPad, error) {
+	return []bookings.LaunchPad{{Id: "other", FullName: "Kennedy", SpaceXLaunchPadId: "456", SeatCapacity: 100, Timezone: "UTC", Active: true}}, nil
+}
+
+func (b bookerMock) FindScheduledFlight(direction, launchPadId, destinationId string, launchDate time.Time) (*bookings.ScheduleEntry, error) {
+	if launchPadId == "false" {
+		return nil, bookings.ErrNotFound
+	}
+	return &bookings.ScheduleEntry{
+		LaunchPadId:   launchPadId,
+		DayOfWeek:     launchDate.Weekday().String(),
+		DestinationId: destinationId,
+		WindowOpens:   "20:00",
+		WindowCloses:  "23:00",
+	}, nil
+}
+
+func (b bookerMock) GetDestination(id string) (*bookings.Destination, error) {
+	return &bookings.Destination{
+		Id:     id,
+		Name:   "Pluto",
+		Active: true,
+	}, nil
+}
+
+func (b bookerMock) IsLaunchScheduleValid(direction, launchPadId, destinationId string, launchDate time.Time) (bool, error) {
+	if launchPadId == "false" {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (b bookerMock) GetScheduleExceptions(date time.Time) ([]bookings.ScheduleException, error) {
+	return []bookings.ScheduleException{
+		{Kind: bookings.ExceptionLaunchPadClosed, LaunchPadId: "closed", Direction: bookings.DirectionOutbound, StartDate: date, EndDate: date},
+	}, nil
+}
+
+func (b bookerMock) CountBookings(direction, launchPadId string, launchDate time.Time) (int, error) {
+	if launchPadId == "full" {
+		return 100, nil
+	}
+	return 0, nil
+}
+
+func (b bookerMock) CountHolds(direction, launchPadId string, launchDate, now time.Time) (int, error) {
+	return 0, nil
+}
+
+func (b bookerMock) GetFare(launchPadId, destinationId string) (*bookings.Fare, error) {
+	return nil, bookings.ErrNotFound
+}
+
+func (b bookerMock) GetEligibilityRules() ([]bookings.EligibilityRule, error) {
+	return nil, nil
+}
+
+func (b bookerMock) GetTravelDays(launchPadId, destinationId string) (int, error) {
+	return 3, nil
+}
+
+func (b bookerMock) GetUpcoming(launchPadId string, from time.Time) ([]bookings.Booking, error) {
+	return []bookings.Booking{}, nil
+}
+
+func (b bookerMock) FlagForReview(bookingId, reason string) (int64, error) {
+	return 1, nil
+}
+
+func (b bookerMock) SetStatus(bookingId, status, paymentId string) (int64, error) {
+	return 1, nil
+}
+
+func (b bookerMock) ClaimCancellation(bookingId, from, reason string) (int64, error) {
+	return 1, nil
+}
+
+func (b bookerMock) Cancel(bookingId, reason string, refundAmount *int64, refundId string) (int64, error) {
+	return 1, nil
+}
+
+func (b bookerMock) ClearDisruption(bookingId, status string) (int64, error) {
+	return 1, nil
+}
+
+func (b bookerMock) GetWaitlist(from time.Time) ([]bookings.WaitlistEntry, error) {
+	return []bookings.WaitlistEntry{}, nil
+}
+
+func (b bookerMock) InTransaction(fn func(tx bookings.Booker) error) error {
+	return fn(b)
+}
+
+// RoundTripFunc defines a function for a RoundTrip
+type RoundTripFunc func(req *http.Request) *http.Response
+
+// RoundTrip is a custom RoundTrip assigned to an http.client used in tests so we can control the response
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
 }
 
 // NewTestClient returns *http.client with Transport replaced to avoid making real calls
@@ -288,3 +1059,964 @@ func NewTestClient(fn RoundTripFunc) *http.Client {
 		Transport: fn,
 	}
 }
+
+func TestServer_PostPayment(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	gateway := payments.NewFake()
+	handlers := NewBookingHandlers(repo, client, "", gateway, bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+
+	// The seeded fare from Cape Canaveral to the Moon is $250,000.
+	const body = `{"first_name": "Ian", "last_name": "Thomson", "gender": "Male", "birthday": "1980-04-12", "launch_pad_id": "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "2010-12-06"}`
+	launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+	booked, _ := repo.CountBookings(bookings.DirectionOutbound, "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", launchDate)
+
+	tests := []struct {
+		name       string
+		decline    bool
+		want       string
+		wantBooked int
+	}{
+		{
+			name:       "1. Payment taken, booking confirmed",
+			want:       `"price":25000000,"currency":"USD","promo_code":"","status":"confirmed","payment_id":"`,
+			wantBooked: booked + 1,
+		},
+		{
+			name:       "2. Payment declined, booking released",
+			decline:    true,
+			want:       `{"Status": "Payment declined, the booking has been released"}`,
+			wantBooked: booked + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway.Decline = tt.decline
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(body)))
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+
+			count, _ := repo.CountBookings(bookings.DirectionOutbound, "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", launchDate)
+			if count != tt.wantBooked {
+				t.Errorf("wrong number of bookings, got %d want %d", count, tt.wantBooked)
+			}
+		})
+	}
+
+	taken := gateway.Payments()
+	if len(taken) != 1 || taken[0].Amount != 25000000 || taken[0].Currency != "USD" {
+		t.Fatalf("wrong payments taken, got %+v", taken)
+	}
+
+	got, err := repo.Get(taken[0].Reference)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != bookings.StatusConfirmed || got.PaymentId != taken[0].Id {
+		t.Errorf("wrong booking status, got %q and payment id %q", got.Status, got.PaymentId)
+	}
+}
+
+func TestServer_SettlePayments(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	gateway := payments.NewFake()
+	handlers := NewBookingHandlers(repo, client, "", gateway, bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+
+	// pending books a flight while the gateway times out, and returns the booking left pending.
+	pending := func(lastName string) bookings.Booking {
+		t.Helper()
+
+		gateway.Err = errors.New("gateway timed out")
+		defer func() { gateway.Err = nil }()
+
+		body := `{"first_name": "Ian", "last_name": "` + lastName + `", "gender": "Male", "birthday": "1980-04-12", "launch_pad_id": "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "2010-12-06"}`
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(body)))
+
+		want := `{"Status": "Payment not confirmed, the booking is pending until the payment is settled"}`
+		if !strings.Contains(w.Body.String(), want) {
+			t.Fatalf("handler returned unexpected body: got %v want %v", w.Body.String(), want)
+		}
+
+		all, _ := repo.GetAll()
+		for _, booking := range all {
+			if booking.LastName == lastName {
+				return booking
+			}
+		}
+
+		t.Fatalf("booking for %s not kept", lastName)
+		return bookings.Booking{}
+	}
+
+	paid := pending("Thomson")
+	declined := pending("Smith")
+	if paid.Status != bookings.StatusPending || paid.Deleted {
+		t.Fatalf("booking should be pending after the gateway timed out, got %+v", paid)
+	}
+
+	// Bookings that have only just been made are still being paid for.
+	if confirmed, err := handlers.SettlePayments(time.Now().UTC()); err != nil || confirmed != 0 {
+		t.Errorf("wrong result settling new bookings, got %d, %v", confirmed, err)
+	}
+
+	// The first payment went through at the gateway before it timed out, and the second is declined.
+	if _, err := gateway.Charge(bookings.Charge{Reference: paid.Id, Amount: *paid.Price, Currency: paid.Currency}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gateway.Decline = true
+
+	if confirmed, err := handlers.SettlePayments(time.Now().UTC().Add(10 * time.Minute)); err != nil || confirmed != 1 {
+		t.Errorf("wrong result settling payments, got %d, %v", confirmed, err)
+	}
+
+	if taken := gateway.Payments(); len(taken) != 1 {
+		t.Errorf("payment taken more than once, got %+v", taken)
+	}
+
+	got, _ := repo.Get(paid.Id)
+	if got.Status != bookings.StatusConfirmed || len(got.PaymentId) == 0 {
+		t.Errorf("booking that was paid for should be confirmed, got %+v", got)
+	}
+
+	got, _ = repo.Get(declined.Id)
+	if got.Status != bookings.StatusPaymentFailed || !got.Deleted {
+		t.Errorf("booking whose payment was declined should be released, got %+v", got)
+	}
+}
+
+func TestServer_DeleteRefund(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gateway := payments.NewFake()
+	handlers := NewBookingHandlers(repo, nil, "", gateway, bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("DELETE /api/v1/booking/{id}", handlers.Delete)
+
+	// Paid for a flight 10 days away, so the default policy refunds 75%.
+	departure := time.Now().UTC().Add(10 * 24 * time.Hour)
+	price := int64(25000000)
+	booking, err := repo.Create(bookings.Booking{
+		Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+		LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+		DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+		LaunchDate:    bookings.Today(departure),
+		DepartureAt:   &departure,
+		Price:         &price,
+		Currency:      "USD",
+		Status:        bookings.StatusConfirmed,
+		PaymentId:     "pay-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{
+			name: "1. Cancelled and refunded according to the policy",
+			want: `"status":"cancelled","payment_id":"pay-1","cancellation_reason":"customer","refund_amount":18750000,"refund_id":"`,
+		},
+		{
+			name: "2. Already cancelled",
+			want: `{"Status": "ID not recognised"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/booking/"+booking.Id, nil))
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), tt.want)
+			}
+		})
+	}
+
+	refunds := gateway.Refunds()
+	if len(refunds) != 1 || refunds[0].PaymentId != "pay-1" || refunds[0].Amount != 18750000 {
+		t.Errorf("wrong refunds, got %+v", refunds)
+	}
+
+	// The same flight after SpaceX scheduled a launch that conflicts with it, which is refunded in full.
+	disrupted, err := repo.Create(bookings.Booking{
+		Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+		LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+		DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+		LaunchDate:    bookings.Today(departure),
+		DepartureAt:   &departure,
+		Price:         &price,
+		Currency:      "USD",
+		Status:        bookings.StatusDisrupted,
+		PaymentId:     "pay-2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/booking/"+disrupted.Id, nil))
+
+	want := `"status":"cancelled","payment_id":"pay-2","cancellation_reason":"spacex_conflict","refund_amount":25000000,"refund_id":"`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), want)
+	}
+}
+
+// slowRefunds counts the refunds it's asked to make, and takes a while over each so cancellations overlap.
+type slowRefunds struct {
+	*payments.Fake
+	calls atomic.Int32
+}
+
+func (g *slowRefunds) Refund(refund bookings.Refund) (*bookings.Refund, error) {
+	g.calls.Add(1)
+	time.Sleep(10 * time.Millisecond)
+	return g.Fake.Refund(refund)
+}
+
+func TestServer_DeleteRefund_Concurrent(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gateway := &slowRefunds{Fake: payments.NewFake()}
+	handlers := NewBookingHandlers(repo, nil, "", gateway, bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("DELETE /api/v1/booking/{id}", handlers.Delete)
+
+	departure := time.Now().UTC().Add(40 * 24 * time.Hour)
+	price := int64(25000000)
+	create := func(status, paymentId string) *bookings.Booking {
+		booking, err := repo.Create(bookings.Booking{
+			Customer:      passenger,
+			LaunchPadId:   capeCanaveralId,
+			DestinationId: moonId,
+			LaunchDate:    bookings.Today(departure),
+			DepartureAt:   &departure,
+			Price:         &price,
+			Currency:      "USD",
+			Status:        status,
+			PaymentId:     paymentId,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return booking
+	}
+
+	// The customer cancels several times at once, and is only refunded once.
+	booking := create(bookings.StatusConfirmed, "pay-1")
+
+	var wg sync.WaitGroup
+	var cancelled atomic.Int32
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/booking/"+booking.Id, nil))
+			if strings.Contains(w.Body.String(), `"status":"cancelled"`) {
+				cancelled.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cancelled.Load() != 1 || gateway.calls.Load() != 1 {
+		t.Errorf("wrong cancellations, got %d cancelled and %d refunds, want 1 of each", cancelled.Load(), gateway.calls.Load())
+	}
+
+	// A booking whose payment hasn't been settled might have been paid for, so it can't be cancelled yet.
+	pending := create(bookings.StatusPending, "")
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/booking/"+pending.Id, nil))
+	if want := `{"Status": "Booking cannot be cancelled until its payment is settled"}`; w.Body.String() != want {
+		t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), want)
+	}
+
+	// A refund that fails leaves the booking claimed, and it's cancelled once the refund is made.
+	unrefunded := create(bookings.StatusConfirmed, "pay-2")
+	gateway.RefundErr = errors.New("timed out")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/booking/"+unrefunded.Id, nil))
+	if want := `{"Status": "Cancellation in progress, the booking will be cancelled once it has been refunded"}`; w.Body.String() != want {
+		t.Errorf("handler returned unexpected body: got %v want %v", w.Body.String(), want)
+	}
+
+	got, err := repo.Get(unrefunded.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != bookings.StatusCancelling || got.Deleted {
+		t.Errorf("booking should be waiting to be cancelled, got %+v", got)
+	}
+
+	gateway.RefundErr = nil
+	settled, err := handlers.SettleCancellations(time.Now().UTC().Add(time.Hour))
+	if settled != 1 || err != nil {
+		t.Errorf("wrong settlement, got %d, %v", settled, err)
+	}
+
+	got, err = repo.Get(unrefunded.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != bookings.StatusCancelled || !got.Deleted || got.RefundAmount == nil || *got.RefundAmount != price {
+		t.Errorf("booking should be cancelled and refunded in full, got %+v", got)
+	}
+}
+
+func TestServer_Waitlist(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spaceXLaunches := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"totalDocs": %d}`, spaceXLaunches))),
+			Header:     make(http.Header),
+		}
+	})
+
+	// A launchpad with a single seat, flying to the Moon on Mondays.
+	launchPad, err := repo.CreateLaunchPad(bookings.LaunchPad{FullName: "Tiny Pad", SpaceXLaunchPadId: "5e9e4502f5090927f8566f99", SeatCapacity: 1, Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.CreateScheduleEntry(bookings.ScheduleEntry{LaunchPadId: launchPad.Id, DayOfWeek: "Monday", DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The waitlist promoter only looks at flights from today onwards, so the flights are on the next two Mondays.
+	monday := time.Now().UTC().AddDate(0, 0, 1)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	nextMonday := monday.AddDate(0, 0, 7)
+
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+	mux.HandleFunc("DELETE /api/v1/booking/{id}", handlers.Delete)
+	mux.HandleFunc("GET /api/v1/waitlist/{id}", handlers.GetWaitlistEntry)
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return w
+	}
+
+	booking := func(firstName string, launchDate time.Time) string {
+		return `{"first_name": "` + firstName + `", "last_name": "Thomson", "gender": "Female", "birthday": "1980-04-12", "launch_pad_id": "` +
+			launchPad.Id + `", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "` + launchDate.Format(time.DateOnly) + `"}`
+	}
+
+	join := func(firstName string, launchDate time.Time, wantStatus string) bookings.WaitlistEntry {
+		t.Helper()
+
+		var response waitlistResponse
+		w := serve(http.MethodPost, "/api/v1/booking?waitlist=true", booking(firstName, launchDate))
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Status != wantStatus {
+			t.Fatalf("%s didn't join the waitlist, got %+v, %v", firstName, response, err)
+		}
+
+		return response.WaitlistEntry
+	}
+
+	entry := func(id string) bookings.WaitlistEntry {
+		t.Helper()
+
+		var got bookings.WaitlistEntry
+		if err := json.NewDecoder(serve(http.MethodGet, "/api/v1/waitlist/"+id, "").Body).Decode(&got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return got
+	}
+
+	// Bookings unmarshal dates as YYYY-MM-DD, so only the id is decoded.
+	var first struct {
+		Id string `json:"id"`
+	}
+	if err := json.NewDecoder(serve(http.MethodPost, "/api/v1/booking", booking("Ian", monday)).Body).Decode(&first); err != nil || len(first.Id) == 0 {
+		t.Fatalf("booking not created, got %v", err)
+	}
+
+	if w := serve(http.MethodPost, "/api/v1/booking", booking("Ann", monday)); w.Body.String() != `{"Status": "Flight cancelled, no seats remaining on this flight"}` {
+		t.Errorf("customer joined the waitlist without asking, got %v", w.Body.String())
+	}
+
+	jane := join("Jane", monday, "Flight full, added to the waitlist")
+	bob := join("Bob", monday, "Flight full, added to the waitlist")
+
+	// Cancelling the booking gives its seat to the first customer on the waitlist.
+	serve(http.MethodDelete, "/api/v1/booking/"+first.Id, "")
+
+	promoted := entry(jane.Id)
+	if promoted.Status != bookings.WaitlistPromoted || len(promoted.BookingId) == 0 {
+		t.Fatalf("first customer not promoted, got %+v", promoted)
+	}
+
+	got, err := repo.Get(promoted.BookingId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.FirstName != "Jane" || got.Status != bookings.StatusConfirmed {
+		t.Errorf("wrong promoted booking, got %+v", got)
+	}
+
+	if waiting := entry(bob.Id); waiting.Status != bookings.WaitlistWaiting {
+		t.Errorf("second customer promoted onto a full flight, got %+v", waiting)
+	}
+
+	// The following Monday's flight overlaps with a SpaceX launch until the launch moves.
+	spaceXLaunches = 1
+	ann := join("Ann", nextMonday, "Flight overlaps with SpaceX launch, added to the waitlist")
+
+	if promoted, err := handlers.PromoteWaitlists(time.Now()); err != nil || promoted != 0 {
+		t.Errorf("wrong promotions while the flight conflicts, got %d, %v", promoted, err)
+	}
+
+	spaceXLaunches = 0
+	if promoted, err := handlers.PromoteWaitlists(time.Now()); err != nil || promoted != 1 {
+		t.Errorf("wrong promotions once the conflict has gone, got %d, %v", promoted, err)
+	}
+
+	if promoted := entry(ann.Id); promoted.Status != bookings.WaitlistPromoted {
+		t.Errorf("customer not promoted once the conflict had gone, got %+v", promoted)
+	}
+
+	if waiting := entry(bob.Id); waiting.Status != bookings.WaitlistWaiting {
+		t.Errorf("customer promoted onto a full flight, got %+v", waiting)
+	}
+
+	// The Monday after that overlaps with a SpaceX launch too, and once the launch moves the flight is cancelled.
+	lastMonday := bookings.Today(nextMonday.AddDate(0, 0, 7))
+	spaceXLaunches = 1
+	carl := join("Carl", lastMonday, "Flight overlaps with SpaceX launch, added to the waitlist")
+	spaceXLaunches = 0
+	if _, err := repo.CreateScheduleException(bookings.ScheduleException{Kind: bookings.ExceptionCancelledFlight, LaunchPadId: launchPad.Id,
+		Direction: bookings.DirectionOutbound, StartDate: lastMonday, EndDate: lastMonday}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if promoted, err := handlers.PromoteWaitlists(time.Now()); err != nil || promoted != 0 {
+		t.Errorf("wrong promotions once the flight is cancelled, got %d, %v", promoted, err)
+	}
+
+	if cancelled := entry(carl.Id); cancelled.Status != bookings.WaitlistFlightUnavailable {
+		t.Errorf("customer still waiting for a cancelled flight, got %+v", cancelled)
+	}
+
+	if w := serve(http.MethodGet, "/api/v1/waitlist/missing", ""); w.Body.String() != `{"Status": "ID not recognised"}` {
+		t.Errorf("handler returned unexpected body: got %v", w.Body.String())
+	}
+}
+
+func TestServer_Waitlist_PaymentPending(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	// The payment provider times out, so payments can't be confirmed.
+	gateway := payments.NewFake()
+	gateway.Err = errors.New("timed out")
+	handlers := NewBookingHandlers(repo, client, "", gateway, bookings.DefaultRefundPolicy)
+
+	monday := time.Now().UTC().AddDate(0, 0, 1)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	monday = bookings.Today(monday)
+
+	var entries []*bookings.WaitlistEntry
+	for _, firstName := range []string{"Jane", "Bob"} {
+		entry, err := repo.CreateWaitlistEntry(bookings.NewWaitlistEntry(bookings.Booking{
+			Customer:      bookings.Customer{FirstName: firstName, LastName: "Thomson", Gender: "Female", Birthday: time.Date(1980, 4, 12, 0, 0, 0, 0, time.UTC)},
+			LaunchPadId:   capeCanaveralId,
+			DestinationId: moonId,
+			LaunchDate:    monday,
+			Direction:     bookings.DirectionOutbound,
+		}, bookings.WaitlistFlightFull))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	// A payment that isn't confirmed doesn't stop the next customer being promoted.
+	if promoted, err := handlers.PromoteWaitlists(time.Now()); err != nil || promoted != 2 {
+		t.Errorf("wrong promotions while payments are pending, got %d, %v", promoted, err)
+	}
+
+	for _, entry := range entries {
+		got, err := repo.GetWaitlistEntry(entry.Id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Status != bookings.WaitlistPromoted {
+			t.Errorf("customer not promoted, got %+v", got)
+			continue
+		}
+
+		booking, err := repo.Get(got.BookingId)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if booking.Status != bookings.StatusPending {
+			t.Errorf("wrong status for a booking whose payment isn't confirmed, got %q want %q", booking.Status, bookings.StatusPending)
+		}
+	}
+}
+
+func TestServer_PostAlternatives(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// SpaceX are launching from Cape Canaveral on 2030-12-09, local time.
+	newYork, _ := time.LoadLocation("America/New_York")
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		var request bookings.SpaceXLaunchesRequest
+		json.NewDecoder(req.Body).Decode(&request)
+
+		totalDocs := 0
+		if request.Query.LaunchPad == "5e9e4501f509094ba4566f84" && request.Query.DateUtc.Gte.In(newYork).Format(time.DateOnly) == "2030-12-09" {
+			totalDocs = 1
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"totalDocs": %d}`, totalDocs))),
+			Header:     make(http.Header),
+		}
+	})
+
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking", handlers.Post)
+
+	// The first Tuesday after today at Cape Canaveral, so the Monday a week before it has already passed.
+	tuesday := bookings.Today(time.Now().In(newYork)).AddDate(0, 0, 1)
+	for tuesday.Weekday() != time.Tuesday {
+		tuesday = tuesday.AddDate(0, 0, 1)
+	}
+	day := func(days int) string {
+		return tuesday.AddDate(0, 0, days).Format(time.DateOnly)
+	}
+
+	tests := []struct {
+		name             string
+		launchDate       string
+		wantStatus       string
+		wantAlternatives []string
+	}{
+		{
+			// Cape Canaveral flies to the Moon on Mondays, but not on the 9th, and South Texas on Tuesdays.
+			name:             "1. Wrong day of the week",
+			launchDate:       "2030-12-10",
+			wantStatus:       "Flight cancelled, this launchpad does not fly to the destination on the requested day",
+			wantAlternatives: []string{"b542c0cf 2030-12-16", "b542c0cf 2030-12-02", "b542c0cf 2030-12-23", "b09e0b80 2030-12-10"},
+		},
+		{
+			// Nobody else flies to the Moon on Mondays.
+			name:             "2. SpaceX conflict",
+			launchDate:       "2030-12-09",
+			wantStatus:       "Flight cancelled, overlaps with SpaceX launch",
+			wantAlternatives: []string{"b542c0cf 2030-12-02", "b542c0cf 2030-12-16", "b542c0cf 2030-11-25"},
+		},
+		{
+			name:             "3. Dates that have passed aren't suggested",
+			launchDate:       day(0),
+			wantStatus:       "Flight cancelled, this launchpad does not fly to the destination on the requested day",
+			wantAlternatives: []string{"b542c0cf " + day(-1), "b542c0cf " + day(6), "b542c0cf " + day(13), "b09e0b80 " + day(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"first_name": "Ian", "last_name": "Thomson", "gender": "Male", "birthday": "1980-04-12", "launch_pad_id": "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975", "destination_id": "466fc378-14eb-4ed9-8bec-d29abe54c5a9", "launch_date": "` + tt.launchDate + `"}`
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/booking", strings.NewReader(body)))
+
+			var response rejectionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if response.Status != tt.wantStatus {
+				t.Errorf("wrong status, got %q want %q", response.Status, tt.wantStatus)
+			}
+
+			var got []string
+			for _, alternative := range response.Alternatives {
+				got = append(got, alternative.LaunchPadId[:8]+" "+alternative.LaunchDate.Format(time.DateOnly))
+			}
+
+			if strings.Join(got, ", ") != strings.Join(tt.wantAlternatives, ", ") {
+				t.Errorf("wrong alternatives, got %v want %v", got, tt.wantAlternatives)
+			}
+		})
+	}
+}
+
+func TestServer_FindAlternatives_SlowSpaceX(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Every SpaceX request takes 100ms.
+	var calls atomic.Int32
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"totalDocs": 0}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	handlers := NewBookingHandlers(repo, client, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+
+	// Tuesday, Cape Canaveral only flies to the Moon on Mondays.
+	launchDate, _ := time.Parse(time.DateOnly, "2030-12-10")
+	booking := bookings.Booking{LaunchPadId: capeCanaveralId, DestinationId: moonId, LaunchDate: launchDate,
+		Direction: bookings.DirectionOutbound}
+
+	t.Run("1. Checked at the same time", func(t *testing.T) {
+		calls.Store(0)
+		start := time.Now()
+
+		alternatives, err := handlers.findAlternatives(context.Background(), booking)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("alternatives took too long, got %v", elapsed)
+		}
+		if got := calls.Load(); got > maxAlternativeChecks {
+			t.Errorf("too many SpaceX checks, got %d want at most %d", got, maxAlternativeChecks)
+		}
+		if len(alternatives) != bookings.MaxAlternativeDates+1 {
+			t.Errorf("wrong number of alternatives, got %d want %d", len(alternatives), bookings.MaxAlternativeDates+1)
+		}
+	})
+
+	t.Run("2. Not checked in time", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+
+		alternatives, err := handlers.findAlternatives(ctx, booking)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Errorf("alternatives didn't stop at the deadline, took %v", elapsed)
+		}
+		if len(alternatives) != 0 {
+			t.Errorf("alternatives not checked against SpaceX were suggested, got %v", alternatives)
+		}
+	})
+}
+
+func TestServer_BoardingPass(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handlers := NewBookingHandlers(repo, nil, "", payments.NewFake(), bookings.DefaultRefundPolicy)
+	handlers.BoardingPassKey = key
+	mux := &http.ServeMux{}
+	mux.HandleFunc("POST /api/v1/booking/{id}/check-in", handlers.PostCheckIn)
+	mux.HandleFunc("GET /api/v1/booking/{id}/boarding-pass", handlers.GetBoardingPass)
+	mux.HandleFunc("GET /api/v1/boarding/key", handlers.GetBoardingPassKey)
+	mux.HandleFunc("POST /api/v1/boarding/verify", handlers.PostBoardingVerify)
+
+	now := time.Now().UTC()
+
+	create := func(departureAt time.Time, status string) *bookings.Booking {
+		launchDate := bookings.Today(departureAt)
+		accessToken, err := bookings.NewAccessToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		booking, err := repo.Create(bookings.Booking{
+			Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+			LaunchPadId:   "b542c0cf-7fe3-4bb1-a63f-7cbdf8359975",
+			DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+			LaunchDate:    launchDate,
+			DepartureAt:   &departureAt,
+			Status:        status,
+			AccessToken:   accessToken,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return booking
+	}
+
+	// withToken adds the access token to the request as a bearer token.
+	withToken := func(req *http.Request, accessToken string) *http.Request {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		return req
+	}
+
+	confirmed := create(now.Add(2*time.Hour), bookings.StatusConfirmed)
+	nextWeek := create(now.AddDate(0, 0, 7), bookings.StatusConfirmed)
+	pending := create(now.Add(2*time.Hour), bookings.StatusPending)
+
+	sign := func(booking *bookings.Booking) string {
+		pass, err := bookings.NewBoardingPass(*booking)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return pass.Sign(key)
+	}
+	token := sign(confirmed)
+
+	tests := []struct {
+		name            string
+		req             *http.Request
+		want            string
+		wantStatusCode  int
+		wantContentType string
+	}{
+		{
+			name:            "1. PNG of the QR code",
+			req:             withToken(httptest.NewRequest(http.MethodGet, "/api/v1/booking/"+confirmed.Id+"/boarding-pass", nil), confirmed.AccessToken),
+			want:            "\x89PNG",
+			wantStatusCode:  200,
+			wantContentType: "image/png",
+		},
+		{
+			name:            "2. Printable PDF",
+			req:             withToken(httptest.NewRequest(http.MethodGet, "/api/v1/booking/"+confirmed.Id+"/boarding-pass?format=pdf", nil), confirmed.AccessToken),
+			want:            "%PDF-",
+			wantStatusCode:  200,
+			wantContentType: "application/pdf",
+		},
+		{
+			name:            "3. Booking not confirmed, returns 409",
+			req:             withToken(httptest.NewRequest(http.MethodGet, "/api/v1/booking/"+pending.Id+"/boarding-pass", nil), pending.AccessToken),
+			want:            `{"Status":"Boarding passes are only issued for confirmed and checked in bookings"}`,
+			wantStatusCode:  409,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "3a. Without the access token, returns 401",
+			req:             httptest.NewRequest(http.MethodGet, "/api/v1/booking/"+confirmed.Id+"/boarding-pass", nil),
+			want:            `{"Status":"Access token not recognised"}`,
+			wantStatusCode:  401,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "3b. With another booking's access token, returns 401",
+			req:             withToken(httptest.NewRequest(http.MethodGet, "/api/v1/booking/"+confirmed.Id+"/boarding-pass", nil), nextWeek.AccessToken),
+			want:            `{"Status":"Access token not recognised"}`,
+			wantStatusCode:  401,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "4. Unknown booking, returns 404",
+			req:             httptest.NewRequest(http.MethodGet, "/api/v1/booking/nope/boarding-pass", nil),
+			want:            `{"Status":"ID not recognised"}`,
+			wantStatusCode:  404,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "5. Public key",
+			req:             httptest.NewRequest(http.MethodGet, "/api/v1/boarding/key", nil),
+			want:            `"public_key":"` + base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)) + `"`,
+			wantStatusCode:  200,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "6. Scanned before checking in, returns 409",
+			req:             httptest.NewRequest(http.MethodPost, "/api/v1/boarding/verify", strings.NewReader(`{"token": "`+token+`"}`)),
+			want:            `{"Status":"Passenger has not checked in"}`,
+			wantStatusCode:  409,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "7a. Checking in without the access token, returns 401",
+			req:             httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+confirmed.Id+"/check-in", nil),
+			want:            `{"Status":"Access token not recognised"}`,
+			wantStatusCode:  401,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "7b. Checking in with another booking's access token, returns 401",
+			req:             withToken(httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+confirmed.Id+"/check-in", nil), nextWeek.AccessToken),
+			want:            `{"Status":"Access token not recognised"}`,
+			wantStatusCode:  401,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "7c. Checked in",
+			req:             withToken(httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+confirmed.Id+"/check-in", nil), confirmed.AccessToken),
+			want:            `"status":"checked_in"`,
+			wantStatusCode:  200,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "8. Checked in again, returns 409",
+			req:             withToken(httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+confirmed.Id+"/check-in", nil), confirmed.AccessToken),
+			want:            `{"Status":"Only confirmed bookings can check in"}`,
+			wantStatusCode:  409,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "9. Scanned at the gate, boarded",
+			req:             httptest.NewRequest(http.MethodPost, "/api/v1/boarding/verify", strings.NewReader(`{"token": "`+token+`"}`)),
+			want:            `{"Status":"Boarded","booking":{"id":"` + confirmed.Id + `"`,
+			wantStatusCode:  200,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "10. Scanned again, already boarded",
+			req:             httptest.NewRequest(http.MethodPost, "/api/v1/boarding/verify", strings.NewReader(`{"token": "`+token+`"}`)),
+			want:            `{"Status":"Already boarded","booking":{"id":"` + confirmed.Id + `"`,
+			wantStatusCode:  200,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "11. Tampered with, returns 400",
+			req:             httptest.NewRequest(http.MethodPost, "/api/v1/boarding/verify", strings.NewReader(`{"token": "x`+token+`"}`)),
+			want:            `{"Status":"Boarding pass is not valid"}`,
+			wantStatusCode:  400,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "12. Flight departs next week, check-in not open, returns 409",
+			req:             withToken(httptest.NewRequest(http.MethodPost, "/api/v1/booking/"+nextWeek.Id+"/check-in", nil), nextWeek.AccessToken),
+			want:            `{"Status":"Check-in has not opened for this flight"}`,
+			wantStatusCode:  409,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "13. Unknown booking checking in, returns 404",
+			req:             httptest.NewRequest(http.MethodPost, "/api/v1/booking/nope/check-in", nil),
+			want:            `{"Status":"ID not recognised"}`,
+			wantStatusCode:  404,
+			wantContentType: "application/json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, tt.req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("handler returned wrong content type: got %q want %q", got, tt.wantContentType)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("handler returned unexpected body: got %.200q want %v", w.Body.String(), tt.want)
+			}
+		})
+	}
+
+	boarded, err := repo.Get(confirmed.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if boarded.Status != bookings.StatusBoarded || boarded.CheckedInAt == nil || boarded.BoardedAt == nil {
+		t.Errorf("passenger not recorded as checked in and boarded, got %+v", boarded)
+	}
+
+	// Once boarded, the booking can't be cancelled.
+	if _, err := bookings.CancelBooking(repo, payments.NewFake(), bookings.DefaultRefundPolicy, confirmed.Id, bookings.CancelledByCustomer, now); !errors.Is(err, bookings.ErrInvalidTransition) {
+		t.Errorf("wrong error cancelling a boarded booking, got %v want %v", err, bookings.ErrInvalidTransition)
+	}
+
+	// Boarding closes when the flight departs.
+	nextWeekDeparture := *nextWeek.DepartureAt
+	if _, err := repo.MoveStatus(nextWeek.Id, bookings.StatusConfirmed, bookings.StatusCheckedIn, nextWeekDeparture.Add(-time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = bookings.Board(repo, key.Public().(ed25519.PublicKey), sign(nextWeek), bookings.DefaultCheckInOpens, nextWeekDeparture.Add(time.Minute))
+	if !errors.Is(err, bookings.ErrCheckInClosed) {
+		t.Errorf("wrong error boarding after departure, got %v want %v", err, bookings.ErrCheckInClosed)
+	}
+
+	// A pass issued before its booking was cancelled no longer boards.
+	if _, err := repo.Cancel(nextWeek.Id, bookings.CancelledByCustomer, nil, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = bookings.Board(repo, key.Public().(ed25519.PublicKey), sign(nextWeek), bookings.DefaultCheckInOpens, nextWeekDeparture.Add(-time.Hour))
+	if !errors.Is(err, bookings.ErrNotBoardable) {
+		t.Errorf("wrong error boarding a cancelled booking, got %v want %v", err, bookings.ErrNotBoardable)
+	}
+}
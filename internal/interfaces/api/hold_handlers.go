@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// PostHold holds a seat on the requested flight for the requested number of minutes, so the customer can give their
+// details without the flight becoming full in the meantime. The flight is checked the same way as a booking.
+func (b *BookingHandlers) PostHold(w http.ResponseWriter, r *http.Request) {
+	var request bookings.HoldRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	var validationErr bookings.ValidationError
+	if err := request.Validate(); errors.As(err, &validationErr) {
+		writeStatus(w, http.StatusBadRequest, validationErr.Reason)
+		return
+	}
+
+	flight := request.Flight()
+
+	if err := b.checkLaunchPad(flight); err != nil {
+		writeBookingResult(w, nil, err)
+		return
+	}
+
+	var hold *bookings.Hold
+	err = b.Booker.InTransaction(func(tx bookings.Booker) error {
+		if _, err := prepareFlight(tx, flight, 1); err != nil {
+			return err
+		}
+
+		var err error
+		hold, err = tx.CreateHold(request.NewHold(time.Now().UTC()))
+		return err
+	})
+
+	writeBookingResult(w, hold, err)
+}
+
+// PostHoldConfirm books the customer in the request body on the held flight, releasing the hold, and prices their seat
+// with the optional promo code. Expired holds can't be confirmed.
+func (b *BookingHandlers) PostHoldConfirm(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Gender    string `json:"gender"`
+		Birthday  string `json:"birthday"`
+		Email     string `json:"email"`
+		Phone     string `json:"phone"`
+		PromoCode string `json:"promo_code"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	birthday, err := time.Parse(time.DateOnly, request.Birthday)
+	if err != nil {
+		log.Println(fmt.Errorf("invalid date format. Use YYYY-MM-DD: %w", err))
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	customer := bookings.Customer{
+		FirstName: request.FirstName,
+		LastName:  request.LastName,
+		Gender:    request.Gender,
+		Birthday:  birthday,
+		Email:     request.Email,
+		Phone:     request.Phone,
+	}
+
+	var newBooking *bookings.Booking
+	err = b.Booker.InTransaction(func(tx bookings.Booker) error {
+		hold, err := tx.GetHold(r.PathValue("id"))
+		if err != nil {
+			return err
+		}
+
+		if hold.Expired(time.Now().UTC()) {
+			return bookings.ErrHoldExpired
+		}
+
+		// The hold's seat is released before booking, so the seat check doesn't count it twice.
+		if _, err := tx.DeleteHold(hold.Id); err != nil {
+			return err
+		}
+
+		booking := hold.Booking(customer)
+		booking.PromoCode = request.PromoCode
+
+		newBooking, err = createBooking(tx, booking)
+		return err
+	})
+	if err == nil {
+		newBooking, err = b.payFor(newBooking)
+	}
+	if errors.Is(err, bookings.ErrNotFound) {
+		log.Println(err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "ID not recognised"}`))
+		return
+	}
+
+	writeBookingResult(w, newBooking, err)
+}
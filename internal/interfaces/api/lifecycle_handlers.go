@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// PostCheckIn checks the passenger in for their flight. The request needs the booking's access token as a bearer
+// token. Check-in opens CheckInOpens before departure and closes when the flight departs, and only confirmed bookings
+// can check in. The booking is returned without the customer's contact details.
+func (b *BookingHandlers) PostCheckIn(w http.ResponseWriter, r *http.Request) {
+	booking, err := b.getOwnBooking(r)
+
+	var checkedIn *bookings.Booking
+	if err == nil {
+		checkedIn, err = bookings.MoveBooking(b.Booker, booking.Id, bookings.StatusCheckedIn, b.CheckInOpens, time.Now().UTC())
+	}
+
+	switch {
+	case errors.Is(err, bookings.ErrNotFound):
+		writeStatus(w, http.StatusNotFound, "ID not recognised")
+	case errors.Is(err, bookings.ErrAccessTokenInvalid):
+		writeAccessTokenInvalid(w)
+	case errors.Is(err, bookings.ErrInvalidTransition):
+		writeStatus(w, http.StatusConflict, "Only confirmed bookings can check in")
+	case errors.Is(err, bookings.ErrCheckInNotOpen):
+		writeStatus(w, http.StatusConflict, "Check-in has not opened for this flight")
+	case errors.Is(err, bookings.ErrCheckInClosed):
+		writeStatus(w, http.StatusConflict, "Check-in has closed, the flight has departed")
+	case err != nil:
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+	default:
+		writeJSON(w, http.StatusOK, publicBooking(*checkedIn))
+	}
+}
+
+// PostBookingStatus moves a booking to the status in the request, e.g. to mark passengers as flown or as no-shows once
+// their flight has departed. Only legal moves within their time window are allowed, see bookings.CheckMove.
+func (a *AdminHandlers) PostBookingStatus(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: err.Error()})
+		return
+	}
+
+	if err := bookings.ValidateLifecycleStatus(request.Status); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	moved, err := bookings.MoveBooking(a.Booker, r.PathValue("id"), request.Status, a.CheckInOpens, time.Now().UTC())
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, moved)
+}
+
+// getOwnBooking returns the booking with the id in the request's path, or bookings.ErrAccessTokenInvalid unless the
+// request has the booking's access token as a bearer token. Booking ids are public, so the access token is what shows
+// the request comes from the customer who made the booking.
+func (b *BookingHandlers) getOwnBooking(r *http.Request) (*bookings.Booking, error) {
+	booking, err := b.Booker.Get(r.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = ""
+	}
+
+	if err := booking.CheckAccessToken(token); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// writeAccessTokenInvalid responds that the request didn't have the booking's access token.
+func writeAccessTokenInvalid(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeStatus(w, http.StatusUnauthorized, "Access token not recognised")
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// The formats a manifest can be downloaded in, set by the format query parameter.
+const (
+	manifestJSON = "json"
+	manifestCSV  = "csv"
+	manifestPDF  = "pdf"
+)
+
+// GetManifest returns the manifest of confirmed passengers launching from the launchpad on the date, as JSON, or as a
+// CSV or PDF download when the format query parameter is csv or pdf.
+func (a *AdminHandlers) GetManifest(w http.ResponseWriter, r *http.Request) {
+	launchDate, err := time.Parse(time.DateOnly, r.PathValue("date"))
+	if err != nil {
+		writeAdminError(w, bookings.ValidationError{Reason: "invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = manifestJSON
+	case manifestJSON, manifestCSV, manifestPDF:
+	default:
+		writeAdminError(w, bookings.ValidationError{Reason: "format must be json, csv or pdf"})
+		return
+	}
+
+	launchPad, err := a.Booker.GetLaunchPad(r.PathValue("launchpad_id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	upcoming, err := a.Booker.GetUpcoming(launchPad.Id, launchDate)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	destinations, err := a.Booker.GetDestinations()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	manifest := bookings.NewManifest(*launchPad, launchDate, upcoming, destinations)
+	filename := fmt.Sprintf("manifest-%s-%s.%s", launchPad.Id, launchDate.Format(time.DateOnly), format)
+
+	switch format {
+	case manifestCSV:
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		err = writeManifestCSV(w, manifest)
+	case manifestPDF:
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		err = writeManifestPDF(w, manifest)
+	default:
+		writeJSON(w, http.StatusOK, manifest)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// writeManifestCSV writes a header row, then a row for each passenger.
+func writeManifestCSV(w http.ResponseWriter, manifest bookings.Manifest) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{{"booking_id", "last_name", "first_name", "gender", "age_at_launch", "destination_id", "destination", "status"}}
+	for _, passenger := range manifest.Passengers {
+		rows = append(rows, []string{
+			passenger.BookingId,
+			passenger.LastName,
+			passenger.FirstName,
+			passenger.Gender,
+			strconv.Itoa(passenger.AgeAtLaunch),
+			passenger.DestinationId,
+			passenger.Destination,
+			passenger.Status,
+		})
+	}
+
+	return writer.WriteAll(rows)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"codeberg.org/go-pdf/fpdf"
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// The layout of a manifest PDF, in points. Pages are A4 landscape.
+const (
+	pdfPageWidth    = 842
+	pdfMargin       = 40
+	pdfLineHeight   = 16
+	pdfRowsPerPage  = 28
+	pdfFontSize     = 10
+	pdfTitleSize    = 16
+	pdfTitleSpacing = 24
+)
+
+// pdfColumns are the x positions of the manifest's columns.
+var pdfColumns = []float64{pdfMargin, 280, 360, 410, 520, 590}
+
+// writeManifestPDF writes the manifest as a PDF with a page for every pdfRowsPerPage passengers, in the built-in
+// Helvetica fonts.
+func writeManifestPDF(w io.Writer, manifest bookings.Manifest) error {
+	title := fmt.Sprintf("Manifest: %s, %s", manifest.LaunchPad, manifest.LaunchDate.Format("Monday 2 January 2006"))
+	header := []string{"Name", "Gender", "Age", "Destination", "Status", "Booking"}
+
+	var rows [][]string
+	for _, passenger := range manifest.Passengers {
+		rows = append(rows, []string{
+			passenger.LastName + ", " + passenger.FirstName,
+			passenger.Gender,
+			strconv.Itoa(passenger.AgeAtLaunch),
+			passenger.Destination,
+			passenger.Status,
+			passenger.BookingId,
+		})
+	}
+
+	pages := max((len(rows)+pdfRowsPerPage-1)/pdfRowsPerPage, 1)
+
+	pdf := fpdf.New("L", "pt", "A4", "")
+	pdf.SetProducer("spacetickets", false)
+	// The built-in Helvetica fonts are in Windows-1252, so names are translated from UTF-8.
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for page := 0; page < pages; page++ {
+		pdf.AddPage()
+		y := float64(pdfMargin + pdfTitleSize)
+
+		pdf.SetFont("Helvetica", "B", pdfTitleSize)
+		pdf.Text(pdfMargin, y, translate(title))
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+		pdf.Text(pdfPageWidth-pdfMargin-150, y, fmt.Sprintf("%d passengers, page %d of %d", len(rows), page+1, pages))
+		y += pdfTitleSpacing
+
+		pdf.SetFont("Helvetica", "B", pdfFontSize)
+		for i, cell := range header {
+			pdf.Text(pdfColumns[i], y, cell)
+		}
+
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+		end := min((page+1)*pdfRowsPerPage, len(rows))
+		for _, row := range rows[page*pdfRowsPerPage : end] {
+			y += pdfLineHeight
+			for i, cell := range row {
+				pdf.Text(pdfColumns[i], y, translate(cell))
+			}
+		}
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"log"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// notify sends the notification of the kind about each booking. Notifications that can't be sent are logged, so the
+// change they're about still succeeds.
+func notify(booker bookings.Booker, notifier bookings.Notifier, kind string, changed ...bookings.Booking) {
+	for _, booking := range changed {
+		if err := bookings.SendNotification(booker, notifier, kind, booking); err != nil {
+			log.Println(err)
+		}
+	}
+}
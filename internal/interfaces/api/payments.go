@@ -0,0 +1,182 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// payFor pays for a single booking with pay, using the booking's id as the payment reference.
+func (b *BookingHandlers) payFor(booking *bookings.Booking) (*bookings.Booking, error) {
+	paid, err := b.pay(booking.Id, []bookings.Booking{*booking})
+	if err != nil {
+		return nil, err
+	}
+
+	return &paid[0], nil
+}
+
+// pay takes one payment for bookings that have just been created as pending, moving them to paid, then confirms them
+// and tells their customers.
+// Bookings without a price are confirmed without a charge. If the payment is declined the bookings are released, so
+// their seats can be booked again, and bookings.ErrPaymentDeclined is returned. Any other error from the gateway
+// leaves them pending, since the payment may have been taken, and returns bookings.ErrPaymentPending so SettlePayments
+// can settle them later.
+// It's called once the transaction that created the bookings has committed, so the launchpad isn't locked while the
+// gateway takes the payment.
+func (b *BookingHandlers) pay(reference string, pending []bookings.Booking) ([]bookings.Booking, error) {
+	var paymentId string
+
+	if charge, ok := bookings.NewCharge(reference, pending); ok {
+		payment, err := b.Payments.Charge(charge)
+		if errors.Is(err, bookings.ErrPaymentDeclined) {
+			b.release(pending)
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %w", bookings.ErrPaymentPending, reference, err)
+		}
+
+		paymentId = payment.Id
+
+		if err := b.setStatus(pending, bookings.StatusPaid, paymentId); err != nil {
+			return nil, fmt.Errorf("payment %s taken but bookings not marked as paid: %w", paymentId, err)
+		}
+	}
+
+	if err := b.setStatus(pending, bookings.StatusConfirmed, ""); err != nil {
+		return nil, err
+	}
+
+	confirmed := make([]bookings.Booking, 0, len(pending))
+	for _, booking := range pending {
+		booking.Status = bookings.StatusConfirmed
+		booking.PaymentId = paymentId
+		confirmed = append(confirmed, booking)
+	}
+
+	notify(b.Booker, b.Notifier, bookings.NotifyConfirmed, confirmed...)
+
+	return confirmed, nil
+}
+
+// settlePaymentsAfter is how long a booking is left pending before SettlePayments settles it, so bookings that are
+// still being paid for are left alone.
+const settlePaymentsAfter = 5 * time.Minute
+
+// SettlePayments settles the payments of bookings made more than settlePaymentsAfter ago that are still pending,
+// because the gateway couldn't say whether they'd been paid for, or paid but not confirmed. Each booking, or group
+// booking, is charged again with the same reference, which returns the payment if it was taken, and then confirmed,
+// or released if the payment is declined. Bookings whose payment still can't be settled are left for the next run. It
+// returns how many bookings were confirmed.
+func (b *BookingHandlers) SettlePayments(now time.Time) (int, error) {
+	all, err := b.Booker.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	// Group bookings were paid for together, with their group id as the reference.
+	var references []string
+	pending := map[string][]bookings.Booking{}
+	for _, booking := range all {
+		unsettled := booking.Status == bookings.StatusPending || booking.Status == bookings.StatusPaid
+		if booking.Deleted || !unsettled || now.Sub(booking.CreatedAt) < settlePaymentsAfter {
+			continue
+		}
+
+		reference := booking.Id
+		if len(booking.GroupId) > 0 {
+			reference = booking.GroupId
+		}
+
+		if _, ok := pending[reference]; !ok {
+			references = append(references, reference)
+		}
+		pending[reference] = append(pending[reference], booking)
+	}
+
+	confirmed := 0
+	var errs []error
+	for _, reference := range references {
+		paid, err := b.pay(reference, pending[reference])
+		if errors.Is(err, bookings.ErrPaymentDeclined) {
+			log.Printf("Released %d bookings for %s after their payment was declined\n", len(pending[reference]), reference)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		confirmed += len(paid)
+	}
+
+	return confirmed, errors.Join(errs...)
+}
+
+// SettleCancellations finishes cancelling bookings claimed for cancellation more than settlePaymentsAfter ago, e.g.
+// because the gateway couldn't refund them when the customer cancelled. They're refunded as if they'd been cancelled
+// when they were claimed, and their customers are told and their seats offered to the waitlist as if they'd just been
+// cancelled. It returns how many bookings were cancelled.
+func (b *BookingHandlers) SettleCancellations(now time.Time) (int, error) {
+	all, err := b.Booker.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	var errs []error
+	for _, booking := range all {
+		if booking.Status != bookings.StatusCancelling || now.Sub(booking.UpdatedAt) < settlePaymentsAfter {
+			continue
+		}
+
+		finished, err := bookings.FinishCancellation(b.Booker, b.Payments, b.RefundPolicy, booking, booking.UpdatedAt)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		notify(b.Booker, b.Notifier, bookings.NotifyCancelled, *finished)
+		b.promoteWaitlist(*finished)
+		cancelled++
+	}
+
+	return cancelled, errors.Join(errs...)
+}
+
+// setStatus moves every booking to the status in one transaction.
+func (b *BookingHandlers) setStatus(pending []bookings.Booking, status, paymentId string) error {
+	return b.Booker.InTransaction(func(tx bookings.Booker) error {
+		for _, booking := range pending {
+			if _, err := tx.SetStatus(booking.Id, status, paymentId); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// release marks the bookings as failed payments and deletes them, freeing their seats.
+func (b *BookingHandlers) release(pending []bookings.Booking) {
+	err := b.Booker.InTransaction(func(tx bookings.Booker) error {
+		for _, booking := range pending {
+			if _, err := tx.SetStatus(booking.Id, bookings.StatusPaymentFailed, ""); err != nil {
+				return err
+			}
+
+			if _, err := tx.Delete(booking.Id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println(fmt.Errorf("could not release bookings after a failed payment: %w", err))
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// GetQuote prices a seat on a flight, given by the launch_pad_id, destination_id and launch_date query parameters.
+// The optional birthday parameter applies the child or senior discount, and promo_code applies a promo code.
+// The quote doesn't check the flight is running, that's done when it's booked.
+func (b *BookingHandlers) GetQuote(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	launchPadId, destinationId := query.Get("launch_pad_id"), query.Get("destination_id")
+	if len(launchPadId) == 0 || len(destinationId) == 0 || len(query.Get("launch_date")) == 0 {
+		writeStatus(w, http.StatusBadRequest, "launch_pad_id, destination_id and launch_date are required")
+		return
+	}
+
+	launchDate, err := time.Parse(time.DateOnly, query.Get("launch_date"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "invalid launch_date format. Use YYYY-MM-DD")
+		return
+	}
+
+	var birthday *time.Time
+	if value := query.Get("birthday"); len(value) > 0 {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			writeStatus(w, http.StatusBadRequest, "invalid birthday format. Use YYYY-MM-DD")
+			return
+		}
+		birthday = &date
+	}
+
+	fare, rules, promo, err := findPricing(b.Booker, launchPadId, destinationId, query.Get("promo_code"), time.Now().UTC())
+	switch {
+	case errors.Is(err, bookings.ErrPromoCodeInvalid):
+		writeStatus(w, http.StatusBadRequest, "Promo code is not valid")
+		return
+	case err != nil:
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	case fare == nil:
+		writeStatus(w, http.StatusNotFound, "No fare has been set for this flight")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bookings.NewQuote(*fare, rules, launchDate, birthday, promo))
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// errNotWaiting is returned when promoting a waitlist entry that's already been promoted, e.g. by a cancellation and
+// the waitlist promoter at the same time.
+var errNotWaiting = errors.New("waitlist entry is no longer waiting")
+
+// waitlistResponse is the waitlist entry a rejected customer has been given, and why they're waiting.
+type waitlistResponse struct {
+	Status        string                 `json:"Status"`
+	WaitlistEntry bookings.WaitlistEntry `json:"waitlist_entry"`
+}
+
+// GetWaitlistEntry returns a waitlist entry, without the customer's contact details, so the customer can see if they've
+// been given a seat.
+func (b *BookingHandlers) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	entry, err := b.Booker.GetWaitlistEntry(r.PathValue("id"))
+	if errors.Is(err, bookings.ErrNotFound) {
+		log.Println(err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Status": "ID not recognised"}`))
+		return
+	}
+	if entry != nil {
+		entry.Customer = publicCustomer(entry.Customer)
+	}
+
+	writeBookingResult(w, entry, err)
+}
+
+// joinWaitlist adds the entry to the end of its flight's waitlist. Customers with invalid contact details are rejected,
+// as they couldn't be booked when a seat comes free.
+func (b *BookingHandlers) joinWaitlist(w http.ResponseWriter, entry bookings.WaitlistEntry) {
+	if err := entry.Validate(); err != nil {
+		writeBookingResult(w, nil, err)
+		return
+	}
+
+	created, err := b.Booker.CreateWaitlistEntry(entry)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+
+	status := "Flight full, added to the waitlist"
+	if created.Reason == bookings.WaitlistSpaceXConflict {
+		status = "Flight overlaps with SpaceX launch, added to the waitlist"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(waitlistResponse{Status: status, WaitlistEntry: *created})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "an error occurred, see logs", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PromoteWaitlists promotes the customers waiting for flights launching from today onwards, flight by flight, and
+// returns how many were booked. It's run periodically, so seats freed up by expired holds, admin cancellations and
+// SpaceX launches that have moved go to the waitlist.
+func (b *BookingHandlers) PromoteWaitlists(now time.Time) (int, error) {
+	today := bookings.Today(now.UTC())
+
+	waiting, err := b.Booker.GetWaitlist(today)
+	if err != nil {
+		return 0, err
+	}
+
+	var flights []bookings.Booking
+	for _, entry := range waiting {
+		if !containsFlight(flights, entry) {
+			flights = append(flights, entry.Booking())
+		}
+	}
+
+	promoted := 0
+	var errs []error
+	for _, flight := range flights {
+		n, err := b.promoteFlight(waiting, flight)
+		promoted += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not promote waitlist for launchpad %s on %s: %w", flight.LaunchPadId,
+				flight.LaunchDate.Format(time.DateOnly), err))
+		}
+	}
+
+	return promoted, errors.Join(errs...)
+}
+
+// promoteWaitlist promotes the customers waiting for the flight of a booking that's just been cancelled. Errors are
+// logged, the waitlist promoter will try again later.
+func (b *BookingHandlers) promoteWaitlist(cancelled bookings.Booking) {
+	waiting, err := b.Booker.GetWaitlist(cancelled.LaunchDate)
+	if err == nil {
+		_, err = b.promoteFlight(waiting, cancelled)
+	}
+	if err != nil {
+		log.Println(fmt.Errorf("could not promote waitlist after cancelling booking %s: %w", cancelled.Id, err))
+	}
+}
+
+// promoteFlight books the entries waiting for the flight in the order they joined the waitlist, until the flight is
+// full or still overlaps with a SpaceX launch. Each booking is paid for as if the customer had just made it. If the
+// payment is declined the seat is released and offered to the next customer, and if it can't be confirmed yet the
+// booking is left pending for SettlePayments and the next customer is still promoted. An entry whose promo code is no
+// longer valid is booked at the full price. Entries whose customer is no longer eligible to fly, e.g. because they've
+// booked too many trips since joining, are skipped, and entries whose flight no longer goes ahead are marked
+// bookings.WaitlistFlightUnavailable. It returns how many entries were booked.
+func (b *BookingHandlers) promoteFlight(waiting []bookings.WaitlistEntry, flight bookings.Booking) (int, error) {
+	promoted := 0
+	checked := false
+
+	for _, entry := range waiting {
+		if !entry.ForFlight(flight) {
+			continue
+		}
+
+		booking := entry.Booking()
+
+		var err error
+		if !checked {
+			err = b.checkLaunchPad(booking)
+			if errors.Is(err, bookings.ErrSpaceXConflict) {
+				return promoted, nil
+			}
+			if err != nil && !bookings.FlightUnavailable(err) {
+				return promoted, err
+			}
+			checked = err == nil
+		}
+
+		var newBooking *bookings.Booking
+		if err == nil {
+			newBooking, err = b.promote(entry, booking)
+		}
+		if errors.Is(err, bookings.ErrPromoCodeInvalid) {
+			booking.PromoCode = ""
+			newBooking, err = b.promote(entry, booking)
+		}
+		if bookings.FlightUnavailable(err) {
+			if _, err := b.Booker.SetWaitlistStatus(entry.Id, bookings.WaitlistFlightUnavailable, ""); err != nil {
+				return promoted, err
+			}
+			log.Println(fmt.Errorf("waitlist entry %s can't be promoted: %w", entry.Id, err))
+			continue
+		}
+		if errors.Is(err, errNotWaiting) {
+			continue
+		}
+		if errors.Is(err, bookings.ErrIneligible) {
+			log.Println(fmt.Errorf("could not promote waitlist entry %s: %w", entry.Id, err))
+			continue
+		}
+		if errors.Is(err, bookings.ErrFlightFull) {
+			return promoted, nil
+		}
+		if err != nil {
+			return promoted, err
+		}
+
+		// A payment that isn't confirmed leaves the entry promoted, since SettlePayments may still confirm its booking,
+		// and the next entries are still promoted.
+		if _, payErr := b.payFor(newBooking); errors.Is(payErr, bookings.ErrPaymentDeclined) {
+			if _, err := b.Booker.SetWaitlistStatus(entry.Id, bookings.WaitlistPaymentFailed, ""); err != nil {
+				return promoted, err
+			}
+			continue
+		} else if errors.Is(payErr, bookings.ErrPaymentPending) {
+			log.Println(fmt.Errorf("waitlist entry %s promoted, but its payment isn't confirmed yet: %w", entry.Id, payErr))
+		} else if payErr != nil {
+			return promoted, payErr
+		}
+
+		promoted++
+	}
+
+	return promoted, nil
+}
+
+// promote books the entry's customer on its flight and marks the entry as promoted in one transaction. The launchpad
+// is locked before the entry is read, so an entry can't be promoted twice.
+func (b *BookingHandlers) promote(entry bookings.WaitlistEntry, booking bookings.Booking) (*bookings.Booking, error) {
+	var newBooking *bookings.Booking
+	err := b.Booker.InTransaction(func(tx bookings.Booker) error {
+		if _, err := tx.GetLaunchPad(entry.LaunchPadId); err != nil {
+			return err
+		}
+
+		current, err := tx.GetWaitlistEntry(entry.Id)
+		if err != nil {
+			return err
+		}
+
+		if current.Status != bookings.WaitlistWaiting {
+			return errNotWaiting
+		}
+
+		newBooking, err = createBooking(tx, booking)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.SetWaitlistStatus(entry.Id, bookings.WaitlistPromoted, newBooking.Id)
+		return err
+	})
+
+	return newBooking, err
+}
+
+// containsFlight returns true if one of the flights is the one the entry is waiting for.
+func containsFlight(flights []bookings.Booking, entry bookings.WaitlistEntry) bool {
+	for _, flight := range flights {
+		if entry.ForFlight(flight) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// HoldSweeper periodically deletes expired seat holds. Expired holds already don't count towards a flight's seats, so
+// the sweeper only stops them building up.
+type HoldSweeper struct {
+	Holds    bookings.Holds
+	Interval time.Duration
+}
+
+// NewHoldSweeper returns a new HoldSweeper that sweeps every interval.
+func NewHoldSweeper(holds bookings.Holds, interval time.Duration) HoldSweeper {
+	return HoldSweeper{Holds: holds, Interval: interval}
+}
+
+// Run sweeps expired holds every Interval until the context is cancelled.
+func (s HoldSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep(time.Now().UTC())
+		}
+	}
+}
+
+// Sweep deletes the holds that had expired by now. Errors are logged, so the next sweep can try again.
+func (s HoldSweeper) Sweep(now time.Time) {
+	rowsAffected, err := s.Holds.DeleteExpiredHolds(now)
+	if err != nil {
+		log.Printf("Failed to release expired holds: %v\n", err)
+		return
+	}
+
+	if rowsAffected > 0 {
+		log.Printf("Released %d expired holds\n", rowsAffected)
+	}
+}
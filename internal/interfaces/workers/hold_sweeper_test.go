@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/database"
+)
+
+func TestHoldSweeper_Sweep(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	launchDate, _ := time.Parse(time.DateOnly, "2010-12-06")
+
+	var ids []string
+	for _, expiresAt := range []time.Time{now.Add(-time.Minute), now.Add(time.Minute)} {
+		hold, err := repo.CreateHold(bookings.Hold{LaunchPadId: "pad", DestinationId: "moon", LaunchDate: launchDate, ExpiresAt: expiresAt})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, hold.Id)
+	}
+
+	NewHoldSweeper(repo, time.Minute).Sweep(now)
+
+	if _, err := repo.GetHold(ids[0]); err == nil {
+		t.Errorf("expired hold wasn't released")
+	}
+
+	if _, err := repo.GetHold(ids[1]); err != nil {
+		t.Errorf("unexpired hold was released: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// LaunchPadSyncer periodically syncs the launchpad catalogue with SpaceX's launchpads, so launchpads keep SpaceX's
+// names and the ones SpaceX retire can't be booked.
+type LaunchPadSyncer struct {
+	Booker   bookings.Booker
+	SpaceX   bookings.SpaceXLaunchPads
+	Interval time.Duration
+}
+
+// NewLaunchPadSyncer returns a new LaunchPadSyncer that syncs every interval.
+func NewLaunchPadSyncer(booker bookings.Booker, spaceX bookings.SpaceXLaunchPads, interval time.Duration) LaunchPadSyncer {
+	return LaunchPadSyncer{Booker: booker, SpaceX: spaceX, Interval: interval}
+}
+
+// Run syncs launchpads straight away, then every Interval until the context is cancelled.
+func (s LaunchPadSyncer) Run(ctx context.Context) {
+	s.Sync()
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sync()
+		}
+	}
+}
+
+// Sync syncs the launchpads and logs what changed, along with the launchpads that need mapping by an admin. Errors are
+// logged, so the next run can try again.
+func (s LaunchPadSyncer) Sync() {
+	report, err := bookings.SyncLaunchPads(s.Booker, s.SpaceX)
+	if err != nil {
+		log.Printf("Failed to sync launchpads: %v\n", err)
+	}
+
+	for _, launchPad := range report.Renamed {
+		log.Printf("Renamed launchpad %s to %q\n", launchPad.Id, launchPad.FullName)
+	}
+
+	for _, launchPad := range report.Retired {
+		log.Printf("Retired launchpad %s, SpaceX have retired %s\n", launchPad.Id, launchPad.SpaceXLaunchPadId)
+	}
+
+	for _, spaceXLaunchPad := range report.Unmapped {
+		log.Printf("SpaceX launchpad %s (%s) isn't mapped to a launchpad\n", spaceXLaunchPad.Id, spaceXLaunchPad.FullName)
+	}
+
+	for _, launchPad := range report.Unknown {
+		log.Printf("Launchpad %s maps to SpaceX launchpad %s, which SpaceX don't list\n", launchPad.Id, launchPad.SpaceXLaunchPadId)
+	}
+}
@@ -0,0 +1,96 @@
+package workers
+
+import (
+	"testing"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/database"
+)
+
+// spaceXLaunchPadsMock lists the launchpads in launchPads.
+type spaceXLaunchPadsMock struct {
+	launchPads []bookings.SpaceXLaunchPad
+}
+
+func (s spaceXLaunchPadsMock) GetSpaceXLaunchPads() ([]bookings.SpaceXLaunchPad, error) {
+	return s.launchPads, nil
+}
+
+func TestSyncLaunchPads(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	create := func(fullName, spaceXLaunchPadId string) *bookings.LaunchPad {
+		t.Helper()
+
+		launchPad, err := repo.CreateLaunchPad(bookings.LaunchPad{FullName: fullName, SpaceXLaunchPadId: spaceXLaunchPadId, SeatCapacity: 10, Timezone: "UTC"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return launchPad
+	}
+
+	drifted := create("Old Name", "spacex-drifted")
+	retired := create("Retired Pad", "spacex-retired")
+	unchanged := create("Unchanged Pad", "spacex-unchanged")
+
+	spaceX := spaceXLaunchPadsMock{launchPads: []bookings.SpaceXLaunchPad{
+		{Id: "spacex-drifted", FullName: "New Name", Status: "active"},
+		{Id: "spacex-retired", FullName: "Retired Pad", Status: bookings.SpaceXLaunchPadRetired},
+		{Id: "spacex-unchanged", FullName: "Unchanged Pad", Status: "active"},
+		{Id: "spacex-new", FullName: "Brand New Pad", Status: "under construction"},
+	}}
+
+	report, err := bookings.SyncLaunchPads(repo, spaceX)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Renamed) != 1 || report.Renamed[0].Id != drifted.Id {
+		t.Errorf("wrong launchpads renamed, got %+v", report.Renamed)
+	}
+	if len(report.Retired) != 1 || report.Retired[0].Id != retired.Id {
+		t.Errorf("wrong launchpads retired, got %+v", report.Retired)
+	}
+	if len(report.Unmapped) != 1 || report.Unmapped[0].Id != "spacex-new" {
+		t.Errorf("wrong unmapped launchpads, got %+v", report.Unmapped)
+	}
+	// The seeded launchpads use SpaceX ids the mock doesn't list.
+	if len(report.Unknown) != 6 {
+		t.Errorf("wrong number of unknown launchpads, got %d want %d", len(report.Unknown), 6)
+	}
+
+	launchPads, err := repo.GetLaunchPads()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, launchPad := range launchPads {
+		switch launchPad.Id {
+		case drifted.Id:
+			if launchPad.FullName != "New Name" || !launchPad.Active {
+				t.Errorf("drifted launchpad not renamed, got %+v", launchPad)
+			}
+		case retired.Id:
+			if launchPad.Active {
+				t.Errorf("retired launchpad still active, got %+v", launchPad)
+			}
+		case unchanged.Id:
+			if launchPad.FullName != "Unchanged Pad" || !launchPad.Active {
+				t.Errorf("unchanged launchpad changed, got %+v", launchPad)
+			}
+		}
+	}
+
+	// Syncing again changes nothing.
+	report, err = bookings.SyncLaunchPads(repo, spaceX)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Renamed) != 0 || len(report.Retired) != 0 {
+		t.Errorf("launchpads changed on second sync, got %+v", report)
+	}
+}
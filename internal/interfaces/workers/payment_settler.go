@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Settler settles the payments of bookings left pending because the payment gateway couldn't say whether they'd been
+// paid for, and finishes cancelling bookings that couldn't be refunded when they were cancelled.
+type Settler interface {
+	SettlePayments(now time.Time) (int, error)
+	SettleCancellations(now time.Time) (int, error)
+}
+
+// PaymentSettler periodically settles the payments of bookings that are still pending, e.g. because the gateway timed
+// out while they were being paid for, so they're confirmed or released rather than holding their seats forever. It
+// also finishes cancelling bookings whose refunds failed.
+type PaymentSettler struct {
+	Settler  Settler
+	Interval time.Duration
+}
+
+// NewPaymentSettler returns a new PaymentSettler that settles every interval.
+func NewPaymentSettler(settler Settler, interval time.Duration) PaymentSettler {
+	return PaymentSettler{Settler: settler, Interval: interval}
+}
+
+// Run settles payments every Interval until the context is cancelled.
+func (s PaymentSettler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Settle(time.Now().UTC())
+		}
+	}
+}
+
+// Settle settles the payments of the bookings still pending at now, and the cancellations still waiting for a refund.
+// Errors are logged, so the next run can try again.
+func (s PaymentSettler) Settle(now time.Time) {
+	confirmed, err := s.Settler.SettlePayments(now)
+	if err != nil {
+		log.Printf("Failed to settle payments: %v\n", err)
+	}
+
+	if confirmed > 0 {
+		log.Printf("Confirmed %d bookings after settling their payments\n", confirmed)
+	}
+
+	cancelled, err := s.Settler.SettleCancellations(now)
+	if err != nil {
+		log.Printf("Failed to settle cancellations: %v\n", err)
+	}
+
+	if cancelled > 0 {
+		log.Printf("Cancelled %d bookings after refunding them\n", cancelled)
+	}
+}
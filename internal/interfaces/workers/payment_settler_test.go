@@ -0,0 +1,40 @@
+package workers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type settlerMock struct {
+	calls         []time.Time
+	cancellations []time.Time
+	err           error
+}
+
+func (s *settlerMock) SettlePayments(now time.Time) (int, error) {
+	s.calls = append(s.calls, now)
+	return 1, s.err
+}
+
+func (s *settlerMock) SettleCancellations(now time.Time) (int, error) {
+	s.cancellations = append(s.cancellations, now)
+	return 1, s.err
+}
+
+func TestPaymentSettler_Settle(t *testing.T) {
+	now := time.Now().UTC()
+
+	for _, err := range []error{nil, errors.New("oops")} {
+		settler := &settlerMock{err: err}
+
+		NewPaymentSettler(settler, time.Minute).Settle(now)
+
+		if len(settler.calls) != 1 || !settler.calls[0].Equal(now) {
+			t.Errorf("wrong settlements, got %v", settler.calls)
+		}
+		if len(settler.cancellations) != 1 || !settler.cancellations[0].Equal(now) {
+			t.Errorf("wrong cancellations settled, got %v", settler.cancellations)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+)
+
+// Reconciler periodically re-checks upcoming bookings against SpaceX's launches. SpaceX are only checked when a
+// booking is made, so a launch scheduled afterwards would otherwise leave the passenger with a ticket for a flight that
+// can't leave.
+type Reconciler struct {
+	Booker   bookings.Booker
+	Launches bookings.LaunchConflicts
+	Interval time.Duration
+	// Notifier, when set, tells customers their booking has been disrupted.
+	Notifier bookings.Notifier
+}
+
+// NewReconciler returns a new Reconciler that reconciles every interval.
+func NewReconciler(booker bookings.Booker, launches bookings.LaunchConflicts, interval time.Duration) Reconciler {
+	return Reconciler{Booker: booker, Launches: launches, Interval: interval}
+}
+
+// Run reconciles bookings every Interval until the context is cancelled.
+func (r Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Reconcile(time.Now().UTC())
+		}
+	}
+}
+
+// Reconcile marks the bookings for flights from now onwards that now clash with a SpaceX launch as disrupted, and
+// restores disrupted bookings whose clash has cleared. Errors are logged, so the next run can try again.
+func (r Reconciler) Reconcile(now time.Time) {
+	disrupted, restored, err := bookings.ReconcileBookings(r.Booker, r.Launches, r.Notifier, now)
+	if err != nil {
+		log.Printf("Failed to reconcile bookings: %v\n", err)
+	}
+
+	if disrupted > 0 {
+		log.Printf("Marked %d bookings as disrupted by SpaceX launches\n", disrupted)
+	}
+
+	if restored > 0 {
+		log.Printf("Restored %d disrupted bookings now SpaceX aren't launching during their flight\n", restored)
+	}
+}
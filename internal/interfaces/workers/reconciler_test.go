@@ -0,0 +1,183 @@
+package workers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petherin/spacetickets/internal/domains/bookings"
+	"github.com/petherin/spacetickets/internal/infrastructure/database"
+)
+
+// launchesMock reports a SpaceX launch from the launchpad on the dates in launches, or fails with err if it's set, and
+// counts the checks made.
+type launchesMock struct {
+	launches map[string]bool
+	err      error
+	checks   int
+}
+
+func (l *launchesMock) HasLaunch(spaceXLaunchPadId string, window bookings.LaunchWindow) (bool, error) {
+	l.checks++
+	if l.err != nil {
+		return false, l.err
+	}
+	return l.launches[spaceXLaunchPadId+" "+window.Opens.Format(time.DateOnly)], nil
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	launchPad, err := repo.CreateLaunchPad(bookings.LaunchPad{FullName: "Tiny Pad", SpaceXLaunchPadId: "spacex-pad", SeatCapacity: 10, Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	today := bookings.Today(now)
+	clashing, clear, past := today.AddDate(0, 0, 7), today.AddDate(0, 0, 14), today.AddDate(0, 0, -7)
+
+	create := func(launchDate time.Time, status string) *bookings.Booking {
+		t.Helper()
+
+		var checkedInAt *time.Time
+		if status == bookings.StatusCheckedIn {
+			checkedInAt = &now
+		}
+
+		booking, err := repo.Create(bookings.Booking{
+			Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+			LaunchPadId:   launchPad.Id,
+			DestinationId: "466fc378-14eb-4ed9-8bec-d29abe54c5a9",
+			LaunchDate:    launchDate,
+			Status:        status,
+			CheckedInAt:   checkedInAt,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return booking
+	}
+
+	first := create(clashing, bookings.StatusConfirmed)
+	second := create(clashing, bookings.StatusConfirmed)
+	checkedIn := create(clashing, bookings.StatusCheckedIn)
+	pending := create(clashing, bookings.StatusPending)
+	unaffected := create(clear, bookings.StatusConfirmed)
+	departed := create(past, bookings.StatusConfirmed)
+
+	launches := &launchesMock{launches: map[string]bool{
+		"spacex-pad " + clashing.Format(time.DateOnly): true,
+		"spacex-pad " + past.Format(time.DateOnly):     true,
+	}}
+
+	NewReconciler(repo, launches, time.Hour).Reconcile(now)
+
+	for _, booking := range []*bookings.Booking{first, second, checkedIn} {
+		got, _ := repo.Get(booking.Id)
+		if got.Status != bookings.StatusDisrupted || !got.ReviewRequired || !strings.Contains(got.ReviewReason, "SpaceX") {
+			t.Errorf("clashing booking not disrupted, got %+v", got)
+		}
+	}
+
+	for _, booking := range []*bookings.Booking{pending, unaffected, departed} {
+		if got, _ := repo.Get(booking.Id); got.Status == bookings.StatusDisrupted {
+			t.Errorf("booking disrupted, got %+v", got)
+		}
+	}
+
+	// Each upcoming flight is checked once.
+	if launches.checks != 2 {
+		t.Errorf("wrong number of checks, got %d want %d", launches.checks, 2)
+	}
+
+	// Disrupted bookings are checked again, but not disrupted again.
+	launches.checks = 0
+	disrupted, restored, err := bookings.ReconcileBookings(repo, launches, nil, now)
+	if disrupted != 0 || restored != 0 || err != nil {
+		t.Errorf("wrong result, got %d, %d, %v", disrupted, restored, err)
+	}
+	if launches.checks != 2 {
+		t.Errorf("wrong number of checks, got %d want %d", launches.checks, 2)
+	}
+
+	// When SpaceX move their launch, the disrupted bookings go back to how they were, unless they've been flagged for
+	// another reason since.
+	if _, err := repo.FlagForReview(second.Id, "timetable changed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delete(launches.launches, "spacex-pad "+clashing.Format(time.DateOnly))
+
+	NewReconciler(repo, launches, time.Hour).Reconcile(now)
+
+	for booking, want := range map[*bookings.Booking]string{
+		first:     bookings.StatusConfirmed,
+		checkedIn: bookings.StatusCheckedIn,
+		second:    bookings.StatusDisrupted,
+	} {
+		got, _ := repo.Get(booking.Id)
+		if got.Status != want || got.ReviewRequired != (want == bookings.StatusDisrupted) {
+			t.Errorf("wrong booking after the clash cleared, got %+v want status %s", got, want)
+		}
+	}
+}
+
+func TestReconciler_Reconcile_SpaceXDown(t *testing.T) {
+	repo, err := database.NewMemory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	today := bookings.Today(now)
+
+	// Two launchpads, each with two flights on one day and one on another, with several passengers each.
+	for _, name := range []string{"Tiny Pad", "Other Pad"} {
+		launchPad, err := repo.CreateLaunchPad(bookings.LaunchPad{FullName: name, SpaceXLaunchPadId: "spacex-pad", SeatCapacity: 10, Timezone: "UTC"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, flight := range []struct {
+			destinationId string
+			launchDate    time.Time
+		}{
+			{"466fc378-14eb-4ed9-8bec-d29abe54c5a9", today.AddDate(0, 0, 7)},
+			{"f47eef79-675f-46da-86f9-ee598185d204", today.AddDate(0, 0, 7)},
+			{"466fc378-14eb-4ed9-8bec-d29abe54c5a9", today.AddDate(0, 0, 14)},
+		} {
+			for range 3 {
+				_, err := repo.Create(bookings.Booking{
+					Customer:      bookings.Customer{FirstName: "Ian", LastName: "Thomson", Gender: "Male"},
+					LaunchPadId:   launchPad.Id,
+					DestinationId: flight.destinationId,
+					LaunchDate:    flight.launchDate,
+					Status:        bookings.StatusConfirmed,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+	}
+
+	launches := &launchesMock{err: errors.New("SpaceX API unavailable")}
+
+	disrupted, _, err := bookings.ReconcileBookings(repo, launches, nil, now)
+	if disrupted != 0 || err == nil {
+		t.Errorf("wrong result while SpaceX are down, got %d, %v", disrupted, err)
+	}
+
+	// The outage is only tried once for each launchpad and date.
+	if launches.checks != 4 {
+		t.Errorf("wrong number of checks, got %d want %d", launches.checks, 4)
+	}
+	if got := strings.Count(err.Error(), "SpaceX API unavailable"); got != 4 {
+		t.Errorf("wrong number of errors, got %d want %d: %v", got, 4, err)
+	}
+}
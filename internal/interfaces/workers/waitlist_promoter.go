@@ -0,0 +1,53 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Promoter books waitlisted customers on flights that have seats for them.
+type Promoter interface {
+	PromoteWaitlists(now time.Time) (int, error)
+}
+
+// WaitlistPromoter periodically offers free seats to the customers waiting for them. Cancellations by customers
+// promote their flight's waitlist straight away, the promoter picks up seats freed any other way, and flights whose
+// SpaceX launch has moved.
+type WaitlistPromoter struct {
+	Promoter Promoter
+	Interval time.Duration
+}
+
+// NewWaitlistPromoter returns a new WaitlistPromoter that promotes every interval.
+func NewWaitlistPromoter(promoter Promoter, interval time.Duration) WaitlistPromoter {
+	return WaitlistPromoter{Promoter: promoter, Interval: interval}
+}
+
+// Run promotes waitlisted customers every Interval until the context is cancelled.
+func (p WaitlistPromoter) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Promote(time.Now().UTC())
+		}
+	}
+}
+
+// Promote promotes the customers waiting for flights from now onwards. Errors are logged, so the next run can try
+// again.
+func (p WaitlistPromoter) Promote(now time.Time) {
+	promoted, err := p.Promoter.PromoteWaitlists(now)
+	if err != nil {
+		log.Printf("Failed to promote waitlists: %v\n", err)
+	}
+
+	if promoted > 0 {
+		log.Printf("Promoted %d waitlisted customers\n", promoted)
+	}
+}
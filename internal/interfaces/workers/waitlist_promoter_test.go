@@ -0,0 +1,31 @@
+package workers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type promoterMock struct {
+	calls []time.Time
+	err   error
+}
+
+func (p *promoterMock) PromoteWaitlists(now time.Time) (int, error) {
+	p.calls = append(p.calls, now)
+	return 1, p.err
+}
+
+func TestWaitlistPromoter_Promote(t *testing.T) {
+	now := time.Now().UTC()
+
+	for _, err := range []error{nil, errors.New("oops")} {
+		promoter := &promoterMock{err: err}
+
+		NewWaitlistPromoter(promoter, time.Minute).Promote(now)
+
+		if len(promoter.calls) != 1 || !promoter.calls[0].Equal(now) {
+			t.Errorf("wrong promotions, got %v", promoter.calls)
+		}
+	}
+}